@@ -0,0 +1,112 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/rail44/g/sqlc/generated"
+)
+
+// idのaccountsを、持っている全てのcurrencyの残高と合わせて返します
+func (model *Model) GetAccount(ctx context.Context, id int) (Account, error) {
+	if isSystemAccount(id) {
+		return Account{}, fmt.Errorf("Not found account by id %d: %w", id, NotFoundError)
+	}
+
+	entity, err := model.queries.GetAccount(ctx, int64(id))
+	if err == sql.ErrNoRows {
+		return Account{}, fmt.Errorf("Not found account by id %d: %w", id, NotFoundError)
+	}
+	if err != nil {
+		return Account{}, fmt.Errorf("query GetAccount: %w", err)
+	}
+
+	balances, err := model.GetBalances(ctx, id)
+	if err != nil {
+		return Account{}, err
+	}
+
+	account, err := mapToAccount(entity)
+	if err != nil {
+		return Account{}, err
+	}
+
+	account.Balances = &balances
+	return account, nil
+}
+
+// idのaccountsのnameを変更します。updated_atも更新されます
+func (model *Model) Rename(ctx context.Context, id int, name string) (Account, error) {
+	if len(name) == 0 {
+		return Account{}, fmt.Errorf("name is not presented: %w", ValidationError)
+	}
+	if isSystemAccount(id) {
+		return Account{}, fmt.Errorf("Not found account by id %d: %w", id, NotFoundError)
+	}
+
+	entity, err := model.queries.UpdateAccountName(ctx, sqlc.UpdateAccountNameParams{
+		ID:   int64(id),
+		Name: sql.NullString{String: name, Valid: true},
+	})
+	if err == sql.ErrNoRows {
+		return Account{}, fmt.Errorf("Not found account by id %d: %w", id, NotFoundError)
+	}
+	if err != nil {
+		return Account{}, fmt.Errorf("query UpdateAccountName: %w", err)
+	}
+
+	return mapToAccount(entity)
+}
+
+// accountsをfilterで絞り込み、idの順に1ページ分を返します。残高は含みません
+// 続きのページがあれば、次のページを取得するためのカーソルを返します。なければ空文字列です
+func (model *Model) GetAccounts(ctx context.Context, filter AccountFilter) ([]Account, string, error) {
+	params, err := filter.params()
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := model.queries.GetAccounts(ctx, params)
+	if err != nil {
+		return nil, "", fmt.Errorf("query GetAccounts: %w", err)
+	}
+
+	var next string
+	if limit := int(params.RowLimit) - 1; len(rows) > limit {
+		rows = rows[:limit]
+		next = encodeAccountCursor(rows[limit-1].ID)
+	}
+
+	accounts := make([]Account, 0, len(rows))
+	for _, row := range rows {
+		account, err := mapToAccount(row)
+		if err != nil {
+			return nil, "", err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, next, nil
+}
+
+func mapToAccount(entity sqlc.Account) (Account, error) {
+	metadata, err := decodeMetadata(entity.Metadata)
+	if err != nil {
+		return Account{}, err
+	}
+
+	var externalId *string
+	if entity.ExternalID.Valid {
+		externalId = &entity.ExternalID.String
+	}
+
+	return Account{
+		Id:         int(entity.ID),
+		Name:       entity.Name.String,
+		Status:     AccountStatus(entity.Status),
+		ExternalId: externalId,
+		Metadata:   metadata,
+		InsertedAt: entity.InsertedAt,
+		UpdatedAt:  entity.UpdatedAt,
+	}, nil
+}
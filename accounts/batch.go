@@ -0,0 +1,154 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// 一度に行える送金の件数の上限
+const MaxBatchTransfers = 1000
+
+// 一括送金のうち受け付けられなかった送金のエラー
+// ひとつでもあれば、他の送金も行いません
+type BatchTransferError struct {
+	Items []TransferItemError
+}
+
+func (err *BatchTransferError) Error() string {
+	return fmt.Sprintf("%d of transfers were rejected", len(err.Items))
+}
+
+func (err *BatchTransferError) Unwrap() error {
+	return ValidationError
+}
+
+// senderAccountIdからitemsのそれぞれへcurrencyで送金し、itemsと同じ順序でtransactionのidを返します
+// 全ての送金をひとつのトランザクションで行うので、途中で失敗しても一部だけが送金されることはありません
+// 受け付けられない送金があればBatchTransferError、送金の制限を超えればLimitExceededError、手数料を含めた合計の金額に残高が足りなければDomainError
+func (model *Model) BatchTransfer(ctx context.Context, senderAccountId int, currency string, items []TransferItem) ([]int, error) {
+	if len(items) == 0 || len(items) > MaxBatchTransfers {
+		return nil, fmt.Errorf("transfers should have 1 to %d items: %w", MaxBatchTransfers, ValidationError)
+	}
+
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) ([]int, error) {
+		model := model.WithTx(tx)
+
+		err := model.ensureActive(ctx, senderAccountId)
+		if err != nil {
+			return nil, err
+		}
+
+		c, err := model.GetCurrency(ctx, currency)
+		if err != nil {
+			return nil, err
+		}
+
+		rejected := []TransferItemError{}
+		total := decimal.Zero
+		ids := []int{senderAccountId}
+		fees := make([]fee, len(items))
+		for i, item := range items {
+			err := model.checkTransferItem(ctx, senderAccountId, c, item)
+			if isRejection(err) {
+				rejected = append(rejected, TransferItemError{Index: i, Error: err.Error()})
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			fees[i], err = model.quoteFee(ctx, FeeOperationTransfer, senderAccountId, c, item.Amount)
+			if err != nil {
+				return nil, err
+			}
+
+			total = total.Add(item.Amount).Add(fees[i].amount)
+			ids = append(ids, item.Recipient)
+		}
+		if len(rejected) > 0 {
+			return nil, &BatchTransferError{Items: rejected}
+		}
+
+		// 手数料の定めは送金によらず同じなので、受け取るaccountはひとつです
+		for _, f := range fees {
+			if !f.applies() {
+				continue
+			}
+			err = model.ensureActive(ctx, f.account)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, f.account)
+			break
+		}
+
+		err = model.LockBalances(ctx, currency, ids...)
+		if err != nil {
+			return nil, err
+		}
+
+		amounts := make([]decimal.Decimal, 0, len(items))
+		for _, item := range items {
+			amounts = append(amounts, item.Amount)
+		}
+		err = model.checkLimits(ctx, LimitOperationTransfer, senderAccountId, c, amounts...)
+		if err != nil {
+			return nil, err
+		}
+
+		err = model.HasEnough(ctx, senderAccountId, currency, total)
+		if err != nil {
+			return nil, err
+		}
+
+		txIds := make([]int, 0, len(items))
+		for i, item := range items {
+			txId, err := model.recordTransfer(ctx, senderAccountId, item.Recipient, currency, item.Amount, item.Memo, nil, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			err = model.chargeFee(ctx, txId, senderAccountId, currency, fees[i])
+			if err != nil {
+				return nil, err
+			}
+			txIds = append(txIds, txId)
+		}
+
+		return txIds, nil
+	})
+}
+
+// 一括送金のうちのひとつの送金を、残高以外について確認します
+func (model *Model) checkTransferItem(ctx context.Context, senderAccountId int, currency Currency, item TransferItem) error {
+	if item.Recipient == senderAccountId {
+		return fmt.Errorf("recipient should be different from sender %d: %w", senderAccountId, ValidationError)
+	}
+
+	err := currency.ValidateAmount(item.Amount)
+	if err != nil {
+		return err
+	}
+
+	err = validateMemo(item.Memo, nil)
+	if err != nil {
+		return err
+	}
+
+	return model.ensureActive(ctx, item.Recipient)
+}
+
+// 一括送金の中のひとつの送金だけを受け付けない理由になるエラーであればtrue
+// それ以外のエラーは一括送金全体の失敗として扱います
+func isRejection(err error) bool {
+	for _, target := range []error{ValidationError, NotFoundError, AccountFrozenError, AccountClosedError} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
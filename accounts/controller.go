@@ -2,11 +2,15 @@ package accounts
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"database/sql"
+
+	"github.com/shopspring/decimal"
 )
 
 var ValidationError = errors.New("Validation Error")
@@ -16,24 +20,62 @@ var ServerOptions = StrictHTTPServerOptions{
 	RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 	},
-	ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-		if errors.Is(err, NotFoundError) {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
+	ResponseErrorHandlerFunc: ErrorHandler,
+}
 
-		if errors.Is(err, ValidationError) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+// Modelが返すエラーをHTTPレスポンスに変換します
+// accountsのModelを使う他のパッケージのハンドラからも使います
+func ErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	var limitErr *LimitExceededError
+	if errors.As(err, &limitErr) {
+		limit := limitErr.Limit
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(LimitExceeded{
+			Message:   limitErr.Error(),
+			Limit:     limit.Id,
+			Account:   limit.Account,
+			Operation: LimitExceededOperation(limit.Operation),
+			Currency:  limit.Currency,
+			Kind:      LimitExceededKind(limit.Kind),
+			Value:     limit.Value,
+			Usage:     limitErr.Usage,
+			Requested: limitErr.Requested,
+		})
+		return
+	}
 
-		if errors.Is(err, DomainError) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+	if errors.Is(err, NotFoundError) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
 
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-	},
+	if errors.Is(err, ValidationError) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if errors.Is(err, DomainError) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if errors.Is(err, AccountFrozenError) {
+		http.Error(w, err.Error(), http.StatusLocked)
+		return
+	}
+
+	if errors.Is(err, AccountClosedError) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	if errors.Is(err, ConflictError) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	http.Error(w, err.Error(), http.StatusInternalServerError)
 }
 
 func NewController(model *Model) http.Handler {
@@ -46,29 +88,143 @@ type Controller struct {
 	model *Model
 }
 
-// GET /balance
+// GET /
+func (controller Controller) Accounts(ctx context.Context, req AccountsRequestObject) (AccountsResponseObject, error) {
+	filter := AccountFilter{
+		NamePrefix: req.Params.NamePrefix,
+		ExternalId: req.Params.ExternalId,
+		Since:      req.Params.Since,
+		Until:      req.Params.Until,
+	}
+	metadata, err := parseMetadataParam(req.Params.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	filter.Metadata = metadata
+
+	if req.Params.Limit != nil {
+		if *req.Params.Limit < 1 {
+			return nil, fmt.Errorf("limit must be positive: %w", ValidationError)
+		}
+		filter.Limit = *req.Params.Limit
+	}
+	if req.Params.After != nil {
+		filter.After = *req.Params.After
+	}
+
+	accounts, next, err := controller.model.GetAccounts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Accounts200JSONResponse{
+		Body:    accounts,
+		Headers: Accounts200ResponseHeaders{NextCursor: next},
+	}
+	return res, nil
+}
+
+// GET /{id}
+func (controller Controller) Account(ctx context.Context, req AccountRequestObject) (AccountResponseObject, error) {
+	account, err := controller.model.GetAccount(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return Account200JSONResponse(account), nil
+}
+
+// PATCH /{id}
+func (controller Controller) UpdateAccount(ctx context.Context, req UpdateAccountRequestObject) (UpdateAccountResponseObject, error) {
+	account, err := controller.model.Rename(ctx, req.Id, req.Body.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return UpdateAccount200JSONResponse(account), nil
+}
+
+// GET /{id}/balance
 func (controller Controller) Balance(ctx context.Context, req BalanceRequestObject) (BalanceResponseObject, error) {
-	balance, err := controller.model.GetBalance(ctx, req.Id)
+	currency := currencyOrDefault(req.Params.Currency)
+	balance, available, err := controller.model.GetAvailableBalance(ctx, req.Id, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	credit, err := controller.model.GetCreditLimit(ctx, req.Id, currency)
 	if err != nil {
 		return nil, err
 	}
 
 	res := Balance200JSONResponse{
-		Balance: balance,
+		Currency:    currency,
+		Balance:     balance,
+		Available:   available,
+		CreditLimit: credit,
+		Overdrawn:   balance.IsNegative(),
 	}
 	return res, nil
 }
 
+// GET /{id}/balances
+func (controller Controller) Balances(ctx context.Context, req BalancesRequestObject) (BalancesResponseObject, error) {
+	balances, err := controller.model.GetBalances(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return Balances200JSONResponse(balances), nil
+}
+
 // GET /transactions
 func (controller Controller) Transactions(ctx context.Context, req TransactionsRequestObject) (TransactionsResponseObject, error) {
-	transactions, err := controller.model.GetTransactions(ctx, req.Id)
+	filter := TransactionFilter{
+		Memo:         req.Params.Memo,
+		Type:         (*string)(req.Params.Type),
+		Direction:    (*string)(req.Params.Direction),
+		Counterparty: req.Params.Counterparty,
+		Currency:     req.Params.Currency,
+		Since:        req.Params.Since,
+		Until:        req.Params.Until,
+	}
+	minAmount, err := parseAmountParam("min_amount", req.Params.MinAmount)
 	if err != nil {
 		return nil, err
 	}
+	filter.MinAmount = minAmount
 
-	res := Transactions200JSONResponse(
-		transactions,
-	)
+	maxAmount, err := parseAmountParam("max_amount", req.Params.MaxAmount)
+	if err != nil {
+		return nil, err
+	}
+	filter.MaxAmount = maxAmount
+
+	metadata, err := parseMetadataParam(req.Params.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	filter.Metadata = metadata
+
+	if req.Params.Limit != nil {
+		if *req.Params.Limit < 1 {
+			return nil, fmt.Errorf("limit must be positive: %w", ValidationError)
+		}
+		filter.Limit = *req.Params.Limit
+	}
+	if req.Params.After != nil {
+		filter.After = *req.Params.After
+	}
+
+	transactions, next, err := controller.model.GetTransactions(ctx, req.Id, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Transactions200JSONResponse{
+		Body:    transactions,
+		Headers: Transactions200ResponseHeaders{NextCursor: next},
+	}
 	return res, nil
 }
 
@@ -78,7 +234,7 @@ func (controller Controller) Register(ctx context.Context, req RegisterRequestOb
 		return nil, fmt.Errorf("name is not presented: %w", ValidationError)
 	}
 
-	id, err := controller.model.Register(ctx, req.Body.Name)
+	id, err := controller.model.Register(ctx, req.Body.Name, req.Body.ExternalId, metadataOrNil(req.Body.Metadata))
 	if err != nil {
 		return nil, err
 	}
@@ -91,11 +247,12 @@ func (controller Controller) Register(ctx context.Context, req RegisterRequestOb
 
 // POST /{id}/mint
 func (controller Controller) Mint(ctx context.Context, req MintRequestObject) (MintResponseObject, error) {
-	if req.Body.Amount <= 0 {
-		return nil, fmt.Errorf("amount should be positive value %d: %w", req.Body.Amount, ValidationError)
+	idempotency, err := NewIdempotency(req.Params.IdempotencyKey, "mint", req.Id, req.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	txId, err := controller.model.Mint(ctx, req.Id, req.Body.Amount)
+	txId, err := controller.model.Mint(ctx, req.Id, currencyOrDefault(req.Body.Currency), req.Body.Amount, req.Body.Memo, req.Body.Reference, metadataOrNil(req.Body.Metadata), idempotency)
 	if err != nil {
 		return nil, err
 	}
@@ -108,11 +265,12 @@ func (controller Controller) Mint(ctx context.Context, req MintRequestObject) (M
 
 // POST /{id}/spend
 func (controller Controller) Spend(ctx context.Context, req SpendRequestObject) (SpendResponseObject, error) {
-	if req.Body.Amount <= 0 {
-		return nil, fmt.Errorf("amount should be positive value %d: %w", req.Body.Amount, ValidationError)
+	idempotency, err := NewIdempotency(req.Params.IdempotencyKey, "spend", req.Id, req.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	txId, err := controller.model.Spend(ctx, req.Id, req.Body.Amount)
+	txId, err := controller.model.Spend(ctx, req.Id, currencyOrDefault(req.Body.Currency), req.Body.Amount, req.Body.Memo, req.Body.Reference, metadataOrNil(req.Body.Metadata), idempotency)
 	if err != nil {
 		return nil, err
 	}
@@ -125,15 +283,20 @@ func (controller Controller) Spend(ctx context.Context, req SpendRequestObject)
 
 // POST /{id}/transfer
 func (controller Controller) Transfer(ctx context.Context, req TransferRequestObject) (TransferResponseObject, error) {
-	if req.Body.Amount <= 0 {
-		return nil, fmt.Errorf("amount should be positive value %d: %w", req.Body.Amount, ValidationError)
+	if req.Body.Recipient <= 0 {
+		return nil, fmt.Errorf("recipient id should be positive value %d: %w", req.Body.Recipient, ValidationError)
 	}
 
-	if req.Body.Recipient <= 0 {
-		return nil, fmt.Errorf("recipient id should be positive value %d: %w", req.Body.Amount, ValidationError)
+	if req.Body.Recipient == req.Id {
+		return nil, fmt.Errorf("recipient should be different from sender %d: %w", req.Id, ValidationError)
 	}
 
-	txId, err := controller.model.Transfer(ctx, req.Id, req.Body.Recipient, req.Body.Amount)
+	idempotency, err := NewIdempotency(req.Params.IdempotencyKey, "transfer", req.Id, req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	txId, err := controller.model.Transfer(ctx, req.Id, req.Body.Recipient, currencyOrDefault(req.Body.Currency), req.Body.Amount, req.Body.Memo, req.Body.Reference, metadataOrNil(req.Body.Metadata), idempotency)
 	if err != nil {
 		return nil, err
 	}
@@ -143,3 +306,382 @@ func (controller Controller) Transfer(ctx context.Context, req TransferRequestOb
 	}
 	return res, nil
 }
+
+// POST /{id}/transfers/batch
+func (controller Controller) TransferBatch(ctx context.Context, req TransferBatchRequestObject) (TransferBatchResponseObject, error) {
+	txIds, err := controller.model.BatchTransfer(ctx, req.Id, currencyOrDefault(req.Body.Currency), req.Body.Transfers)
+	var batchErr *BatchTransferError
+	if errors.As(err, &batchErr) {
+		return TransferBatch400JSONResponse{Errors: batchErr.Items}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := TransferBatch200JSONResponse{
+		TransactionIds: txIds,
+	}
+	return res, nil
+}
+
+// POST /{id}/transactions/{txId}/reverse
+func (controller Controller) Reverse(ctx context.Context, req ReverseRequestObject) (ReverseResponseObject, error) {
+	idempotency, err := NewIdempotency(req.Params.IdempotencyKey, fmt.Sprintf("reverse %d", req.TxId), req.Id, req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	txId, err := controller.model.Reverse(ctx, req.Id, req.TxId, req.Body.Amount, idempotency)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Reverse200JSONResponse{
+		TransactionId: txId,
+	}
+	return res, nil
+}
+
+// POST /{id}/quotes
+func (controller Controller) Quote(ctx context.Context, req QuoteRequestObject) (QuoteResponseObject, error) {
+	if req.Body.Ttl <= 0 || req.Body.Ttl > MaxQuoteTtl {
+		return nil, fmt.Errorf("ttl should be between 1 and %d: %w", MaxQuoteTtl, ValidationError)
+	}
+
+	quote, err := controller.model.Quote(ctx, req.Id, req.Body.Source, req.Body.Target, req.Body.Ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return Quote200JSONResponse(quote), nil
+}
+
+// POST /{id}/convert
+func (controller Controller) Convert(ctx context.Context, req ConvertRequestObject) (ConvertResponseObject, error) {
+	recipient := req.Id
+	if req.Body.Recipient != nil {
+		recipient = *req.Body.Recipient
+	}
+
+	idempotency, err := NewIdempotency(req.Params.IdempotencyKey, "convert", req.Id, req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	txId, err := controller.model.Convert(ctx, req.Id, recipient, req.Body.Source, req.Body.Target, req.Body.Amount, req.Body.Quote, idempotency)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Convert200JSONResponse{
+		TransactionId: txId,
+	}
+	return res, nil
+}
+
+// POST /{id}/holds
+func (controller Controller) Hold(ctx context.Context, req HoldRequestObject) (HoldResponseObject, error) {
+	if req.Body.Ttl <= 0 {
+		return nil, fmt.Errorf("ttl should be positive value %d: %w", req.Body.Ttl, ValidationError)
+	}
+
+	hold, err := controller.model.Hold(ctx, req.Id, currencyOrDefault(req.Body.Currency), req.Body.Amount, req.Body.Ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return Hold200JSONResponse(hold), nil
+}
+
+// POST /{id}/holds/{holdId}/capture
+func (controller Controller) Capture(ctx context.Context, req CaptureRequestObject) (CaptureResponseObject, error) {
+	if req.Body.Recipient != nil && *req.Body.Recipient == req.Id {
+		return nil, fmt.Errorf("recipient should be different from sender %d: %w", req.Id, ValidationError)
+	}
+
+	txId, err := controller.model.Capture(ctx, req.Id, req.HoldId, req.Body.Amount, req.Body.Recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Capture200JSONResponse{
+		TransactionId: txId,
+	}
+	return res, nil
+}
+
+// POST /{id}/holds/{holdId}/release
+func (controller Controller) Release(ctx context.Context, req ReleaseRequestObject) (ReleaseResponseObject, error) {
+	hold, err := controller.model.Release(ctx, req.Id, req.HoldId)
+	if err != nil {
+		return nil, err
+	}
+
+	return Release200JSONResponse(hold), nil
+}
+
+// GET /{id}/schedules
+func (controller Controller) Schedules(ctx context.Context, req SchedulesRequestObject) (SchedulesResponseObject, error) {
+	schedules, err := controller.model.GetSchedules(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return Schedules200JSONResponse(schedules), nil
+}
+
+// POST /{id}/schedules
+func (controller Controller) CreateSchedule(ctx context.Context, req CreateScheduleRequestObject) (CreateScheduleResponseObject, error) {
+	schedule, err := controller.model.CreateSchedule(
+		ctx,
+		req.Id,
+		req.Body.Recipient,
+		currencyOrDefault(req.Body.Currency),
+		req.Body.Amount,
+		req.Body.Memo,
+		req.Body.Frequency,
+		req.Body.StartsAt,
+		req.Body.EndsAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateSchedule200JSONResponse(schedule), nil
+}
+
+// GET /{id}/schedules/{scheduleId}
+func (controller Controller) Schedule(ctx context.Context, req ScheduleRequestObject) (ScheduleResponseObject, error) {
+	schedule, err := controller.model.GetSchedule(ctx, req.Id, req.ScheduleId)
+	if err != nil {
+		return nil, err
+	}
+
+	return Schedule200JSONResponse(schedule), nil
+}
+
+// PATCH /{id}/schedules/{scheduleId}
+func (controller Controller) UpdateSchedule(ctx context.Context, req UpdateScheduleRequestObject) (UpdateScheduleResponseObject, error) {
+	schedule, err := controller.model.UpdateSchedule(ctx, req.Id, req.ScheduleId, req.Body.Amount, req.Body.Memo, req.Body.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return UpdateSchedule200JSONResponse(schedule), nil
+}
+
+// DELETE /{id}/schedules/{scheduleId}
+func (controller Controller) CancelSchedule(ctx context.Context, req CancelScheduleRequestObject) (CancelScheduleResponseObject, error) {
+	schedule, err := controller.model.CancelSchedule(ctx, req.Id, req.ScheduleId)
+	if err != nil {
+		return nil, err
+	}
+
+	return CancelSchedule200JSONResponse(schedule), nil
+}
+
+// POST /{id}/schedules/{scheduleId}/pause
+func (controller Controller) PauseSchedule(ctx context.Context, req PauseScheduleRequestObject) (PauseScheduleResponseObject, error) {
+	schedule, err := controller.model.PauseSchedule(ctx, req.Id, req.ScheduleId)
+	if err != nil {
+		return nil, err
+	}
+
+	return PauseSchedule200JSONResponse(schedule), nil
+}
+
+// POST /{id}/schedules/{scheduleId}/resume
+func (controller Controller) ResumeSchedule(ctx context.Context, req ResumeScheduleRequestObject) (ResumeScheduleResponseObject, error) {
+	schedule, err := controller.model.ResumeSchedule(ctx, req.Id, req.ScheduleId)
+	if err != nil {
+		return nil, err
+	}
+
+	return ResumeSchedule200JSONResponse(schedule), nil
+}
+
+// GET /{id}/schedules/{scheduleId}/runs
+func (controller Controller) ScheduleRuns(ctx context.Context, req ScheduleRunsRequestObject) (ScheduleRunsResponseObject, error) {
+	limit := DefaultScheduleRunsLimit
+	if req.Params.Limit != nil {
+		limit = *req.Params.Limit
+	}
+
+	runs, err := controller.model.GetScheduleRuns(ctx, req.Id, req.ScheduleId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return ScheduleRuns200JSONResponse(runs), nil
+}
+
+// GET /{id}/payment_requests
+func (controller Controller) PaymentRequests(ctx context.Context, req PaymentRequestsRequestObject) (PaymentRequestsResponseObject, error) {
+	limit := DefaultPaymentRequestsLimit
+	if req.Params.Limit != nil {
+		limit = *req.Params.Limit
+	}
+
+	requests, err := controller.model.GetPaymentRequests(ctx, req.Id, req.Params.Direction, req.Params.Status, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return PaymentRequests200JSONResponse(requests), nil
+}
+
+// POST /{id}/payment_requests
+func (controller Controller) CreatePaymentRequest(ctx context.Context, req CreatePaymentRequestRequestObject) (CreatePaymentRequestResponseObject, error) {
+	ttl := DefaultPaymentRequestTtl
+	if req.Body.Ttl != nil {
+		ttl = *req.Body.Ttl
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("ttl should be positive value %d: %w", ttl, ValidationError)
+	}
+
+	request, err := controller.model.CreatePaymentRequest(ctx, req.Id, req.Body.Payer, currencyOrDefault(req.Body.Currency), req.Body.Amount, req.Body.Memo, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreatePaymentRequest200JSONResponse(request), nil
+}
+
+// GET /{id}/payment_requests/{requestId}
+func (controller Controller) PaymentRequest(ctx context.Context, req PaymentRequestRequestObject) (PaymentRequestResponseObject, error) {
+	request, err := controller.model.GetPaymentRequest(ctx, req.Id, req.RequestId)
+	if err != nil {
+		return nil, err
+	}
+
+	return PaymentRequest200JSONResponse(request), nil
+}
+
+// POST /{id}/payment_requests/{requestId}/accept
+func (controller Controller) AcceptPaymentRequest(ctx context.Context, req AcceptPaymentRequestRequestObject) (AcceptPaymentRequestResponseObject, error) {
+	request, err := controller.model.AcceptPaymentRequest(ctx, req.Id, req.RequestId)
+	if err != nil {
+		return nil, err
+	}
+
+	return AcceptPaymentRequest200JSONResponse(request), nil
+}
+
+// POST /{id}/payment_requests/{requestId}/decline
+func (controller Controller) DeclinePaymentRequest(ctx context.Context, req DeclinePaymentRequestRequestObject) (DeclinePaymentRequestResponseObject, error) {
+	request, err := controller.model.DeclinePaymentRequest(ctx, req.Id, req.RequestId)
+	if err != nil {
+		return nil, err
+	}
+
+	return DeclinePaymentRequest200JSONResponse(request), nil
+}
+
+// GET /{id}/escrows
+func (controller Controller) Escrows(ctx context.Context, req EscrowsRequestObject) (EscrowsResponseObject, error) {
+	limit := DefaultEscrowsLimit
+	if req.Params.Limit != nil {
+		limit = *req.Params.Limit
+	}
+
+	escrows, err := controller.model.GetEscrows(ctx, req.Id, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return Escrows200JSONResponse(escrows), nil
+}
+
+// POST /{id}/escrows
+func (controller Controller) CreateEscrow(ctx context.Context, req CreateEscrowRequestObject) (CreateEscrowResponseObject, error) {
+	ttl := DefaultEscrowTtl
+	if req.Body.Ttl != nil {
+		ttl = *req.Body.Ttl
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("ttl should be positive value %d: %w", ttl, ValidationError)
+	}
+
+	escrow, err := controller.model.CreateEscrow(ctx, req.Id, req.Body.Recipient, currencyOrDefault(req.Body.Currency), req.Body.Amount, req.Body.Memo, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateEscrow200JSONResponse(escrow), nil
+}
+
+// GET /{id}/escrows/{escrowId}
+func (controller Controller) Escrow(ctx context.Context, req EscrowRequestObject) (EscrowResponseObject, error) {
+	escrow, err := controller.model.GetEscrow(ctx, req.Id, req.EscrowId)
+	if err != nil {
+		return nil, err
+	}
+
+	return Escrow200JSONResponse(escrow), nil
+}
+
+// POST /{id}/escrows/{escrowId}/confirm
+func (controller Controller) ConfirmEscrow(ctx context.Context, req ConfirmEscrowRequestObject) (ConfirmEscrowResponseObject, error) {
+	escrow, err := controller.model.ConfirmEscrow(ctx, req.Id, req.EscrowId)
+	if err != nil {
+		return nil, err
+	}
+
+	return ConfirmEscrow200JSONResponse(escrow), nil
+}
+
+// POST /{id}/escrows/{escrowId}/dispute
+func (controller Controller) DisputeEscrow(ctx context.Context, req DisputeEscrowRequestObject) (DisputeEscrowResponseObject, error) {
+	escrow, err := controller.model.DisputeEscrow(ctx, req.Id, req.EscrowId)
+	if err != nil {
+		return nil, err
+	}
+
+	return DisputeEscrow200JSONResponse(escrow), nil
+}
+
+// クエリパラメータの金額を変換します。指定されていなければnilです
+func parseAmountParam(name string, s *string) (*decimal.Decimal, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	amount, err := decimal.NewFromString(*s)
+	if err != nil {
+		return nil, fmt.Errorf("%s should be a decimal %q: %w", name, *s, ValidationError)
+	}
+	return &amount, nil
+}
+
+// 省略されたcurrencyをDefaultCurrencyにします
+func currencyOrDefault(currency *string) string {
+	if currency == nil {
+		return DefaultCurrency
+	}
+	return *currency
+}
+
+// key:valueの形式の絞り込み条件をMetadataにします
+func parseMetadataParam(params *[]string) (Metadata, error) {
+	if params == nil {
+		return nil, nil
+	}
+
+	metadata := Metadata{}
+	for _, param := range *params {
+		key, value, found := strings.Cut(param, ":")
+		if !found || len(key) == 0 {
+			return nil, fmt.Errorf("metadata should be key:value %q: %w", param, ValidationError)
+		}
+		metadata[key] = value
+	}
+	return metadata, nil
+}
+
+func metadataOrNil(metadata *Metadata) Metadata {
+	if metadata == nil {
+		return nil
+	}
+	return *metadata
+}
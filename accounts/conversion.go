@@ -0,0 +1,337 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+// quoteでレートを固定しておける秒数の上限
+const MaxQuoteTtl = 600
+
+// sourceの1単位がtargetのRate単位に相当する為替レート
+type Rate struct {
+	Id     int             `json:"id"`
+	Source string          `json:"source"`
+	Target string          `json:"target"`
+	Rate   decimal.Decimal `json:"rate"`
+	// ValidFromから、ValidUntilがあればその前まで有効です
+	ValidFrom  time.Time  `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+}
+
+// 為替レートを公開します
+// ValidFromがゼロ値であれば直ちに有効になります
+func (model *Model) PublishRate(ctx context.Context, rate Rate) (Rate, error) {
+	if rate.Source == rate.Target {
+		return Rate{}, fmt.Errorf("source and target should be different %s: %w", rate.Source, ValidationError)
+	}
+	if !rate.Rate.IsPositive() {
+		return Rate{}, fmt.Errorf("rate should be positive value %s: %w", rate.Rate, ValidationError)
+	}
+
+	validFrom := sql.NullTime{Time: rate.ValidFrom, Valid: !rate.ValidFrom.IsZero()}
+	var validUntil sql.NullTime
+	if rate.ValidUntil != nil {
+		if validFrom.Valid && !rate.ValidUntil.After(rate.ValidFrom) {
+			return Rate{}, fmt.Errorf("valid_until should be after valid_from: %w", ValidationError)
+		}
+		validUntil = sql.NullTime{Time: *rate.ValidUntil, Valid: true}
+	}
+
+	for _, code := range []string{rate.Source, rate.Target} {
+		_, err := model.GetCurrency(ctx, code)
+		if err != nil {
+			return Rate{}, err
+		}
+	}
+
+	published, err := model.queries.InsertRate(ctx, sqlc.InsertRateParams{
+		Source:     rate.Source,
+		Target:     rate.Target,
+		Rate:       rate.Rate.String(),
+		ValidFrom:  validFrom,
+		ValidUntil: validUntil,
+	})
+	if err != nil {
+		return Rate{}, fmt.Errorf("query InsertRate: %w", err)
+	}
+
+	return mapToRate(published)
+}
+
+// 公開された為替レートを返します。sourceやtargetがnilでなければ、それで絞り込みます
+func (model *Model) GetRates(ctx context.Context, source *string, target *string) ([]Rate, error) {
+	params := sqlc.GetRatesParams{}
+	if source != nil {
+		params.Source = sql.NullString{String: *source, Valid: true}
+	}
+	if target != nil {
+		params.Target = sql.NullString{String: *target, Valid: true}
+	}
+
+	rows, err := model.queries.GetRates(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("query GetRates: %w", err)
+	}
+
+	rates := make([]Rate, 0, len(rows))
+	for _, row := range rows {
+		rate, err := mapToRate(row)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// sourceからtargetへの今有効なレートを返します。なければNotFoundError
+func (model *Model) GetCurrentRate(ctx context.Context, source string, target string) (Rate, error) {
+	rate, err := model.queries.GetCurrentRate(ctx, sqlc.GetCurrentRateParams{
+		Source: source,
+		Target: target,
+	})
+	if err == sql.ErrNoRows {
+		return Rate{}, fmt.Errorf("Not found rate from %s to %s: %w", source, target, NotFoundError)
+	}
+	if err != nil {
+		return Rate{}, fmt.Errorf("query GetCurrentRate: %w", err)
+	}
+
+	return mapToRate(rate)
+}
+
+// accountIdのために、sourceからtargetへの今有効なレートをttl秒間固定します
+func (model *Model) Quote(ctx context.Context, accountId int, source string, target string, ttl int) (Quote, error) {
+	err := model.Exists(ctx, accountId)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	rate, err := model.GetCurrentRate(ctx, source, target)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	quote, err := model.queries.InsertQuote(ctx, sqlc.InsertQuoteParams{
+		Account: int64(accountId),
+		RateID:  int64(rate.Id),
+		Source:  rate.Source,
+		Target:  rate.Target,
+		Rate:    rate.Rate.String(),
+		Ttl:     int32(ttl),
+	})
+	if err != nil {
+		return Quote{}, fmt.Errorf("query InsertQuote: %w", err)
+	}
+
+	return mapToQuote(sqlc.LockQuoteRow{
+		ID:          quote.ID,
+		Account:     quote.Account,
+		RateID:      quote.RateID,
+		Source:      quote.Source,
+		Target:      quote.Target,
+		Rate:        quote.Rate,
+		ExpiresAt:   quote.ExpiresAt,
+		InsertedAt:  quote.InsertedAt,
+		Transaction: quote.Transaction,
+	})
+}
+
+// accountIdのsourceのamountを、targetに換算してrecipientAccountIdに入金します
+// quoteIdが指定されていればそのquoteで固定したレートを、なければその時点で有効なレートを使います
+// 換算した金額はtargetの桁数に切り捨てます
+func (model *Model) Convert(ctx context.Context, accountId int, recipientAccountId int, source string, target string, amount decimal.Decimal, quoteId *int, idempotency *Idempotency) (int, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (int, error) {
+		model := model.WithTx(tx)
+		return model.idempotent(ctx, accountId, idempotency, func() (int, error) {
+			return model.convert(ctx, accountId, recipientAccountId, source, target, amount, quoteId)
+		})
+	})
+}
+
+// Convertの本体です。WithTxで得たModelから呼び出してください
+func (model *Model) convert(ctx context.Context, accountId int, recipientAccountId int, source string, target string, amount decimal.Decimal, quoteId *int) (int, error) {
+	err := model.ensureActive(ctx, accountId)
+	if err != nil {
+		return 0, err
+	}
+
+	err = model.ensureActive(ctx, recipientAccountId)
+	if err != nil {
+		return 0, err
+	}
+
+	if source == target {
+		return 0, fmt.Errorf("source and target should be different %s: %w", source, ValidationError)
+	}
+
+	_, err = model.validateAmount(ctx, source, amount)
+	if err != nil {
+		return 0, err
+	}
+
+	targetCurrency, err := model.GetCurrency(ctx, target)
+	if err != nil {
+		return 0, err
+	}
+
+	var rateId int
+	var rate decimal.Decimal
+	if quoteId != nil {
+		quote, err := model.lockUnusedQuote(ctx, accountId, *quoteId)
+		if err != nil {
+			return 0, err
+		}
+		if quote.Source != source || quote.Target != target {
+			return 0, fmt.Errorf("quote %d is for %s to %s: %w", *quoteId, quote.Source, quote.Target, ValidationError)
+		}
+
+		rateId = int(quote.RateID)
+		rate, err = parseDecimal(quote.Rate)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		current, err := model.GetCurrentRate(ctx, source, target)
+		if err != nil {
+			return 0, err
+		}
+		rateId, rate = current.Id, current.Rate
+	}
+
+	converted := amount.Mul(rate).Truncate(targetCurrency.Scale)
+	if !converted.IsPositive() {
+		return 0, fmt.Errorf("%s %s is too small to convert into %s: %w", amount, source, target, DomainError)
+	}
+
+	err = model.lockBalanceKeys(ctx,
+		balanceKey{account: accountId, currency: source},
+		balanceKey{account: recipientAccountId, currency: target},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	err = model.HasEnough(ctx, accountId, source, amount)
+	if err != nil {
+		return 0, err
+	}
+
+	conversionId, err := model.queries.InsertConversion(ctx, sqlc.InsertConversionParams{
+		Amount:          amount.String(),
+		Source:          source,
+		ConvertedAmount: converted.String(),
+		Target:          target,
+		Rate:            rate.String(),
+		RateID:          int64(rateId),
+		Recipient:       int64(recipientAccountId),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("query InsertConversion: %w", err)
+	}
+
+	txId, err := model.queries.InsertTransaction(ctx, sqlc.InsertTransactionParams{
+		Account:    int64(accountId),
+		Conversion: sql.NullInt64{Int64: conversionId, Valid: true},
+		Metadata:   emptyMetadata,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("query InsertTransaction: %w", err)
+	}
+
+	err = model.post(ctx, txId,
+		posting{account: accountId, currency: source, amount: amount.Neg()},
+		posting{account: ExchangeAccountId, currency: source, amount: amount},
+		posting{account: ExchangeAccountId, currency: target, amount: converted.Neg()},
+		posting{account: recipientAccountId, currency: target, amount: converted},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if quoteId != nil {
+		err = model.queries.UpdateQuoteTransaction(ctx, sqlc.UpdateQuoteTransactionParams{
+			ID:          int64(*quoteId),
+			Transaction: sql.NullInt64{Int64: txId, Valid: true},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("query UpdateQuoteTransaction: %w", err)
+		}
+	}
+
+	return int(txId), nil
+}
+
+// まだ使われていない有効なquoteをロックして返します
+func (model *Model) lockUnusedQuote(ctx context.Context, accountId int, quoteId int) (sqlc.LockQuoteRow, error) {
+	quote, err := model.queries.LockQuote(ctx, sqlc.LockQuoteParams{
+		ID:      int64(quoteId),
+		Account: int64(accountId),
+	})
+	if err == sql.ErrNoRows {
+		return sqlc.LockQuoteRow{}, fmt.Errorf("Not found quote by id %d: %w", quoteId, NotFoundError)
+	}
+	if err != nil {
+		return sqlc.LockQuoteRow{}, fmt.Errorf("query LockQuote: %w", err)
+	}
+
+	if quote.Transaction.Valid {
+		return sqlc.LockQuoteRow{}, fmt.Errorf("quote %d was already used: %w", quoteId, ConflictError)
+	}
+	if quote.Expired {
+		return sqlc.LockQuoteRow{}, fmt.Errorf("quote %d has expired: %w", quoteId, DomainError)
+	}
+
+	return quote, nil
+}
+
+func mapToRate(entity sqlc.Rate) (Rate, error) {
+	rate, err := parseDecimal(entity.Rate)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	var validUntil *time.Time
+	if entity.ValidUntil.Valid {
+		validUntil = &entity.ValidUntil.Time
+	}
+
+	return Rate{
+		Id:         int(entity.ID),
+		Source:     entity.Source,
+		Target:     entity.Target,
+		Rate:       rate,
+		ValidFrom:  entity.ValidFrom,
+		ValidUntil: validUntil,
+	}, nil
+}
+
+func mapToQuote(entity sqlc.LockQuoteRow) (Quote, error) {
+	rate, err := parseDecimal(entity.Rate)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	var transaction *int
+	if entity.Transaction.Valid {
+		id := int(entity.Transaction.Int64)
+		transaction = &id
+	}
+
+	return Quote{
+		Id:          int(entity.ID),
+		Account:     int(entity.Account),
+		Source:      entity.Source,
+		Target:      entity.Target,
+		Rate:        rate,
+		ExpiresAt:   entity.ExpiresAt,
+		InsertedAt:  entity.InsertedAt,
+		Transaction: transaction,
+	}, nil
+}
@@ -0,0 +1,122 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+// accountのcurrencyの残高を、-CreditLimitまで負にすることを認める与信枠
+type CreditLimit struct {
+	Account     int             `json:"account"`
+	Currency    string          `json:"currency"`
+	CreditLimit decimal.Decimal `json:"credit_limit"`
+	InsertedAt  time.Time       `json:"inserted_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// idのaccountのcurrencyの与信枠を返します
+// 定めがなければ0です
+func (model *Model) GetCreditLimit(ctx context.Context, id int, currency string) (decimal.Decimal, error) {
+	limitDecimal, err := model.queries.GetCreditLimit(ctx, sqlc.GetCreditLimitParams{
+		Account:  int64(id),
+		Currency: currency,
+	})
+	if err == sql.ErrNoRows {
+		return decimal.Zero, nil
+	}
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("query GetCreditLimit: %w", err)
+	}
+
+	return parseDecimal(limitDecimal)
+}
+
+// idのaccountに定めた全てのcurrencyの与信枠を返します
+func (model *Model) GetCreditLimits(ctx context.Context, id int) ([]CreditLimit, error) {
+	err := model.Exists(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := model.queries.GetCreditLimits(ctx, int64(id))
+	if err != nil {
+		return nil, fmt.Errorf("query GetCreditLimits: %w", err)
+	}
+
+	limits := make([]CreditLimit, 0, len(rows))
+	for _, row := range rows {
+		limit, err := mapToCreditLimit(row)
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, limit)
+	}
+	return limits, nil
+}
+
+// idのaccountのcurrencyの与信枠を定めます。既に定めがあれば置き換えます
+// 既に残高が新しい与信枠を超えて負になっていても、以後の出金を認めなくなるだけで残高は変わりません
+func (model *Model) SetCreditLimit(ctx context.Context, id int, currency string, limit decimal.Decimal) (CreditLimit, error) {
+	err := model.Exists(ctx, id)
+	if err != nil {
+		return CreditLimit{}, err
+	}
+
+	c, err := model.GetCurrency(ctx, currency)
+	if err != nil {
+		return CreditLimit{}, err
+	}
+
+	if limit.IsNegative() {
+		return CreditLimit{}, fmt.Errorf("credit limit should not be negative %s: %w", limit, ValidationError)
+	}
+	if !limit.Equal(limit.Truncate(c.Scale)) {
+		return CreditLimit{}, fmt.Errorf("credit limit %s has more than %d decimal places for %s: %w", limit, c.Scale, c.Code, ValidationError)
+	}
+
+	entity, err := model.queries.UpsertCreditLimit(ctx, sqlc.UpsertCreditLimitParams{
+		Account:     int64(id),
+		Currency:    currency,
+		CreditLimit: limit.String(),
+	})
+	if err != nil {
+		return CreditLimit{}, fmt.Errorf("query UpsertCreditLimit: %w", err)
+	}
+
+	return mapToCreditLimit(entity)
+}
+
+// idのaccountのcurrencyの与信枠を削除し、以後は残高を負にできないようにします
+func (model *Model) DeleteCreditLimit(ctx context.Context, id int, currency string) error {
+	affected, err := model.queries.DeleteCreditLimit(ctx, sqlc.DeleteCreditLimitParams{
+		Account:  int64(id),
+		Currency: currency,
+	})
+	if err != nil {
+		return fmt.Errorf("query DeleteCreditLimit: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("Not found credit limit of account %d for %s: %w", id, currency, NotFoundError)
+	}
+	return nil
+}
+
+func mapToCreditLimit(entity sqlc.CreditLimit) (CreditLimit, error) {
+	limit, err := parseDecimal(entity.CreditLimit)
+	if err != nil {
+		return CreditLimit{}, err
+	}
+
+	return CreditLimit{
+		Account:     int(entity.Account),
+		Currency:    entity.Currency,
+		CreditLimit: limit,
+		InsertedAt:  entity.InsertedAt,
+		UpdatedAt:   entity.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,128 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/lib/pq"
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+// 通貨を指定しなかった場合に使う、単一の通貨しかなかった頃からの既定の通貨
+const DefaultCurrency = "G"
+
+// 通貨のコードは英大文字と数字で16文字まで
+var currencyCodePattern = regexp.MustCompile(`^[A-Z0-9]{1,16}$`)
+
+// 小数点以下の桁数の上限
+const MaxCurrencyScale = 18
+
+type Currency struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// 金額に許す小数点以下の桁数
+	Scale int32 `json:"scale"`
+}
+
+// codeの通貨が定義されていなければNotFoundError
+func (model *Model) GetCurrency(ctx context.Context, code string) (Currency, error) {
+	currency, err := model.queries.GetCurrency(ctx, code)
+	if err == sql.ErrNoRows {
+		return Currency{}, fmt.Errorf("Not found currency by code %s: %w", code, NotFoundError)
+	}
+	if err != nil {
+		return Currency{}, fmt.Errorf("query GetCurrency: %w", err)
+	}
+
+	return mapToCurrency(currency), nil
+}
+
+func (model *Model) GetCurrencies(ctx context.Context) ([]Currency, error) {
+	rows, err := model.queries.GetCurrencies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query GetCurrencies: %w", err)
+	}
+
+	currencies := make([]Currency, 0, len(rows))
+	for _, row := range rows {
+		currencies = append(currencies, mapToCurrency(row))
+	}
+	return currencies, nil
+}
+
+// 通貨を定義します。同じcodeの通貨が既にあればConflictError
+func (model *Model) CreateCurrency(ctx context.Context, currency Currency) (Currency, error) {
+	if !currencyCodePattern.MatchString(currency.Code) {
+		return Currency{}, fmt.Errorf("currency code should be up to 16 uppercase letters or digits %q: %w", currency.Code, ValidationError)
+	}
+	if len(currency.Name) == 0 {
+		return Currency{}, fmt.Errorf("currency name is not presented: %w", ValidationError)
+	}
+	if currency.Scale < 0 || currency.Scale > MaxCurrencyScale {
+		return Currency{}, fmt.Errorf("scale should be between 0 and %d: %w", MaxCurrencyScale, ValidationError)
+	}
+
+	created, err := model.queries.InsertCurrency(ctx, sqlc.InsertCurrencyParams{
+		Code:  currency.Code,
+		Name:  currency.Name,
+		Scale: currency.Scale,
+	})
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return Currency{}, fmt.Errorf("currency %s already exists: %w", currency.Code, ConflictError)
+	}
+	if err != nil {
+		return Currency{}, fmt.Errorf("query InsertCurrency: %w", err)
+	}
+
+	return mapToCurrency(created), nil
+}
+
+// 金額として受け付けられるかを確かめます
+// 正の値で、小数点以下が通貨のscale以内の桁数でなければValidationError
+func (currency Currency) ValidateAmount(amount decimal.Decimal) error {
+	if !amount.IsPositive() {
+		return fmt.Errorf("amount should be positive value %s: %w", amount, ValidationError)
+	}
+
+	if !amount.Equal(amount.Truncate(currency.Scale)) {
+		return fmt.Errorf("amount %s has more than %d decimal places for %s: %w", amount, currency.Scale, currency.Code, ValidationError)
+	}
+
+	return nil
+}
+
+// codeの通貨の金額としてamountを受け付けられるかを確かめ、その通貨を返します
+func (model *Model) validateAmount(ctx context.Context, code string, amount decimal.Decimal) (Currency, error) {
+	currency, err := model.GetCurrency(ctx, code)
+	if err != nil {
+		return Currency{}, err
+	}
+
+	err = currency.ValidateAmount(amount)
+	if err != nil {
+		return Currency{}, err
+	}
+	return currency, nil
+}
+
+func mapToCurrency(entity sqlc.Currency) Currency {
+	return Currency{
+		Code:  entity.Code,
+		Name:  entity.Name,
+		Scale: entity.Scale,
+	}
+}
+
+// DBのDECIMALを文字列で受け取ったものを変換します
+func parseDecimal(s string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("parse %q as decimal: %w", s, err)
+	}
+	return d, nil
+}
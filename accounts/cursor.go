@@ -0,0 +1,242 @@
+package accounts
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DefaultTransactionsLimit = 100
+	MaxTransactionsLimit     = 1000
+	DefaultAccountsLimit     = 100
+	MaxAccountsLimit         = 1000
+)
+
+// GetTransactionsの絞り込みとページ送りの条件
+// nilのフィールドは絞り込みに使いません
+type TransactionFilter struct {
+	// 1ページあたりの件数。0ならDefaultTransactionsLimitになります
+	Limit int
+	// 前のページで返されたカーソル。空なら先頭から取得します
+	After string
+	// mint, spend, transfer, reversal, conversion, journal, fee, escrowのいずれか
+	Type *string
+	// incoming(残高が増える)またはoutgoing(残高が減る)
+	Direction *string
+	// 取引の相手方のaccount
+	Counterparty *int
+	// 指定したcurrencyの取引のみ
+	Currency *string
+	// 自身の残高の増減の絶対値についての範囲
+	MinAmount *decimal.Decimal
+	MaxAmount *decimal.Decimal
+	// inserted_atがSince以上、Until未満のもの
+	Since *time.Time
+	Until *time.Time
+	// 指定したkeyとvalueを全て含むもの
+	Metadata Metadata
+	// 大文字と小文字を区別せず、memoにこの文字列を含むもの
+	Memo *string
+}
+
+// 並び順の最後の要素の(inserted_at, id, currency)を、利用者には中身の見えない文字列にします
+// conversionのように1つのtransactionが複数の行になるため、currencyまで含めて位置を決めます
+func encodeCursor(insertedAt time.Time, id int64, currency string) string {
+	raw := fmt.Sprintf("%d:%d:%s", insertedAt.UnixMicro(), id, currency)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, int64, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, "", fmt.Errorf("malformed cursor: %w", ValidationError)
+	}
+
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 {
+		return time.Time{}, 0, "", fmt.Errorf("malformed cursor: %w", ValidationError)
+	}
+
+	usec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, "", fmt.Errorf("malformed cursor: %w", ValidationError)
+	}
+
+	txId, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, "", fmt.Errorf("malformed cursor: %w", ValidationError)
+	}
+
+	return time.UnixMicro(usec), txId, parts[2], nil
+}
+
+// filterをGetTransactionsのパラメータに変換します
+// 続きのページがあるかを判定するため、Limitより1件多く取得するようにします
+func (filter TransactionFilter) params(accountId int) (sqlc.GetTransactionsParams, error) {
+	limit := filter.Limit
+	if limit == 0 {
+		limit = DefaultTransactionsLimit
+	}
+	if limit < 0 || limit > MaxTransactionsLimit {
+		return sqlc.GetTransactionsParams{}, fmt.Errorf("limit must be between 1 and %d: %w", MaxTransactionsLimit, ValidationError)
+	}
+
+	params := sqlc.GetTransactionsParams{
+		Account:  int64(accountId),
+		RowLimit: int32(limit + 1),
+	}
+
+	if filter.After != "" {
+		insertedAt, id, currency, err := decodeCursor(filter.After)
+		if err != nil {
+			return sqlc.GetTransactionsParams{}, err
+		}
+		params.AfterInsertedAt = sql.NullTime{Time: insertedAt, Valid: true}
+		params.AfterID = sql.NullInt64{Int64: id, Valid: true}
+		params.AfterCurrency = sql.NullString{String: currency, Valid: true}
+	}
+	if filter.Type != nil {
+		switch TransactionsParamsType(*filter.Type) {
+		case TransactionsParamsTypeMint, TransactionsParamsTypeSpend, TransactionsParamsTypeTransfer, TransactionsParamsTypeReversal,
+			TransactionsParamsTypeConversion, TransactionsParamsTypeJournal, TransactionsParamsTypeFee, TransactionsParamsTypeEscrow:
+		default:
+			return sqlc.GetTransactionsParams{}, fmt.Errorf("type should be mint, spend, transfer, reversal, conversion, journal, fee or escrow %q: %w", *filter.Type, ValidationError)
+		}
+		params.Type = sql.NullString{String: *filter.Type, Valid: true}
+	}
+	if filter.Direction != nil {
+		err := Direction(*filter.Direction).validate()
+		if err != nil {
+			return sqlc.GetTransactionsParams{}, err
+		}
+		params.Direction = sql.NullString{String: *filter.Direction, Valid: true}
+	}
+	if filter.Counterparty != nil {
+		params.Counterparty = sql.NullInt64{Int64: int64(*filter.Counterparty), Valid: true}
+	}
+	if filter.Currency != nil {
+		params.Currency = sql.NullString{String: *filter.Currency, Valid: true}
+	}
+	if filter.MinAmount != nil {
+		params.MinAmount = sql.NullString{String: filter.MinAmount.String(), Valid: true}
+	}
+	if filter.MaxAmount != nil {
+		params.MaxAmount = sql.NullString{String: filter.MaxAmount.String(), Valid: true}
+	}
+	if filter.Since != nil {
+		params.Since = sql.NullTime{Time: *filter.Since, Valid: true}
+	}
+	if filter.Until != nil {
+		params.Until = sql.NullTime{Time: *filter.Until, Valid: true}
+	}
+	if filter.Metadata != nil {
+		metadata, err := metadataParam(filter.Metadata)
+		if err != nil {
+			return sqlc.GetTransactionsParams{}, err
+		}
+		params.Metadata = metadata
+	}
+	if filter.Memo != nil {
+		params.Memo = sql.NullString{String: escapeLike(*filter.Memo), Valid: true}
+	}
+
+	return params, nil
+}
+
+// GetAccountsの絞り込みとページ送りの条件
+// nilのフィールドは絞り込みに使いません
+type AccountFilter struct {
+	// 1ページあたりの件数。0ならDefaultAccountsLimitになります
+	Limit int
+	// 前のページで返されたカーソル。空なら先頭から取得します
+	After string
+	// nameがこの文字列で始まるもの
+	NamePrefix *string
+	ExternalId *string
+	// inserted_atがSince以上、Until未満のもの
+	Since *time.Time
+	Until *time.Time
+	// 指定したkeyとvalueを全て含むもの
+	Metadata Metadata
+}
+
+// accountsはidの順に並べるので、最後の要素のidだけで位置が決まります
+func encodeAccountCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeAccountCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("malformed cursor: %w", ValidationError)
+	}
+
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed cursor: %w", ValidationError)
+	}
+
+	return id, nil
+}
+
+// filterをGetAccountsのパラメータに変換します
+// 続きのページがあるかを判定するため、Limitより1件多く取得するようにします
+func (filter AccountFilter) params() (sqlc.GetAccountsParams, error) {
+	limit := filter.Limit
+	if limit == 0 {
+		limit = DefaultAccountsLimit
+	}
+	if limit < 0 || limit > MaxAccountsLimit {
+		return sqlc.GetAccountsParams{}, fmt.Errorf("limit must be between 1 and %d: %w", MaxAccountsLimit, ValidationError)
+	}
+
+	params := sqlc.GetAccountsParams{
+		RowLimit: int32(limit + 1),
+	}
+
+	if filter.After != "" {
+		id, err := decodeAccountCursor(filter.After)
+		if err != nil {
+			return sqlc.GetAccountsParams{}, err
+		}
+		params.AfterID = sql.NullInt64{Int64: id, Valid: true}
+	}
+	if filter.NamePrefix != nil {
+		params.NamePrefix = sql.NullString{String: *filter.NamePrefix, Valid: true}
+	}
+	if filter.Since != nil {
+		params.Since = sql.NullTime{Time: *filter.Since, Valid: true}
+	}
+	if filter.Until != nil {
+		params.Until = sql.NullTime{Time: *filter.Until, Valid: true}
+	}
+	if filter.ExternalId != nil {
+		params.ExternalID = sql.NullString{String: *filter.ExternalId, Valid: true}
+	}
+	if filter.Metadata != nil {
+		metadata, err := metadataParam(filter.Metadata)
+		if err != nil {
+			return sqlc.GetAccountsParams{}, err
+		}
+		params.Metadata = metadata
+	}
+
+	return params, nil
+}
+
+// JSONBの包含で絞り込むために、metadataをJSONの文字列にします
+func metadataParam(metadata Metadata) (sql.NullString, error) {
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("encoding metadata: %w", err)
+	}
+	return sql.NullString{String: string(encoded), Valid: true}, nil
+}
@@ -0,0 +1,415 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+// escrowsの有効期間(秒)の既定値と上限
+const (
+	DefaultEscrowTtl = 7 * 24 * 60 * 60
+	MaxEscrowTtl     = 90 * 24 * 60 * 60
+)
+
+// escrowsを取得する件数の既定値と上限
+const (
+	DefaultEscrowsLimit = 100
+	MaxEscrowsLimit     = 1000
+)
+
+// RefundExpiredEscrowsが一度に払い戻す件数
+const expiredEscrowsBatchSize = 100
+
+// senderがrecipientとの取引のため、currencyのamountをシステム勘定のescrowに預けます
+// ttl秒の間に双方の確認が揃わなければ、RefundExpiredEscrowsでsenderに払い戻します
+func (model *Model) CreateEscrow(ctx context.Context, sender int, recipient int, currency string, amount decimal.Decimal, memo *string, ttl int) (Escrow, error) {
+	if recipient == sender {
+		return Escrow{}, fmt.Errorf("recipient should be different from sender %d: %w", sender, ValidationError)
+	}
+
+	if ttl < 1 || ttl > MaxEscrowTtl {
+		return Escrow{}, fmt.Errorf("ttl should be between 1 and %d: %w", MaxEscrowTtl, ValidationError)
+	}
+
+	columns, err := newMemoColumns(memo, nil)
+	if err != nil {
+		return Escrow{}, err
+	}
+
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (Escrow, error) {
+		model := model.WithTx(tx)
+
+		for _, id := range []int{sender, recipient} {
+			err := model.ensureActive(ctx, id)
+			if err != nil {
+				return Escrow{}, err
+			}
+		}
+
+		c, err := model.validateAmount(ctx, currency, amount)
+		if err != nil {
+			return Escrow{}, err
+		}
+
+		err = model.LockBalances(ctx, currency, sender, recipient)
+		if err != nil {
+			return Escrow{}, err
+		}
+
+		// escrowへの預け入れも、transferの制限の対象になります
+		err = model.checkLimits(ctx, LimitOperationTransfer, sender, c, amount)
+		if err != nil {
+			return Escrow{}, err
+		}
+
+		err = model.HasEnough(ctx, sender, currency, amount)
+		if err != nil {
+			return Escrow{}, err
+		}
+
+		entity, err := model.queries.InsertEscrow(ctx, sqlc.InsertEscrowParams{
+			Sender:    int64(sender),
+			Recipient: int64(recipient),
+			Currency:  currency,
+			Amount:    amount.String(),
+			Memo:      columns.memo,
+			Ttl:       int32(ttl),
+		})
+		if err != nil {
+			return Escrow{}, fmt.Errorf("query InsertEscrow: %w", err)
+		}
+
+		err = model.moveEscrow(ctx, entity, sqlc.EscrowActionDeposit)
+		if err != nil {
+			return Escrow{}, err
+		}
+
+		return mapToEscrow(entity)
+	})
+}
+
+// accountIdがsenderかrecipientであるescrowを返します
+func (model *Model) GetEscrow(ctx context.Context, accountId int, escrowId int) (Escrow, error) {
+	entity, err := model.queries.GetEscrow(ctx, sqlc.GetEscrowParams{
+		ID:      int64(escrowId),
+		Account: int64(accountId),
+	})
+	if err == sql.ErrNoRows {
+		return Escrow{}, fmt.Errorf("Not found escrow by id %d: %w", escrowId, NotFoundError)
+	}
+	if err != nil {
+		return Escrow{}, fmt.Errorf("query GetEscrow: %w", err)
+	}
+
+	return mapToEscrow(entity)
+}
+
+// accountIdがsenderかrecipientであるescrowsを新しいものから返します
+func (model *Model) GetEscrows(ctx context.Context, accountId int, limit int) ([]Escrow, error) {
+	if limit < 1 || limit > MaxEscrowsLimit {
+		return nil, fmt.Errorf("limit should be between 1 and %d: %w", MaxEscrowsLimit, ValidationError)
+	}
+
+	err := model.Exists(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := model.queries.GetEscrows(ctx, sqlc.GetEscrowsParams{
+		Account:  int64(accountId),
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query GetEscrows: %w", err)
+	}
+
+	escrows := make([]Escrow, 0, len(rows))
+	for _, row := range rows {
+		escrow, err := mapToEscrow(row)
+		if err != nil {
+			return nil, err
+		}
+		escrows = append(escrows, escrow)
+	}
+	return escrows, nil
+}
+
+// 当事者のaccountIdが取引の済んだことを確認します
+// senderとrecipientの確認が揃うと、同じトランザクションでrecipientに払い出します
+func (model *Model) ConfirmEscrow(ctx context.Context, accountId int, escrowId int) (Escrow, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (Escrow, error) {
+		model := model.WithTx(tx)
+
+		escrow, err := model.lockFundedEscrow(ctx, accountId, escrowId)
+		if err != nil {
+			return Escrow{}, err
+		}
+
+		escrow, err = model.queries.ConfirmEscrow(ctx, sqlc.ConfirmEscrowParams{
+			ID:      escrow.ID,
+			Account: int64(accountId),
+		})
+		if err != nil {
+			return Escrow{}, fmt.Errorf("query ConfirmEscrow: %w", err)
+		}
+
+		if escrow.SenderConfirmedAt.Valid && escrow.RecipientConfirmedAt.Valid {
+			return model.settleEscrow(ctx, escrow, sqlc.EscrowActionRelease)
+		}
+		return mapToEscrow(escrow)
+	})
+}
+
+// 当事者のaccountIdが異議を申し立てます
+// 管理者がResolveEscrowで解決するまで、払い出しも期限切れによる払い戻しも行いません
+func (model *Model) DisputeEscrow(ctx context.Context, accountId int, escrowId int) (Escrow, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (Escrow, error) {
+		model := model.WithTx(tx)
+
+		escrow, err := model.lockFundedEscrow(ctx, accountId, escrowId)
+		if err != nil {
+			return Escrow{}, err
+		}
+
+		return model.updateEscrowStatus(ctx, escrow.ID, sqlc.EscrowStatusDisputed)
+	})
+}
+
+// 異議が申し立てられたescrowを、actionがreleaseならrecipientへ払い出し、refundならsenderへ払い戻して解決します
+func (model *Model) ResolveEscrow(ctx context.Context, escrowId int, action EscrowMovementAction) (Escrow, error) {
+	if action != Release && action != Refund {
+		return Escrow{}, fmt.Errorf("resolution should be release or refund %q: %w", action, ValidationError)
+	}
+
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (Escrow, error) {
+		model := model.WithTx(tx)
+
+		row, err := model.queries.LockEscrow(ctx, int64(escrowId))
+		if err == sql.ErrNoRows {
+			return Escrow{}, fmt.Errorf("Not found escrow by id %d: %w", escrowId, NotFoundError)
+		}
+		if err != nil {
+			return Escrow{}, fmt.Errorf("query LockEscrow: %w", err)
+		}
+
+		if row.Status != sqlc.EscrowStatusDisputed {
+			return Escrow{}, fmt.Errorf("escrow %d is %s and has no dispute to resolve: %w", escrowId, row.Status, ConflictError)
+		}
+
+		return model.settleEscrow(ctx, lockedEscrow(row), sqlc.EscrowAction(action))
+	})
+}
+
+// expires_atを過ぎても双方の確認が揃わなかったescrowsをsenderに払い戻し、払い戻した件数を返します
+// senderが凍結されているなど払い戻せないものは飛ばしてその理由を返し、次の呼び出しで改めて試みます
+// 飛ばしたものがいくら溜まっても後続のescrowsを払い戻せるよう、expires_atとidの順に全ての期限切れのものを辿ります
+func (model *Model) RefundExpiredEscrows(ctx context.Context) (int, []error, error) {
+	params := sqlc.GetExpiredEscrowsParams{RowLimit: expiredEscrowsBatchSize}
+	count := 0
+	var skipped []error
+	for {
+		rows, err := model.queries.GetExpiredEscrows(ctx, params)
+		if err != nil {
+			return count, skipped, fmt.Errorf("query GetExpiredEscrows: %w", err)
+		}
+
+		for _, row := range rows {
+			refunded, err := model.refundExpiredEscrow(ctx, row.ID)
+			if isRejection(err) {
+				skipped = append(skipped, fmt.Errorf("escrow %d: %w", row.ID, err))
+				continue
+			}
+			if err != nil {
+				return count, skipped, err
+			}
+			if refunded {
+				count++
+			}
+		}
+
+		if len(rows) < expiredEscrowsBatchSize {
+			return count, skipped, nil
+		}
+		last := rows[len(rows)-1]
+		params.AfterExpiresAt = sql.NullTime{Time: last.ExpiresAt, Valid: true}
+		params.AfterID = sql.NullInt64{Int64: last.ID, Valid: true}
+	}
+}
+
+// 取得してからロックするまでに確認が揃ったり異議が申し立てられていれば、払い戻さずにfalseを返します
+func (model *Model) refundExpiredEscrow(ctx context.Context, escrowId int64) (bool, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (bool, error) {
+		model := model.WithTx(tx)
+
+		row, err := model.queries.LockEscrow(ctx, escrowId)
+		if err != nil {
+			return false, fmt.Errorf("query LockEscrow: %w", err)
+		}
+		if row.Status != sqlc.EscrowStatusFunded || !row.Expired {
+			return false, nil
+		}
+
+		_, err = model.settleEscrow(ctx, lockedEscrow(row), sqlc.EscrowActionRefund)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+}
+
+// accountIdが当事者である、確認や異議を受け付けられるescrowをロックして返します
+func (model *Model) lockFundedEscrow(ctx context.Context, accountId int, escrowId int) (sqlc.Escrow, error) {
+	row, err := model.queries.LockEscrow(ctx, int64(escrowId))
+	if err == sql.ErrNoRows || (err == nil && int(row.Sender) != accountId && int(row.Recipient) != accountId) {
+		return sqlc.Escrow{}, fmt.Errorf("Not found escrow by id %d: %w", escrowId, NotFoundError)
+	}
+	if err != nil {
+		return sqlc.Escrow{}, fmt.Errorf("query LockEscrow: %w", err)
+	}
+
+	if row.Status != sqlc.EscrowStatusFunded {
+		return sqlc.Escrow{}, fmt.Errorf("escrow %d was already %s: %w", escrowId, row.Status, ConflictError)
+	}
+	if row.Expired {
+		return sqlc.Escrow{}, fmt.Errorf("escrow %d has expired: %w", escrowId, DomainError)
+	}
+
+	return lockedEscrow(row), nil
+}
+
+// escrowの資金をactionに従って払い出すか払い戻し、escrowを終えた状態にします
+func (model *Model) settleEscrow(ctx context.Context, escrow sqlc.Escrow, action sqlc.EscrowAction) (Escrow, error) {
+	payee, status := escrow.Sender, sqlc.EscrowStatusRefunded
+	if action == sqlc.EscrowActionRelease {
+		payee, status = escrow.Recipient, sqlc.EscrowStatusReleased
+	}
+
+	err := model.ensureActive(ctx, int(payee))
+	if err != nil {
+		return Escrow{}, err
+	}
+
+	err = model.LockBalances(ctx, escrow.Currency, int(escrow.Sender), int(escrow.Recipient))
+	if err != nil {
+		return Escrow{}, err
+	}
+
+	err = model.moveEscrow(ctx, escrow, action)
+	if err != nil {
+		return Escrow{}, err
+	}
+
+	return model.updateEscrowStatus(ctx, escrow.ID, status)
+}
+
+// escrowの資金の動きを、残高が動いた当事者のtransactionとして記録します
+// 残高が動かない側の当事者の取引履歴には、escrowsの当事者であることから増減0の行として現れます
+// 当事者の残高の行はロックしておいてください
+func (model *Model) moveEscrow(ctx context.Context, escrow sqlc.Escrow, action sqlc.EscrowAction) error {
+	amount, err := parseDecimal(escrow.Amount)
+	if err != nil {
+		return err
+	}
+
+	movementId, err := model.queries.InsertEscrowMovement(ctx, sqlc.InsertEscrowMovementParams{
+		Escrow: escrow.ID,
+		Action: action,
+	})
+	if err != nil {
+		return fmt.Errorf("query InsertEscrowMovement: %w", err)
+	}
+
+	// depositではsenderからescrowへ、releaseとrefundではescrowからrecipientやsenderへ動かします
+	sender, recipient := int(escrow.Sender), int(escrow.Recipient)
+	from, to := sender, EscrowAccountId
+	switch action {
+	case sqlc.EscrowActionRelease:
+		from, to = EscrowAccountId, recipient
+	case sqlc.EscrowActionRefund:
+		from, to = EscrowAccountId, sender
+	}
+
+	account := from
+	if isSystemAccount(account) {
+		account = to
+	}
+
+	txId, err := model.queries.InsertTransaction(ctx, sqlc.InsertTransactionParams{
+		Account:        int64(account),
+		EscrowMovement: sql.NullInt64{Int64: movementId, Valid: true},
+		Metadata:       emptyMetadata,
+	})
+	if err != nil {
+		return fmt.Errorf("query InsertTransaction: %w", err)
+	}
+
+	return model.post(ctx, txId,
+		posting{account: from, currency: escrow.Currency, amount: amount.Neg()},
+		posting{account: to, currency: escrow.Currency, amount: amount},
+	)
+}
+
+func (model *Model) updateEscrowStatus(ctx context.Context, escrowId int64, status sqlc.EscrowStatus) (Escrow, error) {
+	entity, err := model.queries.UpdateEscrowStatus(ctx, sqlc.UpdateEscrowStatusParams{
+		ID:     escrowId,
+		Status: status,
+	})
+	if err != nil {
+		return Escrow{}, fmt.Errorf("query UpdateEscrowStatus: %w", err)
+	}
+	return mapToEscrow(entity)
+}
+
+// LockEscrowで取得した行から、期限切れかどうかを除いたもの
+func lockedEscrow(row sqlc.LockEscrowRow) sqlc.Escrow {
+	return sqlc.Escrow{
+		ID:                   row.ID,
+		Sender:               row.Sender,
+		Recipient:            row.Recipient,
+		Currency:             row.Currency,
+		Amount:               row.Amount,
+		Memo:                 row.Memo,
+		Status:               row.Status,
+		SenderConfirmedAt:    row.SenderConfirmedAt,
+		RecipientConfirmedAt: row.RecipientConfirmedAt,
+		ExpiresAt:            row.ExpiresAt,
+		InsertedAt:           row.InsertedAt,
+		UpdatedAt:            row.UpdatedAt,
+	}
+}
+
+func mapToEscrow(entity sqlc.Escrow) (Escrow, error) {
+	amount, err := parseDecimal(entity.Amount)
+	if err != nil {
+		return Escrow{}, err
+	}
+
+	var memo *string
+	if entity.Memo.Valid {
+		memo = &entity.Memo.String
+	}
+
+	escrow := Escrow{
+		Id:         int(entity.ID),
+		Sender:     int(entity.Sender),
+		Recipient:  int(entity.Recipient),
+		Amount:     amount,
+		Currency:   entity.Currency,
+		Memo:       memo,
+		Status:     EscrowStatus(entity.Status),
+		ExpiresAt:  entity.ExpiresAt,
+		InsertedAt: entity.InsertedAt,
+		UpdatedAt:  entity.UpdatedAt,
+	}
+	if entity.SenderConfirmedAt.Valid {
+		escrow.SenderConfirmedAt = &entity.SenderConfirmedAt.Time
+	}
+	if entity.RecipientConfirmedAt.Valid {
+		escrow.RecipientConfirmedAt = &entity.RecipientConfirmedAt.Time
+	}
+	return escrow, nil
+}
@@ -0,0 +1,273 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+// 手数料を課す取引の種類
+type FeeOperation string
+
+const (
+	FeeOperationTransfer FeeOperation = "transfer"
+	FeeOperationSpend    FeeOperation = "spend"
+)
+
+// operationとcurrencyの取引に課す手数料の定め
+// 手数料はFlat + 金額 * Percentage / 100を、MinとMaxがあればその範囲に収め、通貨の桁数に切り捨てたものです
+// 手数料はAccountに入金されます
+type FeeSchedule struct {
+	Id         int              `json:"id"`
+	Operation  FeeOperation     `json:"operation"`
+	Currency   string           `json:"currency"`
+	Flat       decimal.Decimal  `json:"flat"`
+	Percentage decimal.Decimal  `json:"percentage"`
+	Min        *decimal.Decimal `json:"min,omitempty"`
+	Max        *decimal.Decimal `json:"max,omitempty"`
+	Account    int              `json:"account"`
+	InsertedAt time.Time        `json:"inserted_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// amountの取引に課す手数料を、scaleの桁数に切り捨てて返します
+// 利用者に不利にならないよう、端数は切り捨てます
+func (schedule FeeSchedule) Calculate(amount decimal.Decimal, scale int32) decimal.Decimal {
+	fee := schedule.Flat.Add(amount.Mul(schedule.Percentage).Div(decimal.NewFromInt(100)))
+	if schedule.Min != nil && fee.LessThan(*schedule.Min) {
+		fee = *schedule.Min
+	}
+	if schedule.Max != nil && fee.GreaterThan(*schedule.Max) {
+		fee = *schedule.Max
+	}
+	return fee.Truncate(scale)
+}
+
+func (operation FeeOperation) validate() error {
+	switch operation {
+	case FeeOperationTransfer, FeeOperationSpend:
+		return nil
+	}
+	return fmt.Errorf("operation should be transfer or spend %q: %w", operation, ValidationError)
+}
+
+func (model *Model) GetFeeSchedules(ctx context.Context) ([]FeeSchedule, error) {
+	rows, err := model.queries.GetFeeSchedules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query GetFeeSchedules: %w", err)
+	}
+
+	schedules := make([]FeeSchedule, 0, len(rows))
+	for _, row := range rows {
+		schedule, err := mapToFeeSchedule(row)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+// schedule.Operationとschedule.Currencyの手数料を定めます。既に定めがあれば置き換えます
+// 手数料を受け取るaccountは取引できる状態でなければなりません
+func (model *Model) SetFeeSchedule(ctx context.Context, schedule FeeSchedule) (FeeSchedule, error) {
+	err := schedule.Operation.validate()
+	if err != nil {
+		return FeeSchedule{}, err
+	}
+
+	currency, err := model.GetCurrency(ctx, schedule.Currency)
+	if err != nil {
+		return FeeSchedule{}, err
+	}
+
+	if schedule.Percentage.IsNegative() || schedule.Percentage.GreaterThan(decimal.NewFromInt(100)) {
+		return FeeSchedule{}, fmt.Errorf("percentage should be between 0 and 100 %s: %w", schedule.Percentage, ValidationError)
+	}
+
+	// flat, min, maxはいずれも手数料の金額として扱います
+	for _, bound := range []*decimal.Decimal{&schedule.Flat, schedule.Min, schedule.Max} {
+		if bound == nil {
+			continue
+		}
+		if bound.IsNegative() {
+			return FeeSchedule{}, fmt.Errorf("fee should not be negative %s: %w", bound, ValidationError)
+		}
+		if !bound.Equal(bound.Truncate(currency.Scale)) {
+			return FeeSchedule{}, fmt.Errorf("fee %s has more than %d decimal places for %s: %w", bound, currency.Scale, currency.Code, ValidationError)
+		}
+	}
+	if schedule.Min != nil && schedule.Max != nil && schedule.Min.GreaterThan(*schedule.Max) {
+		return FeeSchedule{}, fmt.Errorf("min %s should not be greater than max %s: %w", schedule.Min, schedule.Max, ValidationError)
+	}
+
+	var min, max sql.NullString
+	if schedule.Min != nil {
+		min = sql.NullString{String: schedule.Min.String(), Valid: true}
+	}
+	if schedule.Max != nil {
+		max = sql.NullString{String: schedule.Max.String(), Valid: true}
+	}
+
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (FeeSchedule, error) {
+		model := model.WithTx(tx)
+
+		err := model.ensureActive(ctx, schedule.Account)
+		if err != nil {
+			return FeeSchedule{}, err
+		}
+
+		entity, err := model.queries.UpsertFeeSchedule(ctx, sqlc.UpsertFeeScheduleParams{
+			Operation:  sqlc.FeeOperation(schedule.Operation),
+			Currency:   schedule.Currency,
+			Flat:       schedule.Flat.String(),
+			Percentage: schedule.Percentage.String(),
+			Min:        min,
+			Max:        max,
+			Account:    int64(schedule.Account),
+		})
+		if err != nil {
+			return FeeSchedule{}, fmt.Errorf("query UpsertFeeSchedule: %w", err)
+		}
+
+		return mapToFeeSchedule(entity)
+	})
+}
+
+// operationとcurrencyの手数料の定めを削除し、以後は手数料を課さないようにします
+// 既に課した手数料の記録は残ります
+func (model *Model) DeleteFeeSchedule(ctx context.Context, operation FeeOperation, currency string) error {
+	err := operation.validate()
+	if err != nil {
+		return err
+	}
+
+	affected, err := model.queries.DeleteFeeSchedule(ctx, sqlc.DeleteFeeScheduleParams{
+		Operation: sqlc.FeeOperation(operation),
+		Currency:  currency,
+	})
+	if err != nil {
+		return fmt.Errorf("query DeleteFeeSchedule: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("Not found fee schedule for %s in %s: %w", operation, currency, NotFoundError)
+	}
+	return nil
+}
+
+// 取引に課すことになった手数料
+// 手数料の定めがない場合や、支払うaccountが受け取るaccountと同じ場合はゼロ値です
+type fee struct {
+	schedule int64
+	account  int
+	amount   decimal.Decimal
+}
+
+func (f fee) applies() bool {
+	return f.amount.IsPositive()
+}
+
+// payerがoperationでcurrencyのamountを動かす場合の手数料を求めます
+func (model *Model) quoteFee(ctx context.Context, operation FeeOperation, payer int, currency Currency, amount decimal.Decimal) (fee, error) {
+	entity, err := model.queries.GetFeeSchedule(ctx, sqlc.GetFeeScheduleParams{
+		Operation: sqlc.FeeOperation(operation),
+		Currency:  currency.Code,
+	})
+	if err == sql.ErrNoRows {
+		return fee{}, nil
+	}
+	if err != nil {
+		return fee{}, fmt.Errorf("query GetFeeSchedule: %w", err)
+	}
+
+	schedule, err := mapToFeeSchedule(entity)
+	if err != nil {
+		return fee{}, err
+	}
+	if schedule.Account == payer {
+		return fee{}, nil
+	}
+
+	return fee{
+		schedule: entity.ID,
+		account:  schedule.Account,
+		amount:   schedule.Calculate(amount, currency.Scale),
+	}, nil
+}
+
+// originalの取引に対する手数料を、originalとは別のtransactionとして記録し、payerから手数料のaccountへ残高を動かします
+// 手数料のaccountの残高の行もロックしておいてください
+func (model *Model) chargeFee(ctx context.Context, original int, payer int, currency string, f fee) error {
+	if !f.applies() {
+		return nil
+	}
+
+	feeId, err := model.queries.InsertFee(ctx, sqlc.InsertFeeParams{
+		Amount:    f.amount.String(),
+		Currency:  currency,
+		Original:  int64(original),
+		Recipient: int64(f.account),
+		Schedule:  sql.NullInt64{Int64: f.schedule, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("query InsertFee: %w", err)
+	}
+
+	txId, err := model.queries.InsertTransaction(ctx, sqlc.InsertTransactionParams{
+		Account:  int64(payer),
+		Fee:      sql.NullInt64{Int64: feeId, Valid: true},
+		Metadata: emptyMetadata,
+	})
+	if err != nil {
+		return fmt.Errorf("query InsertTransaction: %w", err)
+	}
+
+	return model.post(ctx, txId,
+		posting{account: payer, currency: currency, amount: f.amount.Neg()},
+		posting{account: f.account, currency: currency, amount: f.amount},
+	)
+}
+
+func mapToFeeSchedule(entity sqlc.FeeSchedule) (FeeSchedule, error) {
+	flat, err := parseDecimal(entity.Flat)
+	if err != nil {
+		return FeeSchedule{}, err
+	}
+
+	percentage, err := parseDecimal(entity.Percentage)
+	if err != nil {
+		return FeeSchedule{}, err
+	}
+
+	schedule := FeeSchedule{
+		Id:         int(entity.ID),
+		Operation:  FeeOperation(entity.Operation),
+		Currency:   entity.Currency,
+		Flat:       flat,
+		Percentage: percentage,
+		Account:    int(entity.Account),
+		InsertedAt: entity.InsertedAt,
+		UpdatedAt:  entity.UpdatedAt,
+	}
+
+	if entity.Min.Valid {
+		min, err := parseDecimal(entity.Min.String)
+		if err != nil {
+			return FeeSchedule{}, err
+		}
+		schedule.Min = &min
+	}
+	if entity.Max.Valid {
+		max, err := parseDecimal(entity.Max.String)
+		if err != nil {
+			return FeeSchedule{}, err
+		}
+		schedule.Max = &max
+	}
+
+	return schedule, nil
+}
@@ -0,0 +1,240 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+// currencyの有効なholdsの合計を返します
+// 期限切れのholdsは含みません
+func (model *Model) GetHeldAmount(ctx context.Context, id int, currency string) (decimal.Decimal, error) {
+	heldDecimal, err := model.queries.GetHeldAmount(ctx, sqlc.GetHeldAmountParams{
+		Account:  int64(id),
+		Currency: currency,
+	})
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("query GetHeldAmount: %w", err)
+	}
+
+	return parseDecimal(heldDecimal)
+}
+
+// currencyの残高と、そこから有効なholdsを差し引いた利用可能な残高を返します
+func (model *Model) GetAvailableBalance(ctx context.Context, id int, currency string) (decimal.Decimal, decimal.Decimal, error) {
+	balance, err := model.GetBalance(ctx, id, currency)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, err
+	}
+
+	held, err := model.GetHeldAmount(ctx, id, currency)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, err
+	}
+
+	return balance, balance.Sub(held), nil
+}
+
+// accountIdのcurrencyの利用可能な残高をamountだけttl秒間仮押さえします
+func (model *Model) Hold(ctx context.Context, accountId int, currency string, amount decimal.Decimal, ttl int) (Hold, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (Hold, error) {
+		model := model.WithTx(tx)
+
+		err := model.ensureActive(ctx, accountId)
+		if err != nil {
+			return Hold{}, err
+		}
+
+		_, err = model.validateAmount(ctx, currency, amount)
+		if err != nil {
+			return Hold{}, err
+		}
+
+		err = model.HasEnough(ctx, accountId, currency, amount)
+		if err != nil {
+			return Hold{}, err
+		}
+
+		hold, err := model.queries.InsertHold(ctx, sqlc.InsertHoldParams{
+			Account:  int64(accountId),
+			Amount:   amount.String(),
+			Currency: currency,
+			Ttl:      int32(ttl),
+		})
+		if err != nil {
+			return Hold{}, fmt.Errorf("query InsertHold: %w", err)
+		}
+
+		return mapToHold(sqlc.LockHoldRow{
+			ID:          hold.ID,
+			Account:     hold.Account,
+			Amount:      hold.Amount,
+			Status:      hold.Status,
+			ExpiresAt:   hold.ExpiresAt,
+			InsertedAt:  hold.InsertedAt,
+			UpdatedAt:   hold.UpdatedAt,
+			Transaction: hold.Transaction,
+			Currency:    hold.Currency,
+		})
+	})
+}
+
+// holdIdの仮押さえをSpend、recipientが指定されていればTransferとして確定させます
+// amountがnilなら仮押さえした全額を、指定されていればその金額だけを動かし、残りは解放されます
+func (model *Model) Capture(ctx context.Context, accountId int, holdId int, amount *decimal.Decimal, recipient *int) (int, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (int, error) {
+		model := model.WithTx(tx)
+
+		hold, err := model.lockActiveHold(ctx, accountId, holdId)
+		if err != nil {
+			return 0, err
+		}
+
+		// holdのcurrencyが分かったところで、Transferと同じ順序でstatusと残高の行をロックしておきます
+		ids := []int{accountId}
+		if recipient != nil {
+			ids = append(ids, *recipient)
+		}
+		for _, id := range ids {
+			err = model.ensureActive(ctx, id)
+			if err != nil {
+				return 0, err
+			}
+		}
+		err = model.LockBalances(ctx, hold.Currency, ids...)
+		if err != nil {
+			return 0, err
+		}
+
+		if hold.Expired {
+			return 0, fmt.Errorf("hold %d has expired: %w", holdId, DomainError)
+		}
+
+		held, err := parseDecimal(hold.Amount)
+		if err != nil {
+			return 0, err
+		}
+
+		captured := held
+		if amount != nil {
+			captured = *amount
+		}
+		if captured.GreaterThan(held) {
+			return 0, fmt.Errorf("%s %s was requested, but only %s was held: %w", captured, hold.Currency, held, DomainError)
+		}
+
+		// 先に仮押さえを外してから、HasEnoughを含む通常のSpendやTransferを行います
+		err = model.updateHoldStatus(ctx, holdId, sqlc.HoldStatusCaptured, 0)
+		if err != nil {
+			return 0, err
+		}
+
+		var txId int
+		if recipient != nil {
+			txId, err = model.transfer(ctx, accountId, *recipient, hold.Currency, captured, nil, nil, nil)
+		} else {
+			txId, err = model.spend(ctx, accountId, hold.Currency, captured, nil, nil, nil)
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		err = model.updateHoldStatus(ctx, holdId, sqlc.HoldStatusCaptured, txId)
+		if err != nil {
+			return 0, err
+		}
+
+		return txId, nil
+	})
+}
+
+// holdIdの仮押さえを解放します
+func (model *Model) Release(ctx context.Context, accountId int, holdId int) (Hold, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (Hold, error) {
+		model := model.WithTx(tx)
+
+		hold, err := model.lockActiveHold(ctx, accountId, holdId)
+		if err != nil {
+			return Hold{}, err
+		}
+
+		err = model.updateHoldStatus(ctx, holdId, sqlc.HoldStatusReleased, 0)
+		if err != nil {
+			return Hold{}, err
+		}
+
+		hold.Status = sqlc.HoldStatusReleased
+		hold.Expired = false
+		return mapToHold(hold)
+	})
+}
+
+// 確定も解放もされていないholdをロックして返します
+func (model *Model) lockActiveHold(ctx context.Context, accountId int, holdId int) (sqlc.LockHoldRow, error) {
+	err := model.Exists(ctx, accountId)
+	if err != nil {
+		return sqlc.LockHoldRow{}, err
+	}
+
+	hold, err := model.queries.LockHold(ctx, sqlc.LockHoldParams{
+		ID:      int64(holdId),
+		Account: int64(accountId),
+	})
+	if err == sql.ErrNoRows {
+		return sqlc.LockHoldRow{}, fmt.Errorf("Not found hold by id %d: %w", holdId, NotFoundError)
+	}
+	if err != nil {
+		return sqlc.LockHoldRow{}, fmt.Errorf("query LockHold: %w", err)
+	}
+
+	if hold.Status != sqlc.HoldStatusActive {
+		return sqlc.LockHoldRow{}, fmt.Errorf("hold %d was already %s: %w", holdId, hold.Status, ConflictError)
+	}
+
+	return hold, nil
+}
+
+// txIdが0でなければ、確定した際のtransactionとして記録します
+func (model *Model) updateHoldStatus(ctx context.Context, holdId int, status sqlc.HoldStatus, txId int) error {
+	err := model.queries.UpdateHoldStatus(ctx, sqlc.UpdateHoldStatusParams{
+		ID:          int64(holdId),
+		Status:      status,
+		Transaction: sql.NullInt64{Int64: int64(txId), Valid: txId != 0},
+	})
+	if err != nil {
+		return fmt.Errorf("query UpdateHoldStatus: %w", err)
+	}
+	return nil
+}
+
+func mapToHold(entity sqlc.LockHoldRow) (Hold, error) {
+	amount, err := parseDecimal(entity.Amount)
+	if err != nil {
+		return Hold{}, err
+	}
+
+	status := HoldStatus(entity.Status)
+	if entity.Status == sqlc.HoldStatusActive && entity.Expired {
+		status = HoldStatusExpired
+	}
+
+	var transaction *int
+	if entity.Transaction.Valid {
+		id := int(entity.Transaction.Int64)
+		transaction = &id
+	}
+
+	return Hold{
+		Id:          int(entity.ID),
+		Account:     int(entity.Account),
+		Amount:      amount,
+		Currency:    entity.Currency,
+		Status:      status,
+		ExpiresAt:   entity.ExpiresAt,
+		InsertedAt:  entity.InsertedAt,
+		Transaction: transaction,
+	}, nil
+}
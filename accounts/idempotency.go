@@ -0,0 +1,92 @@
+package accounts
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rail44/g/sqlc/generated"
+)
+
+// Idempotency-Keyヘッダによる再送の検出に使う情報
+type Idempotency struct {
+	Key string
+	// 同じキーで異なる内容のリクエストが送られてきたことを検出するためのハッシュ
+	RequestHash string
+}
+
+// Idempotency-Keyヘッダの値と、operationとaccountIdとリクエストボディからIdempotencyを作ります
+// ヘッダが付いていなければnilを返します
+// accountsのModelを使う他のパッケージのハンドラからも使います
+func NewIdempotency(key *IdempotencyKey, operation string, accountId int, body interface{}) (*Idempotency, error) {
+	if key == nil {
+		return nil, nil
+	}
+
+	if len(*key) == 0 || len(*key) > 255 {
+		return nil, fmt.Errorf("Idempotency-Key should be 1 to 255 characters: %w", ValidationError)
+	}
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("encoding request body: %w", err)
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%d\n", operation, accountId)
+	hash.Write(encoded)
+
+	return &Idempotency{Key: *key, RequestHash: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// idempotencyのキーが既に使われていれば、fを実行せずに最初のリクエストのtransactionのidを返します
+// 使われていなければfを実行し、その結果をキーと一緒に記録します
+// 同じトランザクションでInsertTransactionと一緒に書き込むため、WithTxで得たModelから呼び出してください
+func (model *Model) idempotent(ctx context.Context, accountId int, idempotency *Idempotency, f func() (int, error)) (int, error) {
+	if idempotency == nil {
+		return f()
+	}
+
+	// 同じキーのリクエストが同時に届いた場合でも、片方がコミットするまでもう片方を待たせます
+	err := model.queries.LockIdempotencyKey(ctx, sqlc.LockIdempotencyKeyParams{
+		Key:     idempotency.Key,
+		Account: int64(accountId),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("query LockIdempotencyKey: %w", err)
+	}
+
+	stored, err := model.queries.GetIdempotencyKey(ctx, sqlc.GetIdempotencyKeyParams{
+		Account: int64(accountId),
+		Key:     idempotency.Key,
+	})
+	if err == nil {
+		if stored.RequestHash != idempotency.RequestHash {
+			return 0, fmt.Errorf("Idempotency-Key %s was already used for another request: %w", idempotency.Key, ConflictError)
+		}
+		return int(stored.Transaction), nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, fmt.Errorf("query GetIdempotencyKey: %w", err)
+	}
+
+	txId, err := f()
+	if err != nil {
+		return 0, err
+	}
+
+	err = model.queries.InsertIdempotencyKey(ctx, sqlc.InsertIdempotencyKeyParams{
+		Account:     int64(accountId),
+		Key:         idempotency.Key,
+		RequestHash: idempotency.RequestHash,
+		Transaction: int64(txId),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("query InsertIdempotencyKey: %w", err)
+	}
+
+	return txId, nil
+}
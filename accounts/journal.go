@@ -0,0 +1,178 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+// ひとつのjournalに含められるlegsの上限
+const MaxJournalLegs = 100
+
+// journalでaccountのcurrencyの残高を動かす1行
+// Amountが正なら入金、負なら出金です
+type Leg struct {
+	Account  int             `json:"account"`
+	Currency string          `json:"currency"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+// 複数のaccountsの間で、legsをひとつのtransactionとしてまとめて動かします
+// legsはcurrency毎に合計が0でなければならず、出金するaccountはそれぞれの出金の合計以上の残高をもっている必要があります
+// 出金するaccountそれぞれの差し引きの出金には、transferの制限を適用します
+// transactionは最初に出金するaccountが行ったものとして記録し、idempotencyもそのaccountについて扱います
+func (model *Model) PostJournal(ctx context.Context, legs []Leg, memo *string, metadata Metadata, idempotency *Idempotency) (int, error) {
+	initiator, err := JournalInitiator(legs)
+	if err != nil {
+		return 0, err
+	}
+
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (int, error) {
+		model := model.WithTx(tx)
+		return model.idempotent(ctx, initiator, idempotency, func() (int, error) {
+			return model.postJournal(ctx, initiator, legs, memo, metadata)
+		})
+	})
+}
+
+// PostJournalの本体です。WithTxで得たModelから呼び出してください
+func (model *Model) postJournal(ctx context.Context, initiator int, legs []Leg, memo *string, metadata Metadata) (int, error) {
+	if len(legs) < 2 || len(legs) > MaxJournalLegs {
+		return 0, fmt.Errorf("legs should have 2 to %d items: %w", MaxJournalLegs, ValidationError)
+	}
+
+	columns, err := newMemoColumns(memo, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	encoded, err := encodeMetadata(metadata)
+	if err != nil {
+		return 0, err
+	}
+
+	currencies := map[string]Currency{}
+	sums := map[string]decimal.Decimal{}
+	nets := map[balanceKey]decimal.Decimal{}
+	for i, leg := range legs {
+		if leg.Amount.IsZero() {
+			return 0, fmt.Errorf("amount of leg %d should not be zero: %w", i, ValidationError)
+		}
+
+		currency, ok := currencies[leg.Currency]
+		if !ok {
+			currency, err = model.GetCurrency(ctx, leg.Currency)
+			if err != nil {
+				return 0, err
+			}
+			currencies[leg.Currency] = currency
+		}
+
+		err = currency.ValidateAmount(leg.Amount.Abs())
+		if err != nil {
+			return 0, fmt.Errorf("leg %d: %w", i, err)
+		}
+
+		sums[leg.Currency] = sums[leg.Currency].Add(leg.Amount)
+		key := balanceKey{account: leg.Account, currency: leg.Currency}
+		nets[key] = nets[key].Add(leg.Amount)
+	}
+
+	for currency, sum := range sums {
+		if !sum.IsZero() {
+			return 0, fmt.Errorf("legs in %s should net to zero, but sum to %s: %w", currency, sum, ValidationError)
+		}
+	}
+
+	// 凍結や閉鎖と競合しないよう、残高より先にaccountsのstatusを共有ロックします
+	accountIds := []int{}
+	seen := map[int]bool{}
+	for _, leg := range legs {
+		if !seen[leg.Account] {
+			seen[leg.Account] = true
+			accountIds = append(accountIds, leg.Account)
+		}
+	}
+	sort.Ints(accountIds)
+	for _, id := range accountIds {
+		err = model.ensureActive(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	keys := make([]balanceKey, 0, len(nets))
+	for key := range nets {
+		keys = append(keys, key)
+	}
+	err = model.lockBalanceKeys(ctx, keys...)
+	if err != nil {
+		return 0, err
+	}
+
+	for key, net := range nets {
+		if !net.IsNegative() {
+			continue
+		}
+		// 出金するaccountそれぞれに、差し引きの出金をひとつのtransferとして制限を確かめます
+		err = model.checkLimits(ctx, LimitOperationTransfer, key.account, currencies[key.currency], net.Neg())
+		if err != nil {
+			return 0, err
+		}
+		err = model.HasEnough(ctx, key.account, key.currency, net.Neg())
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	journalId, err := model.queries.InsertJournal(ctx, columns.memo)
+	if err != nil {
+		return 0, fmt.Errorf("query InsertJournal: %w", err)
+	}
+
+	postings := make([]posting, 0, len(legs))
+	for _, leg := range legs {
+		err = model.queries.InsertJournalLeg(ctx, sqlc.InsertJournalLegParams{
+			Journal:  journalId,
+			Account:  int64(leg.Account),
+			Currency: leg.Currency,
+			Amount:   leg.Amount.String(),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("query InsertJournalLeg: %w", err)
+		}
+
+		postings = append(postings, posting{account: leg.Account, currency: leg.Currency, amount: leg.Amount})
+	}
+
+	txId, err := model.queries.InsertTransaction(ctx, sqlc.InsertTransactionParams{
+		Account:  int64(initiator),
+		Journal:  sql.NullInt64{Int64: journalId, Valid: true},
+		Metadata: encoded,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("query InsertTransaction: %w", err)
+	}
+
+	err = model.post(ctx, txId, postings...)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(txId), nil
+}
+
+// 最初に出金するaccountを返します
+// PostJournalはこのaccountについてidempotencyを扱うので、NewIdempotencyにもこのaccountを渡してください
+func JournalInitiator(legs []Leg) (int, error) {
+	for _, leg := range legs {
+		if leg.Amount.IsNegative() {
+			return leg.Account, nil
+		}
+	}
+	return 0, fmt.Errorf("legs should have at least one negative amount: %w", ValidationError)
+}
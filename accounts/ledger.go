@@ -0,0 +1,136 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+// schema.sqlで作成されるシステム勘定
+// Mintはissuanceから、Spendはsinkへの振替として仕訳します
+// Conversionは換算元をexchangeが受け取り、換算先をexchangeが払い出すものとして仕訳します
+// Escrowsの資金は払い出すか払い戻すまでescrowが預かります
+const (
+	IssuanceAccountId = -1
+	SinkAccountId     = -2
+	ExchangeAccountId = -3
+	EscrowAccountId   = -4
+)
+
+// balancesとpostingsが食い違っている場合のエラー
+var LedgerMismatchError = errors.New("Ledger Mismatch")
+
+func isSystemAccount(id int) bool {
+	return id < 0
+}
+
+// 仕訳の1行
+// amountが正なら入金、負なら出金です
+type posting struct {
+	account  int
+	currency string
+	amount   decimal.Decimal
+}
+
+// txIdのtransactionに仕訳を記録し、残高のキャッシュであるbalancesも同じだけ動かします
+// 仕訳はcurrency毎に合計が0でなければならず、通貨をまたいで釣り合わせることはできません
+// balancesは必ずここを通して更新することで、postingsの合計と一致させます
+// システム勘定はbalancesを持たず、残高はpostingsから導出します(全てのMintとSpendで同じ行を更新して詰まらないように)
+func (model *Model) post(ctx context.Context, txId int64, postings ...posting) error {
+	sums := map[string]decimal.Decimal{}
+	for _, p := range postings {
+		sums[p.currency] = sums[p.currency].Add(p.amount)
+	}
+	for currency, sum := range sums {
+		if !sum.IsZero() {
+			return fmt.Errorf("postings for transaction %d are not balanced in %s: %s", txId, currency, sum)
+		}
+	}
+
+	for _, p := range postings {
+		// 仕訳の直後の残高も記録しておき、取引履歴で全履歴を積み上げずに済むようにします
+		var balanceAfter sql.NullString
+		if !isSystemAccount(p.account) {
+			balance, err := model.moveBalance(ctx, p)
+			if err != nil {
+				return err
+			}
+			balanceAfter = sql.NullString{String: balance, Valid: true}
+		}
+
+		err := model.queries.InsertPosting(ctx, sqlc.InsertPostingParams{
+			Transaction:  txId,
+			Account:      int64(p.account),
+			Amount:       p.amount.String(),
+			Currency:     p.currency,
+			BalanceAfter: balanceAfter,
+		})
+		if err != nil {
+			return fmt.Errorf("query InsertPosting: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// pの分だけbalancesを動かし、動かした後の残高を返します
+func (model *Model) moveBalance(ctx context.Context, p posting) (string, error) {
+	if !p.amount.IsNegative() {
+		balance, err := model.queries.IncrementBalance(ctx, sqlc.IncrementBalanceParams{
+			Account:  int64(p.account),
+			Currency: p.currency,
+			Amount:   p.amount.String(),
+		})
+		if err != nil {
+			return "", fmt.Errorf("query IncrementBalance: %w", err)
+		}
+		return balance, nil
+	}
+
+	balance, err := model.queries.DecrementBalance(ctx, sqlc.DecrementBalanceParams{
+		Account:  int64(p.account),
+		Currency: p.currency,
+		Amount:   p.amount.Neg().String(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("query DecrementBalance: %w", err)
+	}
+	return balance, nil
+}
+
+// postingsの合計から導出したidのcurrencyの残高を返します
+// システム勘定の残高もこちらで求めます
+func (model *Model) GetPostedBalance(ctx context.Context, id int, currency string) (decimal.Decimal, error) {
+	balanceDecimal, err := model.queries.GetPostedBalance(ctx, sqlc.GetPostedBalanceParams{
+		Account:  int64(id),
+		Currency: currency,
+	})
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("query GetPostedBalance: %w", err)
+	}
+
+	return parseDecimal(balanceDecimal)
+}
+
+// balancesに記録された残高がpostingsの合計と一致していなければLedgerMismatchError
+func (model *Model) VerifyBalance(ctx context.Context, id int, currency string) error {
+	balance, err := model.GetBalance(ctx, id, currency)
+	if err != nil {
+		return err
+	}
+
+	posted, err := model.GetPostedBalance(ctx, id, currency)
+	if err != nil {
+		return err
+	}
+
+	if !balance.Equal(posted) {
+		return fmt.Errorf("%s balance of account %d was %s, but postings sum up to %s: %w", currency, id, balance, posted, LedgerMismatchError)
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+// 出金の制限をかける取引の種類
+type LimitOperation string
+
+const (
+	LimitOperationTransfer LimitOperation = "transfer"
+	LimitOperationSpend    LimitOperation = "spend"
+)
+
+// 出金の制限の種類
+type LimitKind string
+
+const (
+	// 1回の出金の金額の上限
+	LimitKindMaxAmount LimitKind = "max_amount"
+	// 直近24時間の出金の金額の合計の上限
+	LimitKindDailyVolume LimitKind = "daily_volume"
+	// 直近1時間の出金の回数の上限
+	LimitKindHourlyCount LimitKind = "hourly_count"
+)
+
+// 制限を数える期間(秒)
+var limitWindows = map[LimitKind]int{
+	LimitKindDailyVolume: 24 * 60 * 60,
+	LimitKindHourlyCount: 60 * 60,
+}
+
+// accountがoperationでcurrencyを出金する際の制限
+// AccountがnilであればすべてのAccountに対する既定値で、同じOperation, Currency, Kindのaccount毎の制限があればそちらを優先します
+type Limit struct {
+	Id         int             `json:"id"`
+	Account    *int            `json:"account,omitempty"`
+	Operation  LimitOperation  `json:"operation"`
+	Currency   string          `json:"currency"`
+	Kind       LimitKind       `json:"kind"`
+	Value      decimal.Decimal `json:"value"`
+	InsertedAt time.Time       `json:"inserted_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// 出金が制限を超えるために行えなかったことを表すエラー
+// Usageは制限を数える期間にすでに出金した金額か回数で、max_amountでは0です
+// 残高不足と同じく、再試行しても結果の変わらないDomainErrorとして扱います
+type LimitExceededError struct {
+	Limit     Limit
+	Usage     decimal.Decimal
+	Requested decimal.Decimal
+}
+
+func (err *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s limit %s of %s for %s was exceeded: usage %s, requested %s", err.Limit.Kind, err.Limit.Value, err.Limit.Operation, err.Limit.Currency, err.Usage, err.Requested)
+}
+
+func (err *LimitExceededError) Unwrap() error {
+	return DomainError
+}
+
+func (operation LimitOperation) validate() error {
+	switch operation {
+	case LimitOperationTransfer, LimitOperationSpend:
+		return nil
+	}
+	return fmt.Errorf("operation should be transfer or spend %q: %w", operation, ValidationError)
+}
+
+func (kind LimitKind) validate() error {
+	switch kind {
+	case LimitKindMaxAmount, LimitKindDailyVolume, LimitKindHourlyCount:
+		return nil
+	}
+	return fmt.Errorf("kind should be max_amount, daily_volume or hourly_count %q: %w", kind, ValidationError)
+}
+
+func (model *Model) GetLimits(ctx context.Context) ([]Limit, error) {
+	rows, err := model.queries.GetLimits(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query GetLimits: %w", err)
+	}
+
+	limits := make([]Limit, 0, len(rows))
+	for _, row := range rows {
+		limit, err := mapToLimit(row)
+		if err != nil {
+			return nil, err
+		}
+		limits = append(limits, limit)
+	}
+	return limits, nil
+}
+
+// limit.Account, limit.Operation, limit.Currency, limit.Kindの制限を定めます。既に定めがあれば置き換えます
+func (model *Model) SetLimit(ctx context.Context, limit Limit) (Limit, error) {
+	err := limit.Operation.validate()
+	if err != nil {
+		return Limit{}, err
+	}
+
+	err = limit.Kind.validate()
+	if err != nil {
+		return Limit{}, err
+	}
+
+	var account sql.NullInt64
+	if limit.Account != nil {
+		err = model.Exists(ctx, *limit.Account)
+		if err != nil {
+			return Limit{}, err
+		}
+		account = sql.NullInt64{Int64: int64(*limit.Account), Valid: true}
+	}
+
+	currency, err := model.GetCurrency(ctx, limit.Currency)
+	if err != nil {
+		return Limit{}, err
+	}
+
+	if !limit.Value.IsPositive() {
+		return Limit{}, fmt.Errorf("limit should be positive %s: %w", limit.Value, ValidationError)
+	}
+	if limit.Kind == LimitKindHourlyCount {
+		if !limit.Value.IsInteger() {
+			return Limit{}, fmt.Errorf("limit of %s should be an integer %s: %w", limit.Kind, limit.Value, ValidationError)
+		}
+	} else if !limit.Value.Equal(limit.Value.Truncate(currency.Scale)) {
+		return Limit{}, fmt.Errorf("limit %s has more than %d decimal places for %s: %w", limit.Value, currency.Scale, currency.Code, ValidationError)
+	}
+
+	entity, err := model.queries.UpsertLimit(ctx, sqlc.UpsertLimitParams{
+		Account:   account,
+		Operation: sqlc.LimitOperation(limit.Operation),
+		Currency:  limit.Currency,
+		Kind:      sqlc.LimitKind(limit.Kind),
+		Value:     limit.Value.String(),
+	})
+	if err != nil {
+		return Limit{}, fmt.Errorf("query UpsertLimit: %w", err)
+	}
+
+	return mapToLimit(entity)
+}
+
+// idの制限を削除します
+// account毎の制限を削除すると、以後はそのaccountにも既定値が適用されます
+func (model *Model) DeleteLimit(ctx context.Context, id int) error {
+	affected, err := model.queries.DeleteLimit(ctx, int64(id))
+	if err != nil {
+		return fmt.Errorf("query DeleteLimit: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("Not found limit by id %d: %w", id, NotFoundError)
+	}
+	return nil
+}
+
+// accountIdがoperationでcurrencyのamountsを出金しても制限を超えないか確認し、超えるならLimitExceededErrorを返します
+// 直近の出金を数えるので、accountIdのcurrencyの残高の行をロックし、同時に行われる出金を待ってから呼び出してください
+func (model *Model) checkLimits(ctx context.Context, operation LimitOperation, accountId int, currency Currency, amounts ...decimal.Decimal) error {
+	rows, err := model.queries.GetEffectiveLimits(ctx, sqlc.GetEffectiveLimitsParams{
+		Account:   sql.NullInt64{Int64: int64(accountId), Valid: true},
+		Operation: sqlc.LimitOperation(operation),
+		Currency:  currency.Code,
+	})
+	if err != nil {
+		return fmt.Errorf("query GetEffectiveLimits: %w", err)
+	}
+
+	total := decimal.Zero
+	for _, amount := range amounts {
+		total = total.Add(amount)
+	}
+
+	for _, row := range rows {
+		limit, err := mapToLimit(row)
+		if err != nil {
+			return err
+		}
+
+		if limit.Kind == LimitKindMaxAmount {
+			for _, amount := range amounts {
+				if amount.GreaterThan(limit.Value) {
+					return &LimitExceededError{Limit: limit, Usage: decimal.Zero, Requested: amount}
+				}
+			}
+			continue
+		}
+
+		usage, err := model.queries.GetOutgoingUsage(ctx, sqlc.GetOutgoingUsageParams{
+			Account:       int64(accountId),
+			WindowSeconds: int32(limitWindows[limit.Kind]),
+			Operation:     string(operation),
+			Currency:      currency.Code,
+		})
+		if err != nil {
+			return fmt.Errorf("query GetOutgoingUsage: %w", err)
+		}
+
+		var used, requested decimal.Decimal
+		switch limit.Kind {
+		case LimitKindDailyVolume:
+			used, err = parseDecimal(usage.Volume)
+			if err != nil {
+				return err
+			}
+			requested = total
+		case LimitKindHourlyCount:
+			used = decimal.NewFromInt(usage.Count)
+			requested = decimal.NewFromInt(int64(len(amounts)))
+		}
+
+		if used.Add(requested).GreaterThan(limit.Value) {
+			return &LimitExceededError{Limit: limit, Usage: used, Requested: requested}
+		}
+	}
+	return nil
+}
+
+func mapToLimit(entity sqlc.Limit) (Limit, error) {
+	value, err := parseDecimal(entity.Value)
+	if err != nil {
+		return Limit{}, err
+	}
+
+	var account *int
+	if entity.Account.Valid {
+		id := int(entity.Account.Int64)
+		account = &id
+	}
+
+	return Limit{
+		Id:         int(entity.ID),
+		Account:    account,
+		Operation:  LimitOperation(entity.Operation),
+		Currency:   entity.Currency,
+		Kind:       LimitKind(entity.Kind),
+		Value:      value,
+		InsertedAt: entity.InsertedAt,
+		UpdatedAt:  entity.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package accounts
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// memoとreferenceの長さの上限
+const (
+	MaxMemoLength      = 500
+	MaxReferenceLength = 255
+)
+
+// memoやreferenceが空であったり長すぎればValidationError
+func validateMemo(memo *string, reference *Reference) error {
+	if memo != nil {
+		if len(*memo) == 0 {
+			return fmt.Errorf("memo should not be empty: %w", ValidationError)
+		}
+		if utf8.RuneCountInString(*memo) > MaxMemoLength {
+			return fmt.Errorf("memo is longer than %d characters: %w", MaxMemoLength, ValidationError)
+		}
+	}
+
+	if reference != nil {
+		if len(reference.Type) == 0 || len(reference.Id) == 0 {
+			return fmt.Errorf("reference should have both type and id: %w", ValidationError)
+		}
+		if utf8.RuneCountInString(reference.Type) > MaxReferenceLength || utf8.RuneCountInString(reference.Id) > MaxReferenceLength {
+			return fmt.Errorf("reference type and id should be at most %d characters: %w", MaxReferenceLength, ValidationError)
+		}
+	}
+
+	return nil
+}
+
+// mints, spends, transfersに書き込むmemoとreferenceのカラム
+type memoColumns struct {
+	memo          sql.NullString
+	referenceType sql.NullString
+	referenceId   sql.NullString
+}
+
+// memoとreferenceを検証してカラムの値にします
+func newMemoColumns(memo *string, reference *Reference) (memoColumns, error) {
+	err := validateMemo(memo, reference)
+	if err != nil {
+		return memoColumns{}, err
+	}
+
+	columns := memoColumns{}
+	if memo != nil {
+		columns.memo = sql.NullString{String: *memo, Valid: true}
+	}
+	if reference != nil {
+		columns.referenceType = sql.NullString{String: reference.Type, Valid: true}
+		columns.referenceId = sql.NullString{String: reference.Id, Valid: true}
+	}
+	return columns, nil
+}
+
+func mapToMemo(memo sql.NullString, referenceType sql.NullString, referenceId sql.NullString) (*string, *Reference) {
+	var m *string
+	if memo.Valid {
+		m = &memo.String
+	}
+
+	var reference *Reference
+	if referenceType.Valid && referenceId.Valid {
+		reference = &Reference{Type: referenceType.String, Id: referenceId.String}
+	}
+
+	return m, reference
+}
+
+// ILIKEのパターンの中で、利用者の入力した%と_をそのままの文字として扱うためにエスケープします
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
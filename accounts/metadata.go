@@ -0,0 +1,80 @@
+package accounts
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// metadataの大きさの上限
+// 検索のためのものなので、大きなデータは連携先のシステムで持ってもらいます
+const (
+	MaxMetadataKeys        = 50
+	MaxMetadataKeyLength   = 40
+	MaxMetadataValueLength = 500
+	MaxExternalIdLength    = 255
+)
+
+// metadataを受け付けない取引に書き込む空のオブジェクト
+var emptyMetadata = json.RawMessage("{}")
+
+// metadataの大きさが上限を超えていればValidationError
+func validateMetadata(metadata Metadata) error {
+	if len(metadata) > MaxMetadataKeys {
+		return fmt.Errorf("metadata can have at most %d keys: %w", MaxMetadataKeys, ValidationError)
+	}
+
+	for key, value := range metadata {
+		if len(key) == 0 {
+			return fmt.Errorf("metadata key should not be empty: %w", ValidationError)
+		}
+		if utf8.RuneCountInString(key) > MaxMetadataKeyLength {
+			return fmt.Errorf("metadata key %q is longer than %d characters: %w", key, MaxMetadataKeyLength, ValidationError)
+		}
+		if utf8.RuneCountInString(value) > MaxMetadataValueLength {
+			return fmt.Errorf("metadata value for %q is longer than %d characters: %w", key, MaxMetadataValueLength, ValidationError)
+		}
+	}
+
+	return nil
+}
+
+// metadataを検証してJSONBのカラムに書き込む形にします。nilは空のオブジェクトになります
+func encodeMetadata(metadata Metadata) (json.RawMessage, error) {
+	err := validateMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata == nil {
+		metadata = Metadata{}
+	}
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("encoding metadata: %w", err)
+	}
+	return encoded, nil
+}
+
+func decodeMetadata(raw json.RawMessage) (Metadata, error) {
+	metadata := Metadata{}
+	err := json.Unmarshal(raw, &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("decoding metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+func validateExternalId(externalId *string) error {
+	if externalId == nil {
+		return nil
+	}
+	if len(*externalId) == 0 {
+		return fmt.Errorf("external_id should not be empty: %w", ValidationError)
+	}
+	if utf8.RuneCountInString(*externalId) > MaxExternalIdLength {
+		return fmt.Errorf("external_id is longer than %d characters: %w", MaxExternalIdLength, ValidationError)
+	}
+	return nil
+}
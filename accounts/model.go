@@ -7,7 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/rail44/g/sqlc/generated"
-	"strconv"
+	"sort"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
 )
 
 var NotFoundError = errors.New("NotFound")
@@ -15,47 +18,45 @@ var NotFoundError = errors.New("NotFound")
 // データを問い合わせて発覚するロジックのエラー
 var DomainError = errors.New("Domain Error")
 
-// 関数をラップして単一トランザクションでクエリを実行するためのユーティリティ関数
-// 下の方に使用例があります
-func WithTransaction[T interface{}](db *sql.DB, f func(tx *sql.Tx) (T, error)) (T, error) {
-	var v T
-	tx, err := db.Begin()
-	if err != nil {
-		return v, fmt.Errorf("begining transaction: %w", err)
-	}
-
-	// Commit()まで到達せずにスコープを抜けた場合はRollback
-	defer tx.Rollback()
-
-	v, err = f(tx)
-	if err != nil {
-		return v, err
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		return v, fmt.Errorf("commit: %w", err)
-	}
-
-	return v, nil
-}
+// 既にある状態と矛盾するリクエストのエラー
+var ConflictError = errors.New("Conflict")
 
 // sqlcで生成したクエリを発行するためのドメインモデル
 // QueryとCommandに分けるのもありかもしれないです
 // (Commandについてはinstantiate毎にトランザクションを発行してしまうと見通しがよくなるかもしれない
 type Model struct {
-	db *sql.DB
+	db        *sql.DB
+	queries   *sqlc.Queries
+	txOptions TxOptions
 }
 
 func NewModel(db *sql.DB) *Model {
-	return &Model{db: db}
+	return &Model{db: db, queries: sqlc.New(db), txOptions: DefaultTxOptions}
+}
+
+// 書き込みのトランザクションをoptsで実行するModelを返します
+func (model *Model) WithTxOptions(opts TxOptions) *Model {
+	m := *model
+	m.txOptions = opts
+	return &m
+}
+
+// txを通して問い合わせを行うModelを返します
+// WithTransactionに渡す関数の中ではこちらを使うことで、残高の確認なども含めて同一トランザクションで実行されます
+func (model *Model) WithTx(tx *sql.Tx) *Model {
+	m := *model
+	m.queries = model.queries.WithTx(tx)
+	return &m
 }
 
 // idのaccountsが存在していなければNotFound Error
+// システム勘定は利用者のaccountsとしては存在しないものとして扱います
 func (model *Model) Exists(ctx context.Context, id int) error {
-	queries := sqlc.New(model.db)
+	if isSystemAccount(id) {
+		return fmt.Errorf("Not found account by id %d: %w", id, NotFoundError)
+	}
 
-	_, err := queries.GetAccount(ctx, int64(id))
+	_, err := model.queries.GetAccount(ctx, int64(id))
 
 	if err == sql.ErrNoRows {
 		// NotFoundErrorをwrapしてearly return
@@ -70,211 +71,519 @@ func (model *Model) Exists(ctx context.Context, id int) error {
 	return nil
 }
 
-// idのaccountsがamount以上の残高をもっていなければDomainError
-func (model *Model) HasEnough(ctx context.Context, id int, amount int) error {
-	balance, err := model.GetBalance(ctx, id)
+// idのaccountsがcurrencyでamount以上の利用可能な残高をもっていなければDomainError
+// 利用可能な残高は、残高から有効なholdsの合計を差し引いたものです
+// 与信枠があるaccountsは、利用可能な残高が-credit_limitを下回らない範囲で負にできます
+// 残高の行はSELECT ... FOR UPDATEでロックされるため、トランザクション内で呼べばコミットまで他の更新を待たせることができます
+// holdsの追加も同じ行のロックを取ってから行うので、確認の後に仮押さえが増えることはありません
+func (model *Model) HasEnough(ctx context.Context, id int, currency string, amount decimal.Decimal) error {
+	return model.hasEnough(ctx, id, currency, amount, true)
+}
+
+// useCreditがfalseであれば与信枠を使わず、仮押さえを除いた残高だけで確かめます
+// 本人が選んでいない出金で、残高を負にしないために使います
+func (model *Model) hasEnough(ctx context.Context, id int, currency string, amount decimal.Decimal, useCredit bool) error {
+	balance, err := model.LockBalance(ctx, id, currency)
+	if err != nil {
+		return err
+	}
 
+	held, err := model.GetHeldAmount(ctx, id, currency)
 	if err != nil {
 		return err
 	}
 
-	if amount > balance {
-		return fmt.Errorf("%d amount was requested, but balance was only %d: %w", amount, balance, DomainError)
+	credit := decimal.Zero
+	if useCredit {
+		credit, err = model.GetCreditLimit(ctx, id, currency)
+		if err != nil {
+			return err
+		}
+	}
+
+	if available := balance.Sub(held); amount.GreaterThan(available.Add(credit)) {
+		return fmt.Errorf("%s %s was requested, but available balance was only %s with credit limit %s: %w", amount, currency, available, credit, DomainError)
 	}
 
 	return nil
 }
 
-func (model *Model) GetBalance(ctx context.Context, id int) (int, error) {
-	queries := sqlc.New(model.db)
-
+// idのaccountsのcurrencyの残高を返します
+// まだ一度も受け取っていないcurrencyの残高は0です
+func (model *Model) GetBalance(ctx context.Context, id int, currency string) (decimal.Decimal, error) {
 	err := model.Exists(ctx, id)
 	if err != nil {
-		return 0, err
+		return decimal.Decimal{}, err
 	}
 
-	balanceDecimal, err := queries.GetBalance(ctx, int64(id))
+	_, err = model.GetCurrency(ctx, currency)
 	if err != nil {
-		return 0, fmt.Errorf("querying GetBalance: %w", err)
+		return decimal.Decimal{}, err
 	}
 
-	balance, err := strconv.Atoi(balanceDecimal)
+	balanceDecimal, err := model.queries.GetBalance(ctx, sqlc.GetBalanceParams{
+		Account:  int64(id),
+		Currency: currency,
+	})
+	if err == sql.ErrNoRows {
+		return decimal.Zero, nil
+	}
 	if err != nil {
-		return 0, fmt.Errorf("parsing balance as decimal: %w", err)
+		return decimal.Decimal{}, fmt.Errorf("querying GetBalance: %w", err)
 	}
-	return balance, nil
+
+	return parseDecimal(balanceDecimal)
 }
 
-func (model *Model) Register(ctx context.Context, name string) (int, error) {
-	return WithTransaction(model.db, func(tx *sql.Tx) (int, error) {
-		queries := sqlc.New(tx)
-		accountId, err := queries.InsertAccount(ctx, sql.NullString{String: name, Valid: true})
+// idのaccountsが持っている全てのcurrencyの残高を返します
+func (model *Model) GetBalances(ctx context.Context, id int) ([]Balance, error) {
+	err := model.Exists(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := model.queries.GetBalances(ctx, int64(id))
+	if err != nil {
+		return nil, fmt.Errorf("querying GetBalances: %w", err)
+	}
+
+	balances := make([]Balance, 0, len(rows))
+	for _, row := range rows {
+		balance, err := parseDecimal(row.Balance)
 		if err != nil {
-			return 0, fmt.Errorf("querying InsertAccount: %w", err)
+			return nil, err
 		}
 
-		err = queries.InsertBalance(ctx, accountId)
+		held, err := model.GetHeldAmount(ctx, id, row.Currency)
 		if err != nil {
-			return 0, fmt.Errorf("querying InsertBalance: %w", err)
+			return nil, err
 		}
 
-		return int(accountId), nil
-	})
+		credit, err := model.GetCreditLimit(ctx, id, row.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		balances = append(balances, Balance{
+			Currency:    row.Currency,
+			Balance:     balance,
+			Available:   balance.Sub(held),
+			CreditLimit: credit,
+			Overdrawn:   balance.IsNegative(),
+		})
+	}
+
+	return balances, nil
 }
 
-func (model *Model) Mint(ctx context.Context, accountId int, amount int) (int, error) {
-	return WithTransaction(model.db, func(tx *sql.Tx) (int, error) {
-		queries := sqlc.New(tx)
+// currencyの複数の残高の行をidの昇順にロックします
+// 常に同じ順序でロックを取ることで、逆向きの送金が同時に走ってもデッドロックしないようにしています
+func (model *Model) LockBalances(ctx context.Context, currency string, ids ...int) error {
+	keys := make([]balanceKey, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, balanceKey{account: id, currency: currency})
+	}
+	return model.lockBalanceKeys(ctx, keys...)
+}
 
-		err := model.Exists(ctx, accountId)
-		if err != nil {
-			return 0, err
+// 残高の行を特定するaccountとcurrencyの組
+type balanceKey struct {
+	account  int
+	currency string
+}
+
+// 異なるcurrencyを含む残高の行を(account, currency)の昇順にロックします
+// LockBalancesも同じ順序に従うので、通貨をまたぐ取引と同時に走ってもデッドロックしません
+func (model *Model) lockBalanceKeys(ctx context.Context, keys ...balanceKey) error {
+	sorted := append([]balanceKey{}, keys...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].account != sorted[j].account {
+			return sorted[i].account < sorted[j].account
 		}
+		return sorted[i].currency < sorted[j].currency
+	})
 
-		amountDecimal := strconv.Itoa(amount)
-		mintId, err := queries.InsertMint(ctx, amountDecimal)
+	for _, key := range sorted {
+		_, err := model.LockBalance(ctx, key.account, key.currency)
 		if err != nil {
-			return 0, fmt.Errorf("querying InsertMint: %w", err)
+			return err
 		}
+	}
 
-		accountIdInt64 := int64(accountId)
-		txId, err := queries.InsertTransaction(ctx, sqlc.InsertTransactionParams{
-			Account: accountIdInt64,
-			Mint:    sql.NullInt64{Int64: mintId, Valid: true},
-		})
+	return nil
+}
+
+// GetBalanceと同様ですが、残高の行をトランザクションの終わりまでロックします
+// 行がまだなければ作ってからロックします
+func (model *Model) LockBalance(ctx context.Context, id int, currency string) (decimal.Decimal, error) {
+	err := model.Exists(ctx, id)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	params := sqlc.LockBalanceParams{Account: int64(id), Currency: currency}
+	balanceDecimal, err := model.queries.LockBalance(ctx, params)
+	if err == sql.ErrNoRows {
+		err = model.queries.InsertBalance(ctx, sqlc.InsertBalanceParams{Account: int64(id), Currency: currency})
 		if err != nil {
-			return 0, fmt.Errorf("querying InsertTransaction: %w", err)
+			return decimal.Decimal{}, fmt.Errorf("querying InsertBalance: %w", err)
 		}
+		balanceDecimal, err = model.queries.LockBalance(ctx, params)
+	}
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("querying LockBalance: %w", err)
+	}
 
-		err = queries.IncrementBalance(ctx, sqlc.IncrementBalanceParams{
-			Account: accountIdInt64,
-			Amount:  strconv.Itoa(amount),
-		})
+	return parseDecimal(balanceDecimal)
+}
+
+// externalIdが指定されていれば、既に同じexternalIdのaccountsがある場合はConflictError
+func (model *Model) Register(ctx context.Context, name string, externalId *string, metadata Metadata) (int, error) {
+	err := validateExternalId(externalId)
+	if err != nil {
+		return 0, err
+	}
+
+	encoded, err := encodeMetadata(metadata)
+	if err != nil {
+		return 0, err
+	}
+
+	params := sqlc.InsertAccountParams{
+		Name:     sql.NullString{String: name, Valid: true},
+		Metadata: encoded,
+	}
+	if externalId != nil {
+		params.ExternalID = sql.NullString{String: *externalId, Valid: true}
+	}
+
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (int, error) {
+		model := model.WithTx(tx)
+		accountId, err := model.queries.InsertAccount(ctx, params)
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return 0, fmt.Errorf("account with external_id %s already exists: %w", *externalId, ConflictError)
+		}
 		if err != nil {
-			return 0, fmt.Errorf("querying IncrementBalance: %w", err)
+			return 0, fmt.Errorf("querying InsertAccount: %w", err)
 		}
-		return int(txId), nil
+
+		return int(accountId), nil
+	})
+}
+
+func (model *Model) Mint(ctx context.Context, accountId int, currency string, amount decimal.Decimal, memo *string, reference *Reference, metadata Metadata, idempotency *Idempotency) (int, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (int, error) {
+		model := model.WithTx(tx)
+		return model.idempotent(ctx, accountId, idempotency, func() (int, error) {
+			return model.mint(ctx, accountId, currency, amount, memo, reference, metadata)
+		})
 	})
 }
 
-func (model *Model) GetTransactions(ctx context.Context, accountId int) ([]interface{}, error) {
-	queries := sqlc.New(model.db)
+// Mintの本体です。WithTxで得たModelから呼び出してください
+func (model *Model) mint(ctx context.Context, accountId int, currency string, amount decimal.Decimal, memo *string, reference *Reference, metadata Metadata) (int, error) {
+	err := model.ensureActive(ctx, accountId)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = model.validateAmount(ctx, currency, amount)
+	if err != nil {
+		return 0, err
+	}
+
+	columns, err := newMemoColumns(memo, reference)
+	if err != nil {
+		return 0, err
+	}
+
+	encoded, err := encodeMetadata(metadata)
+	if err != nil {
+		return 0, err
+	}
+
+	amountDecimal := amount.String()
+	mintId, err := model.queries.InsertMint(ctx, sqlc.InsertMintParams{
+		Amount:        amountDecimal,
+		Currency:      currency,
+		Memo:          columns.memo,
+		ReferenceType: columns.referenceType,
+		ReferenceID:   columns.referenceId,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("querying InsertMint: %w", err)
+	}
+
+	accountIdInt64 := int64(accountId)
+	txId, err := model.queries.InsertTransaction(ctx, sqlc.InsertTransactionParams{
+		Account:  accountIdInt64,
+		Mint:     sql.NullInt64{Int64: mintId, Valid: true},
+		Metadata: encoded,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("querying InsertTransaction: %w", err)
+	}
+
+	err = model.post(ctx, txId,
+		posting{account: IssuanceAccountId, currency: currency, amount: amount.Neg()},
+		posting{account: accountId, currency: currency, amount: amount},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return int(txId), nil
+}
 
+// accountIdの取引履歴をfilterで絞り込み、1ページ分を返します
+// 続きのページがあれば、次のページを取得するためのカーソルを返します。なければ空文字列です
+func (model *Model) GetTransactions(ctx context.Context, accountId int, filter TransactionFilter) ([]interface{}, string, error) {
 	err := model.Exists(ctx, accountId)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	transactions, err := queries.GetTransactions(ctx, int64(accountId))
+	params, err := filter.params(accountId)
 	if err != nil {
-		return nil, fmt.Errorf("query GetTransactions: %w", err)
+		return nil, "", err
 	}
 
-	var result []interface{}
+	transactions, err := model.queries.GetTransactions(ctx, params)
+	if err != nil {
+		return nil, "", fmt.Errorf("query GetTransactions: %w", err)
+	}
+
+	var next string
+	if limit := int(params.RowLimit) - 1; len(transactions) > limit {
+		transactions = transactions[:limit]
+		last := transactions[limit-1]
+		next = encodeCursor(last.InsertedAt, last.TransactionID, last.Currency)
+	}
+
+	result := []interface{}{}
 	for _, v := range transactions {
 		row, err := mapToSubtype(v)
 		if err != nil {
-			return nil, fmt.Errorf("mapToSubtype: %w", err)
+			return nil, "", fmt.Errorf("mapToSubtype: %w", err)
 		}
 
 		result = append(result, row)
 	}
 
-	return result, nil
+	return result, next, nil
 }
 
-func (model *Model) Spend(ctx context.Context, accountId int, amount int) (int, error) {
-	return WithTransaction(model.db, func(tx *sql.Tx) (int, error) {
-		queries := sqlc.New(tx)
-		err := model.Exists(ctx, accountId)
-		if err != nil {
-			return 0, err
-		}
+func (model *Model) Spend(ctx context.Context, accountId int, currency string, amount decimal.Decimal, memo *string, reference *Reference, metadata Metadata, idempotency *Idempotency) (int, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (int, error) {
+		model := model.WithTx(tx)
+		return model.idempotent(ctx, accountId, idempotency, func() (int, error) {
+			return model.spend(ctx, accountId, currency, amount, memo, reference, metadata)
+		})
+	})
+}
 
-		err = model.HasEnough(ctx, accountId, amount)
+// Spendの本体です。WithTxで得たModelから呼び出してください
+func (model *Model) spend(ctx context.Context, accountId int, currency string, amount decimal.Decimal, memo *string, reference *Reference, metadata Metadata) (int, error) {
+	err := model.ensureActive(ctx, accountId)
+	if err != nil {
+		return 0, err
+	}
+
+	c, err := model.validateAmount(ctx, currency, amount)
+	if err != nil {
+		return 0, err
+	}
+
+	columns, err := newMemoColumns(memo, reference)
+	if err != nil {
+		return 0, err
+	}
+
+	encoded, err := encodeMetadata(metadata)
+	if err != nil {
+		return 0, err
+	}
+
+	fee, err := model.quoteFee(ctx, FeeOperationSpend, accountId, c, amount)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := []int{accountId}
+	if fee.applies() {
+		err = model.ensureActive(ctx, fee.account)
 		if err != nil {
 			return 0, err
 		}
+		ids = append(ids, fee.account)
+	}
 
-		amountDecimal := strconv.Itoa(amount)
-		mintId, err := queries.InsertSpend(ctx, amountDecimal)
-		if err != nil {
-			return 0, fmt.Errorf("query InsertSpend: %w", err)
-		}
+	err = model.LockBalances(ctx, currency, ids...)
+	if err != nil {
+		return 0, err
+	}
 
-		accountIdInt64 := int64(accountId)
-		txId, err := queries.InsertTransaction(ctx, sqlc.InsertTransactionParams{
-			Account: accountIdInt64,
-			Spend:   sql.NullInt64{Int64: mintId, Valid: true},
-		})
-		if err != nil {
-			return 0, fmt.Errorf("query InsertTransaction: %w", err)
-		}
+	err = model.checkLimits(ctx, LimitOperationSpend, accountId, c, amount)
+	if err != nil {
+		return 0, err
+	}
 
-		err = queries.DecrementBalance(ctx, sqlc.DecrementBalanceParams{
-			Account: accountIdInt64,
-			Amount:  strconv.Itoa(amount),
-		})
-		if err != nil {
-			return 0, fmt.Errorf("query DecrementBalance: %w", err)
-		}
+	// 手数料も合わせて支払えなければ、spend自体も行いません
+	err = model.HasEnough(ctx, accountId, currency, amount.Add(fee.amount))
+	if err != nil {
+		return 0, err
+	}
 
-		return int(txId), nil
+	amountDecimal := amount.String()
+	mintId, err := model.queries.InsertSpend(ctx, sqlc.InsertSpendParams{
+		Amount:        amountDecimal,
+		Currency:      currency,
+		Memo:          columns.memo,
+		ReferenceType: columns.referenceType,
+		ReferenceID:   columns.referenceId,
 	})
+	if err != nil {
+		return 0, fmt.Errorf("query InsertSpend: %w", err)
+	}
+
+	accountIdInt64 := int64(accountId)
+	txId, err := model.queries.InsertTransaction(ctx, sqlc.InsertTransactionParams{
+		Account:  accountIdInt64,
+		Spend:    sql.NullInt64{Int64: mintId, Valid: true},
+		Metadata: encoded,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("query InsertTransaction: %w", err)
+	}
+
+	err = model.post(ctx, txId,
+		posting{account: accountId, currency: currency, amount: amount.Neg()},
+		posting{account: SinkAccountId, currency: currency, amount: amount},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	err = model.chargeFee(ctx, int(txId), accountId, currency, fee)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(txId), nil
 }
 
-func (model *Model) Transfer(ctx context.Context, senderAccountId int, recipientAccountId int, amount int) (int, error) {
-	return WithTransaction(model.db, func(tx *sql.Tx) (int, error) {
-		queries := sqlc.New(tx)
+// 送金元と送金先で同じcurrencyの残高を動かします。異なる通貨への換算は行いません
+func (model *Model) Transfer(ctx context.Context, senderAccountId int, recipientAccountId int, currency string, amount decimal.Decimal, memo *string, reference *Reference, metadata Metadata, idempotency *Idempotency) (int, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (int, error) {
+		model := model.WithTx(tx)
+		return model.idempotent(ctx, senderAccountId, idempotency, func() (int, error) {
+			return model.transfer(ctx, senderAccountId, recipientAccountId, currency, amount, memo, reference, metadata)
+		})
+	})
+}
 
-		err := model.Exists(ctx, senderAccountId)
-		if err != nil {
-			return 0, err
-		}
+// Transferの本体です。WithTxで得たModelから呼び出してください
+func (model *Model) transfer(ctx context.Context, senderAccountId int, recipientAccountId int, currency string, amount decimal.Decimal, memo *string, reference *Reference, metadata Metadata) (int, error) {
+	err := model.ensureActive(ctx, senderAccountId)
+	if err != nil {
+		return 0, err
+	}
 
-		err = model.Exists(ctx, recipientAccountId)
-		if err != nil {
-			return 0, err
-		}
+	err = model.ensureActive(ctx, recipientAccountId)
+	if err != nil {
+		return 0, err
+	}
+
+	c, err := model.validateAmount(ctx, currency, amount)
+	if err != nil {
+		return 0, err
+	}
+
+	fee, err := model.quoteFee(ctx, FeeOperationTransfer, senderAccountId, c, amount)
+	if err != nil {
+		return 0, err
+	}
 
-		err = model.HasEnough(ctx, senderAccountId, amount)
+	ids := []int{senderAccountId, recipientAccountId}
+	if fee.applies() {
+		err = model.ensureActive(ctx, fee.account)
 		if err != nil {
 			return 0, err
 		}
+		ids = append(ids, fee.account)
+	}
 
-		amountDecimal := strconv.Itoa(amount)
-		transferId, err := queries.InsertTransfer(ctx, sqlc.InsertTransferParams{
-			Recipient: int64(recipientAccountId),
-			Amount:    amountDecimal,
-		})
-		if err != nil {
-			return 0, fmt.Errorf("query InsertTransfer: %w", err)
-		}
+	err = model.LockBalances(ctx, currency, ids...)
+	if err != nil {
+		return 0, err
+	}
 
-		txId, err := queries.InsertTransaction(ctx, sqlc.InsertTransactionParams{
-			Account:  int64(senderAccountId),
-			Transfer: sql.NullInt64{Int64: transferId, Valid: true},
-		})
-		if err != nil {
-			return 0, fmt.Errorf("query InsertTransaction: %w", err)
-		}
+	err = model.checkLimits(ctx, LimitOperationTransfer, senderAccountId, c, amount)
+	if err != nil {
+		return 0, err
+	}
 
-		err = queries.DecrementBalance(ctx, sqlc.DecrementBalanceParams{
-			Account: int64(senderAccountId),
-			Amount:  amountDecimal,
-		})
-		if err != nil {
-			return 0, fmt.Errorf("query DecrementBalance: %w", err)
-		}
+	// 手数料も合わせて支払えなければ、transfer自体も行いません
+	err = model.HasEnough(ctx, senderAccountId, currency, amount.Add(fee.amount))
+	if err != nil {
+		return 0, err
+	}
 
-		err = queries.IncrementBalance(ctx, sqlc.IncrementBalanceParams{
-			Account: int64(recipientAccountId),
-			Amount:  amountDecimal,
-		})
-		if err != nil {
-			return 0, fmt.Errorf("query IncrementBalance: %w", err)
-		}
+	txId, err := model.recordTransfer(ctx, senderAccountId, recipientAccountId, currency, amount, memo, reference, metadata)
+	if err != nil {
+		return 0, err
+	}
+
+	err = model.chargeFee(ctx, txId, senderAccountId, currency, fee)
+	if err != nil {
+		return 0, err
+	}
+
+	return txId, nil
+}
+
+// 確認を済ませたtransferを記録し、残高を動かします
+// 残高の行はロックしておいてください
+func (model *Model) recordTransfer(ctx context.Context, senderAccountId int, recipientAccountId int, currency string, amount decimal.Decimal, memo *string, reference *Reference, metadata Metadata) (int, error) {
+	columns, err := newMemoColumns(memo, reference)
+	if err != nil {
+		return 0, err
+	}
+
+	encoded, err := encodeMetadata(metadata)
+	if err != nil {
+		return 0, err
+	}
+
+	amountDecimal := amount.String()
+	transferId, err := model.queries.InsertTransfer(ctx, sqlc.InsertTransferParams{
+		Recipient:     int64(recipientAccountId),
+		Amount:        amountDecimal,
+		Currency:      currency,
+		Memo:          columns.memo,
+		ReferenceType: columns.referenceType,
+		ReferenceID:   columns.referenceId,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("query InsertTransfer: %w", err)
+	}
 
-		return int(txId), nil
+	txId, err := model.queries.InsertTransaction(ctx, sqlc.InsertTransactionParams{
+		Account:  int64(senderAccountId),
+		Transfer: sql.NullInt64{Int64: transferId, Valid: true},
+		Metadata: encoded,
 	})
+	if err != nil {
+		return 0, fmt.Errorf("query InsertTransaction: %w", err)
+	}
+
+	err = model.post(ctx, txId,
+		posting{account: senderAccountId, currency: currency, amount: amount.Neg()},
+		posting{account: recipientAccountId, currency: currency, amount: amount},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(txId), nil
 }
@@ -0,0 +1,1940 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+// テストはG_TEST_DATABASE_URLで指定されたPostgreSQLに対して実行されます
+// スキーマは毎回作り直されるので、消えて困るデータベースは指定しないでください
+func setupModel(t *testing.T) *Model {
+	t.Helper()
+
+	uri := os.Getenv("G_TEST_DATABASE_URL")
+	if uri == "" {
+		t.Skip("G_TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", uri)
+	if err != nil {
+		t.Fatalf("open postgres: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(50)
+
+	schema, err := os.ReadFile("../sqlc/schema.sql")
+	if err != nil {
+		t.Fatalf("reading schema: %v", err)
+	}
+
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("applying schema: %v", err)
+	}
+
+	return NewModel(db)
+}
+
+func registerWithBalance(t *testing.T, model *Model, name string, amount int) int {
+	t.Helper()
+	ctx := context.Background()
+
+	id, err := model.Register(ctx, name, nil, nil)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	if amount > 0 {
+		_, err = model.Mint(ctx, id, DefaultCurrency, decimal.NewFromInt(int64(amount)), nil, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("Mint: %v", err)
+		}
+	}
+
+	return id
+}
+
+func TestConcurrentSpend(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+
+	const initial = 100
+	const attempts = 300
+	id := registerWithBalance(t, model, "spender", initial)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := model.Spend(ctx, id, DefaultCurrency, decimal.NewFromInt(1), nil, nil, nil, nil)
+			if errors.Is(err, DomainError) {
+				return
+			}
+			if err != nil {
+				t.Errorf("Spend: %v", err)
+				return
+			}
+
+			mu.Lock()
+			succeeded++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if succeeded != initial {
+		t.Errorf("expected %d spends to succeed, but %d succeeded", initial, succeeded)
+	}
+
+	balance, err := model.GetBalance(ctx, id, DefaultCurrency)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if !balance.IsZero() {
+		t.Errorf("expected balance to be 0, but was %s", balance)
+	}
+}
+
+func TestConcurrentTransferAndSpend(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+
+	const initial = 50
+	const accountCount = 5
+	const attempts = 500
+
+	ids := make([]int, accountCount)
+	for i := range ids {
+		ids[i] = registerWithBalance(t, model, "account", initial)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	spent := decimal.Zero
+	for i := 0; i < attempts; i++ {
+		sender := rand.Intn(accountCount)
+		amount := decimal.NewFromInt(rand.Int63n(10) + 1)
+		wg.Add(1)
+
+		if sender == accountCount-1 {
+			go func() {
+				defer wg.Done()
+
+				_, err := model.Spend(ctx, ids[sender], DefaultCurrency, amount, nil, nil, nil, nil)
+				if errors.Is(err, DomainError) {
+					return
+				}
+				if err != nil {
+					t.Errorf("Spend: %v", err)
+					return
+				}
+
+				mu.Lock()
+				spent = spent.Add(amount)
+				mu.Unlock()
+			}()
+			continue
+		}
+
+		recipient := sender + 1 + rand.Intn(accountCount-sender-1)
+		go func() {
+			defer wg.Done()
+
+			_, err := model.Transfer(ctx, ids[sender], ids[recipient], DefaultCurrency, amount, nil, nil, nil, nil)
+			if errors.Is(err, DomainError) {
+				return
+			}
+			if err != nil {
+				t.Errorf("Transfer: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := decimal.Zero
+	for _, id := range ids {
+		balance, err := model.GetBalance(ctx, id, DefaultCurrency)
+		if err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+		if balance.IsNegative() {
+			t.Errorf("balance of account %d went negative: %s", id, balance)
+		}
+		total = total.Add(balance)
+
+		err = model.VerifyBalance(ctx, id, DefaultCurrency)
+		if err != nil {
+			t.Errorf("VerifyBalance: %v", err)
+		}
+	}
+
+	if expected := decimal.NewFromInt(initial * accountCount).Sub(spent); !total.Equal(expected) {
+		t.Errorf("expected total balance to be %s, but was %s", expected, total)
+	}
+
+	// 発行した分と消費した分がシステム勘定に反対向きで記録されています
+	issued, err := model.GetPostedBalance(ctx, IssuanceAccountId, DefaultCurrency)
+	if err != nil {
+		t.Fatalf("GetPostedBalance: %v", err)
+	}
+	sunk, err := model.GetPostedBalance(ctx, SinkAccountId, DefaultCurrency)
+	if err != nil {
+		t.Fatalf("GetPostedBalance: %v", err)
+	}
+	if !issued.Equal(decimal.NewFromInt(-initial*accountCount)) || !sunk.Equal(spent) {
+		t.Errorf("expected issuance=%d and sink=%s, but were %s and %s", -initial*accountCount, spent, issued, sunk)
+	}
+}
+
+func TestConcurrentOpposingTransfers(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+
+	const initial = 100
+	const attempts = 400
+	a := registerWithBalance(t, model, "a", initial)
+	b := registerWithBalance(t, model, "b", initial)
+
+	// A→BとB→Aを同時に流しても、ロック順序が固定されていればデッドロックで失敗しません
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		sender, recipient := a, b
+		if i%2 == 1 {
+			sender, recipient = b, a
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := model.Transfer(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(rand.Int63n(5)+1), nil, nil, nil, nil)
+			if errors.Is(err, DomainError) {
+				return
+			}
+			if err != nil {
+				t.Errorf("Transfer: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	balanceA, err := model.GetBalance(ctx, a, DefaultCurrency)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	balanceB, err := model.GetBalance(ctx, b, DefaultCurrency)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+
+	if balanceA.IsNegative() || balanceB.IsNegative() {
+		t.Errorf("balance went negative: a=%s, b=%s", balanceA, balanceB)
+	}
+	if total := balanceA.Add(balanceB); !total.Equal(decimal.NewFromInt(initial * 2)) {
+		t.Errorf("expected total balance to be %d, but was %s", initial*2, total)
+	}
+}
+
+func TestCancelledContextAbortsTransaction(t *testing.T) {
+	model := setupModel(t)
+	id := registerWithBalance(t, model, "cancelled", 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := model.Spend(ctx, id, DefaultCurrency, decimal.NewFromInt(1), nil, nil, nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, but got %v", err)
+	}
+
+	balance, err := model.GetBalance(context.Background(), id, DefaultCurrency)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if !balance.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("expected balance to be 10, but was %s", balance)
+	}
+}
+
+func TestIdempotentSpend(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	id := registerWithBalance(t, model, "idempotent", 10)
+
+	idempotency := &Idempotency{Key: "retry", RequestHash: "hash"}
+	first, err := model.Spend(ctx, id, DefaultCurrency, decimal.NewFromInt(3), nil, nil, nil, idempotency)
+	if err != nil {
+		t.Fatalf("Spend: %v", err)
+	}
+
+	second, err := model.Spend(ctx, id, DefaultCurrency, decimal.NewFromInt(3), nil, nil, nil, idempotency)
+	if err != nil {
+		t.Fatalf("Spend: %v", err)
+	}
+	if first != second {
+		t.Errorf("expected replay to return transaction %d, but got %d", first, second)
+	}
+
+	_, err = model.Spend(ctx, id, DefaultCurrency, decimal.NewFromInt(4), nil, nil, nil, &Idempotency{Key: "retry", RequestHash: "other"})
+	if !errors.Is(err, ConflictError) {
+		t.Errorf("expected ConflictError, but got %v", err)
+	}
+
+	balance, err := model.GetBalance(ctx, id, DefaultCurrency)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if !balance.Equal(decimal.NewFromInt(7)) {
+		t.Errorf("expected balance to be 7, but was %s", balance)
+	}
+}
+
+func TestReconcileRepairsDrift(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	id := registerWithBalance(t, model, "drifted", 10)
+
+	err := model.queries.SetBalance(ctx, sqlc.SetBalanceParams{Account: int64(id), Currency: DefaultCurrency, Balance: "5"})
+	if err != nil {
+		t.Fatalf("SetBalance: %v", err)
+	}
+
+	reconciliation, err := model.Reconcile(ctx, false)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if len(reconciliation.Mismatches) != 1 || !reconciliation.Mismatches[0].Expected.Equal(decimal.NewFromInt(10)) {
+		t.Fatalf("expected a mismatch expecting 10, but got %+v", reconciliation.Mismatches)
+	}
+
+	reconciliation, err = model.Reconcile(ctx, true)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if !reconciliation.Repaired {
+		t.Errorf("expected reconciliation to be repaired")
+	}
+
+	balance, err := model.GetBalance(ctx, id, DefaultCurrency)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if !balance.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("expected balance to be repaired to 10, but was %s", balance)
+	}
+}
+
+func TestPartialReversal(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	id := registerWithBalance(t, model, "refunded", 10)
+
+	spendId, err := model.Spend(ctx, id, DefaultCurrency, decimal.NewFromInt(6), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Spend: %v", err)
+	}
+
+	partial := decimal.NewFromInt(4)
+	_, err = model.Reverse(ctx, id, spendId, &partial, nil)
+	if err != nil {
+		t.Fatalf("Reverse: %v", err)
+	}
+
+	// 残りの2だけが取り消されます
+	_, err = model.Reverse(ctx, id, spendId, nil, nil)
+	if err != nil {
+		t.Fatalf("Reverse: %v", err)
+	}
+
+	_, err = model.Reverse(ctx, id, spendId, nil, nil)
+	if !errors.Is(err, ConflictError) {
+		t.Errorf("expected ConflictError, but got %v", err)
+	}
+
+	balance, err := model.GetBalance(ctx, id, DefaultCurrency)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if !balance.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("expected balance to be 10, but was %s", balance)
+	}
+
+	reconciliation, err := model.Reconcile(ctx, false)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if len(reconciliation.Mismatches) != 0 {
+		t.Errorf("expected no mismatches, but got %+v", reconciliation.Mismatches)
+	}
+}
+
+func TestHoldAndCapture(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	id := registerWithBalance(t, model, "holder", 10)
+
+	hold, err := model.Hold(ctx, id, DefaultCurrency, decimal.NewFromInt(6), 60)
+	if err != nil {
+		t.Fatalf("Hold: %v", err)
+	}
+
+	_, err = model.Spend(ctx, id, DefaultCurrency, decimal.NewFromInt(5), nil, nil, nil, nil)
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError while funds are held, but got %v", err)
+	}
+
+	captured := decimal.NewFromInt(4)
+	_, err = model.Capture(ctx, id, hold.Id, &captured, nil)
+	if err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+
+	_, err = model.Release(ctx, id, hold.Id)
+	if !errors.Is(err, ConflictError) {
+		t.Errorf("expected ConflictError for captured hold, but got %v", err)
+	}
+
+	balance, available, err := model.GetAvailableBalance(ctx, id, DefaultCurrency)
+	if err != nil {
+		t.Fatalf("GetAvailableBalance: %v", err)
+	}
+	if !balance.Equal(decimal.NewFromInt(6)) || !available.Equal(decimal.NewFromInt(6)) {
+		t.Errorf("expected balance=6 and available=6, but were %s and %s", balance, available)
+	}
+}
+
+func TestTransactionsPagination(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	id := registerWithBalance(t, model, "paged", 100)
+	other := registerWithBalance(t, model, "counterparty", 0)
+
+	for i := 0; i < 4; i++ {
+		_, err := model.Spend(ctx, id, DefaultCurrency, decimal.NewFromInt(1), nil, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("Spend: %v", err)
+		}
+	}
+	_, err := model.Transfer(ctx, id, other, DefaultCurrency, decimal.NewFromInt(10), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+
+	// mint 1件、spend 4件、transfer 1件を2件ずつ辿ります
+	var pages [][]interface{}
+	var cursor string
+	for {
+		page, next, err := model.GetTransactions(ctx, id, TransactionFilter{Limit: 2, After: cursor})
+		if err != nil {
+			t.Fatalf("GetTransactions: %v", err)
+		}
+		pages = append(pages, page)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if len(pages) != 3 {
+		t.Fatalf("expected 3 pages, but got %d", len(pages))
+	}
+	if _, ok := pages[0][0].(Mint); !ok {
+		t.Errorf("expected first entry to be Mint, but was %T", pages[0][0])
+	}
+	if transfer, ok := pages[2][1].(Transfer); !ok {
+		t.Errorf("expected last entry to be Transfer, but was %T", pages[2][1])
+	} else if !transfer.Balance.Equal(decimal.NewFromInt(86)) {
+		// カーソルより前の履歴を集計しなくても、全履歴を反映した残高になります
+		t.Errorf("expected balance 86 after the transfer, but got %s", transfer.Balance)
+	}
+
+	outgoing := "outgoing"
+	minAmount := decimal.NewFromInt(5)
+	filtered, _, err := model.GetTransactions(ctx, id, TransactionFilter{Direction: &outgoing, MinAmount: &minAmount})
+	if err != nil {
+		t.Fatalf("GetTransactions: %v", err)
+	}
+	if len(filtered) != 1 {
+		t.Errorf("expected only the transfer, but got %+v", filtered)
+	}
+
+	incoming, _, err := model.GetTransactions(ctx, other, TransactionFilter{Counterparty: &id})
+	if err != nil {
+		t.Fatalf("GetTransactions: %v", err)
+	}
+	if len(incoming) != 1 {
+		t.Errorf("expected only the transfer, but got %+v", incoming)
+	}
+
+	_, _, err = model.GetTransactions(ctx, id, TransactionFilter{After: "!"})
+	if !errors.Is(err, ValidationError) {
+		t.Errorf("expected ValidationError, but got %v", err)
+	}
+
+	typo := "trasnfer"
+	_, _, err = model.GetTransactions(ctx, id, TransactionFilter{Type: &typo})
+	if !errors.Is(err, ValidationError) {
+		t.Errorf("expected ValidationError, but got %v", err)
+	}
+
+	_, _, err = model.GetTransactions(ctx, id, TransactionFilter{Direction: &typo})
+	if !errors.Is(err, ValidationError) {
+		t.Errorf("expected ValidationError, but got %v", err)
+	}
+}
+
+func TestTransactionsPerspective(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	sender := registerWithBalance(t, model, "sender", 30)
+	recipient := registerWithBalance(t, model, "recipient", 5)
+
+	_, err := model.Transfer(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(10), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+
+	sent, _, err := model.GetTransactions(ctx, sender, TransactionFilter{})
+	if err != nil {
+		t.Fatalf("GetTransactions: %v", err)
+	}
+	out := sent[len(sent)-1].(Transfer)
+	if out.Direction != Outgoing || !out.Delta.Equal(decimal.NewFromInt(-10)) || !out.Balance.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("unexpected entry for sender: %+v", out)
+	}
+	if out.Counterparty == nil || *out.Counterparty != recipient {
+		t.Errorf("expected counterparty to be %d, but was %v", recipient, out.Counterparty)
+	}
+
+	received, _, err := model.GetTransactions(ctx, recipient, TransactionFilter{})
+	if err != nil {
+		t.Fatalf("GetTransactions: %v", err)
+	}
+	in := received[len(received)-1].(Transfer)
+	if in.Direction != Incoming || !in.Delta.Equal(decimal.NewFromInt(10)) || !in.Balance.Equal(decimal.NewFromInt(15)) {
+		t.Errorf("unexpected entry for recipient: %+v", in)
+	}
+	if in.Counterparty == nil || *in.Counterparty != sender {
+		t.Errorf("expected counterparty to be %d, but was %v", sender, in.Counterparty)
+	}
+
+	// mintには相手方がいません
+	if minted := received[0].(Mint); minted.Counterparty != nil {
+		t.Errorf("expected no counterparty for mint, but was %d", *minted.Counterparty)
+	}
+}
+
+func TestFractionalAmounts(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	id := registerWithBalance(t, model, "fractional", 0)
+
+	_, err := model.Mint(ctx, id, DefaultCurrency, decimal.RequireFromString("10.5"), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Mint: %v", err)
+	}
+
+	_, err = model.Spend(ctx, id, DefaultCurrency, decimal.RequireFromString("0.25"), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Spend: %v", err)
+	}
+
+	balance, err := model.GetBalance(ctx, id, DefaultCurrency)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if !balance.Equal(decimal.RequireFromString("10.25")) {
+		t.Errorf("expected balance to be 10.25, but was %s", balance)
+	}
+
+	err = model.VerifyBalance(ctx, id, DefaultCurrency)
+	if err != nil {
+		t.Errorf("VerifyBalance: %v", err)
+	}
+}
+
+func TestValidateAmount(t *testing.T) {
+	currency := Currency{Code: DefaultCurrency, Name: "g", Scale: 2}
+
+	for _, tc := range []struct {
+		amount string
+		valid  bool
+	}{
+		{"1", true},
+		{"0.01", true},
+		{"10.50", true},
+		{"10.500", true},
+		{"0.001", false},
+		{"0", false},
+		{"-1", false},
+	} {
+		err := currency.ValidateAmount(decimal.RequireFromString(tc.amount))
+		if tc.valid && err != nil {
+			t.Errorf("expected %s to be valid, but got %v", tc.amount, err)
+		}
+		if !tc.valid && !errors.Is(err, ValidationError) {
+			t.Errorf("expected ValidationError for %s, but got %v", tc.amount, err)
+		}
+	}
+
+	err := Currency{Code: "POINT", Name: "point", Scale: 0}.ValidateAmount(decimal.RequireFromString("0.5"))
+	if !errors.Is(err, ValidationError) {
+		t.Errorf("expected ValidationError with scale 0, but got %v", err)
+	}
+}
+
+func TestMultipleCurrencies(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+
+	_, err := model.CreateCurrency(ctx, Currency{Code: "CREDIT", Name: "credit", Scale: 0})
+	if err != nil {
+		t.Fatalf("CreateCurrency: %v", err)
+	}
+
+	_, err = model.CreateCurrency(ctx, Currency{Code: "CREDIT", Name: "credit", Scale: 0})
+	if !errors.Is(err, ConflictError) {
+		t.Errorf("expected ConflictError for duplicated currency, but got %v", err)
+	}
+
+	sender := registerWithBalance(t, model, "sender", 0)
+	recipient := registerWithBalance(t, model, "recipient", 0)
+
+	_, err = model.Mint(ctx, sender, "CREDIT", decimal.NewFromInt(10), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Mint: %v", err)
+	}
+
+	_, err = model.Mint(ctx, sender, "CREDIT", decimal.RequireFromString("0.5"), nil, nil, nil, nil)
+	if !errors.Is(err, ValidationError) {
+		t.Errorf("expected ValidationError for fractional credit, but got %v", err)
+	}
+
+	_, err = model.Mint(ctx, sender, "UNKNOWN", decimal.NewFromInt(1), nil, nil, nil, nil)
+	if !errors.Is(err, NotFoundError) {
+		t.Errorf("expected NotFoundError for unknown currency, but got %v", err)
+	}
+
+	// creditを持っていても、既定の通貨の残高がなければ送れません
+	_, err = model.Transfer(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(1), nil, nil, nil, nil)
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError for transfer without balance, but got %v", err)
+	}
+
+	_, err = model.Transfer(ctx, sender, recipient, "CREDIT", decimal.NewFromInt(4), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+
+	balances, err := model.GetBalances(ctx, sender)
+	if err != nil {
+		t.Fatalf("GetBalances: %v", err)
+	}
+	// 失敗した送金は巻き戻されるので、既定の通貨の残高の行は作られていません
+	if len(balances) != 1 || balances[0].Currency != "CREDIT" || !balances[0].Balance.Equal(decimal.NewFromInt(6)) {
+		t.Errorf("expected only 6 CREDIT, but got %+v", balances)
+	}
+
+	balance, err := model.GetBalance(ctx, recipient, DefaultCurrency)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if !balance.IsZero() {
+		t.Errorf("expected no balance in %s, but got %s", DefaultCurrency, balance)
+	}
+
+	for _, id := range []int{sender, recipient} {
+		err = model.VerifyBalance(ctx, id, "CREDIT")
+		if err != nil {
+			t.Errorf("VerifyBalance: %v", err)
+		}
+	}
+}
+
+func TestConversion(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+
+	_, err := model.CreateCurrency(ctx, Currency{Code: "CREDIT", Name: "credit", Scale: 0})
+	if err != nil {
+		t.Fatalf("CreateCurrency: %v", err)
+	}
+
+	_, err = model.PublishRate(ctx, Rate{Source: DefaultCurrency, Target: "CREDIT", Rate: decimal.RequireFromString("1.5")})
+	if err != nil {
+		t.Fatalf("PublishRate: %v", err)
+	}
+
+	id := registerWithBalance(t, model, "converter", 10)
+
+	quote, err := model.Quote(ctx, id, DefaultCurrency, "CREDIT", 60)
+	if err != nil {
+		t.Fatalf("Quote: %v", err)
+	}
+
+	// 後から公開されたレートは、固定したquoteには影響しません
+	_, err = model.PublishRate(ctx, Rate{Source: DefaultCurrency, Target: "CREDIT", Rate: decimal.NewFromInt(100)})
+	if err != nil {
+		t.Fatalf("PublishRate: %v", err)
+	}
+
+	// 3.33 * 1.5 = 4.995は、creditの桁数に切り捨てて4になります
+	txId, err := model.Convert(ctx, id, id, DefaultCurrency, "CREDIT", decimal.RequireFromString("3.33"), &quote.Id, nil)
+	if err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+
+	_, err = model.Convert(ctx, id, id, DefaultCurrency, "CREDIT", decimal.NewFromInt(1), &quote.Id, nil)
+	if !errors.Is(err, ConflictError) {
+		t.Errorf("expected ConflictError for used quote, but got %v", err)
+	}
+
+	_, err = model.Reverse(ctx, id, txId, nil, nil)
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError for reversing conversion, but got %v", err)
+	}
+
+	for currency, expected := range map[string]string{DefaultCurrency: "6.67", "CREDIT": "4"} {
+		balance, err := model.GetBalance(ctx, id, currency)
+		if err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+		if !balance.Equal(decimal.RequireFromString(expected)) {
+			t.Errorf("expected %s balance %s, but got %s", currency, expected, balance)
+		}
+
+		err = model.VerifyBalance(ctx, id, currency)
+		if err != nil {
+			t.Errorf("VerifyBalance: %v", err)
+		}
+	}
+
+	// conversionは換算元と換算先の2行として、1件ずつでも取りこぼさずに辿れます
+	var legs []Conversion
+	var cursor string
+	for {
+		page, next, err := model.GetTransactions(ctx, id, TransactionFilter{Limit: 1, After: cursor})
+		if err != nil {
+			t.Fatalf("GetTransactions: %v", err)
+		}
+		for _, entry := range page {
+			if c, ok := entry.(Conversion); ok {
+				legs = append(legs, c)
+			}
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if len(legs) != 2 {
+		t.Fatalf("expected 2 legs of conversion, but got %+v", legs)
+	}
+	for _, leg := range legs {
+		if !leg.Rate.Equal(decimal.RequireFromString("1.5")) {
+			t.Errorf("expected recorded rate 1.5, but got %s", leg.Rate)
+		}
+		switch leg.Currency {
+		case DefaultCurrency:
+			if leg.Direction != Outgoing || !leg.Delta.Equal(decimal.RequireFromString("-3.33")) {
+				t.Errorf("unexpected source leg %+v", leg)
+			}
+		case "CREDIT":
+			if leg.Direction != Incoming || !leg.Delta.Equal(decimal.NewFromInt(4)) {
+				t.Errorf("unexpected target leg %+v", leg)
+			}
+		}
+	}
+}
+
+func TestAccountLifecycle(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	id := registerWithBalance(t, model, "lifecycle", 10)
+	other := registerWithBalance(t, model, "other", 10)
+
+	_, err := model.Freeze(ctx, id, "suspicious activity")
+	if err != nil {
+		t.Fatalf("Freeze: %v", err)
+	}
+
+	_, err = model.Freeze(ctx, id, "again")
+	if !errors.Is(err, ConflictError) {
+		t.Errorf("expected ConflictError for freezing twice, but got %v", err)
+	}
+
+	_, err = model.Spend(ctx, id, DefaultCurrency, decimal.NewFromInt(1), nil, nil, nil, nil)
+	if !errors.Is(err, AccountFrozenError) {
+		t.Errorf("expected AccountFrozenError for spend, but got %v", err)
+	}
+
+	_, err = model.Transfer(ctx, other, id, DefaultCurrency, decimal.NewFromInt(1), nil, nil, nil, nil)
+	if !errors.Is(err, AccountFrozenError) {
+		t.Errorf("expected AccountFrozenError for incoming transfer, but got %v", err)
+	}
+
+	_, err = model.Unfreeze(ctx, id, "cleared")
+	if err != nil {
+		t.Fatalf("Unfreeze: %v", err)
+	}
+
+	_, err = model.Spend(ctx, id, DefaultCurrency, decimal.NewFromInt(1), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Spend: %v", err)
+	}
+
+	_, err = model.Close(ctx, id, "requested by user", nil)
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError for closing with balance, but got %v", err)
+	}
+
+	_, err = model.Close(ctx, id, "requested by user", &other)
+	if err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	_, err = model.Mint(ctx, id, DefaultCurrency, decimal.NewFromInt(1), nil, nil, nil, nil)
+	if !errors.Is(err, AccountClosedError) {
+		t.Errorf("expected AccountClosedError for mint, but got %v", err)
+	}
+
+	for account, expected := range map[int]int64{id: 0, other: 19} {
+		balance, err := model.GetBalance(ctx, account, DefaultCurrency)
+		if err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+		if !balance.Equal(decimal.NewFromInt(expected)) {
+			t.Errorf("expected balance of %d to be %d, but got %s", account, expected, balance)
+		}
+	}
+
+	changes, err := model.GetStatusChanges(ctx, id)
+	if err != nil {
+		t.Fatalf("GetStatusChanges: %v", err)
+	}
+	if len(changes) != 3 || changes[2].Previous != "active" || changes[2].Status != "closed" {
+		t.Errorf("unexpected status changes %+v", changes)
+	}
+}
+
+func TestAccountManagement(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	prefix := fmt.Sprintf("management-%d-", time.Now().UnixNano())
+
+	ids := []int{}
+	for i := 0; i < 3; i++ {
+		id, err := model.Register(ctx, fmt.Sprintf("%s%d", prefix, i), nil, nil)
+		if err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	before, err := model.GetAccount(ctx, ids[0])
+	if err != nil {
+		t.Fatalf("GetAccount: %v", err)
+	}
+
+	renamed, err := model.Rename(ctx, ids[0], "renamed")
+	if err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if renamed.Name != "renamed" || !renamed.UpdatedAt.After(before.UpdatedAt) {
+		t.Errorf("expected name and updated_at to be updated, but got %+v", renamed)
+	}
+
+	_, err = model.Rename(ctx, ids[1], "")
+	if !errors.Is(err, ValidationError) {
+		t.Errorf("expected ValidationError for empty name, but got %v", err)
+	}
+
+	found := []int{}
+	after := ""
+	for {
+		accounts, next, err := model.GetAccounts(ctx, AccountFilter{Limit: 1, After: after, NamePrefix: &prefix})
+		if err != nil {
+			t.Fatalf("GetAccounts: %v", err)
+		}
+		for _, account := range accounts {
+			found = append(found, account.Id)
+		}
+		if next == "" {
+			break
+		}
+		after = next
+	}
+
+	if len(found) != 2 || found[0] != ids[1] || found[1] != ids[2] {
+		t.Errorf("expected accounts %v, but got %v", ids[1:], found)
+	}
+}
+
+func TestValidateMetadata(t *testing.T) {
+	tooMany := Metadata{}
+	for i := 0; i <= MaxMetadataKeys; i++ {
+		tooMany[fmt.Sprintf("key%d", i)] = "value"
+	}
+
+	for _, tc := range []struct {
+		name     string
+		metadata Metadata
+		valid    bool
+	}{
+		{"nil", nil, true},
+		{"order id", Metadata{"order_id": "1234"}, true},
+		{"empty key", Metadata{"": "value"}, false},
+		{"long key", Metadata{strings.Repeat("k", MaxMetadataKeyLength+1): "value"}, false},
+		{"long value", Metadata{"key": strings.Repeat("v", MaxMetadataValueLength+1)}, false},
+		{"too many keys", tooMany, false},
+	} {
+		err := validateMetadata(tc.metadata)
+		if tc.valid && err != nil {
+			t.Errorf("expected %s to be valid, but got %v", tc.name, err)
+		}
+		if !tc.valid && !errors.Is(err, ValidationError) {
+			t.Errorf("expected ValidationError for %s, but got %v", tc.name, err)
+		}
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+
+	externalId := "user-1"
+	id, err := model.Register(ctx, "metadata", &externalId, Metadata{"plan": "premium"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	_, err = model.Register(ctx, "duplicated", &externalId, nil)
+	if !errors.Is(err, ConflictError) {
+		t.Errorf("expected ConflictError for duplicated external_id, but got %v", err)
+	}
+
+	accounts, _, err := model.GetAccounts(ctx, AccountFilter{Metadata: Metadata{"plan": "premium"}})
+	if err != nil {
+		t.Fatalf("GetAccounts: %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].Id != id || *accounts[0].ExternalId != externalId {
+		t.Errorf("expected only account %d, but got %+v", id, accounts)
+	}
+
+	_, err = model.Mint(ctx, id, DefaultCurrency, decimal.NewFromInt(10), nil, nil, Metadata{"order_id": "1", "tag": "campaign"}, nil)
+	if err != nil {
+		t.Fatalf("Mint: %v", err)
+	}
+	_, err = model.Spend(ctx, id, DefaultCurrency, decimal.NewFromInt(3), nil, nil, Metadata{"order_id": "2"}, nil)
+	if err != nil {
+		t.Fatalf("Spend: %v", err)
+	}
+
+	transactions, _, err := model.GetTransactions(ctx, id, TransactionFilter{Metadata: Metadata{"order_id": "2"}})
+	if err != nil {
+		t.Fatalf("GetTransactions: %v", err)
+	}
+	if len(transactions) != 1 {
+		t.Fatalf("expected 1 transaction, but got %d", len(transactions))
+	}
+	spend, ok := transactions[0].(Spend)
+	if !ok || spend.Metadata["order_id"] != "2" {
+		t.Errorf("expected spend with order_id 2, but got %+v", transactions[0])
+	}
+}
+
+func TestMemo(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	sender := registerWithBalance(t, model, "sender", 10)
+	recipient := registerWithBalance(t, model, "recipient", 0)
+
+	memo := "Lunch 50% split"
+	reference := &Reference{Type: "invoice", Id: "INV-1"}
+	_, err := model.Transfer(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(3), &memo, reference, nil, nil)
+	if err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+
+	empty := ""
+	_, err = model.Spend(ctx, sender, DefaultCurrency, decimal.NewFromInt(1), &empty, nil, nil, nil)
+	if !errors.Is(err, ValidationError) {
+		t.Errorf("expected ValidationError for empty memo, but got %v", err)
+	}
+
+	for _, tc := range []struct {
+		search string
+		found  int
+	}{
+		{"lunch", 1},
+		{"50%", 1},
+		{"5_%", 0},
+		{"dinner", 0},
+	} {
+		transactions, _, err := model.GetTransactions(ctx, recipient, TransactionFilter{Memo: &tc.search})
+		if err != nil {
+			t.Fatalf("GetTransactions: %v", err)
+		}
+		if len(transactions) != tc.found {
+			t.Errorf("expected %d transactions for %q, but got %d", tc.found, tc.search, len(transactions))
+			continue
+		}
+		if tc.found == 0 {
+			continue
+		}
+
+		transfer, ok := transactions[0].(Transfer)
+		if !ok || transfer.Memo == nil || *transfer.Memo != memo || transfer.Reference == nil || *transfer.Reference != *reference {
+			t.Errorf("expected transfer with memo and reference, but got %+v", transactions[0])
+		}
+	}
+}
+
+func TestBatchTransfer(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	sender := registerWithBalance(t, model, "payroll", 10)
+	first := registerWithBalance(t, model, "first", 0)
+	second := registerWithBalance(t, model, "second", 0)
+
+	memo := "salary"
+	_, err := model.BatchTransfer(ctx, sender, DefaultCurrency, []TransferItem{
+		{Recipient: first, Amount: decimal.NewFromInt(3), Memo: &memo},
+		{Recipient: sender, Amount: decimal.NewFromInt(1)},
+		{Recipient: second, Amount: decimal.RequireFromString("0.001")},
+	})
+	var batchErr *BatchTransferError
+	if !errors.As(err, &batchErr) || len(batchErr.Items) != 2 || batchErr.Items[0].Index != 1 || batchErr.Items[1].Index != 2 {
+		t.Fatalf("expected BatchTransferError for items 1 and 2, but got %v", err)
+	}
+
+	_, err = model.BatchTransfer(ctx, sender, DefaultCurrency, []TransferItem{
+		{Recipient: first, Amount: decimal.NewFromInt(6)},
+		{Recipient: second, Amount: decimal.NewFromInt(6)},
+	})
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError for insufficient total, but got %v", err)
+	}
+
+	txIds, err := model.BatchTransfer(ctx, sender, DefaultCurrency, []TransferItem{
+		{Recipient: first, Amount: decimal.NewFromInt(3), Memo: &memo},
+		{Recipient: second, Amount: decimal.NewFromInt(4), Memo: &memo},
+	})
+	if err != nil {
+		t.Fatalf("BatchTransfer: %v", err)
+	}
+	if len(txIds) != 2 {
+		t.Errorf("expected 2 transactions, but got %v", txIds)
+	}
+
+	for account, expected := range map[int]int64{sender: 3, first: 3, second: 4} {
+		balance, err := model.GetBalance(ctx, account, DefaultCurrency)
+		if err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+		if !balance.Equal(decimal.NewFromInt(expected)) {
+			t.Errorf("expected balance of %d to be %d, but got %s", account, expected, balance)
+		}
+	}
+}
+
+func TestPostJournal(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	buyer := registerWithBalance(t, model, "buyer", 10)
+	seller := registerWithBalance(t, model, "seller", 0)
+	platform := registerWithBalance(t, model, "platform", 0)
+
+	_, err := model.PostJournal(ctx, []Leg{
+		{Account: buyer, Currency: DefaultCurrency, Amount: decimal.NewFromInt(-10)},
+		{Account: seller, Currency: DefaultCurrency, Amount: decimal.NewFromInt(9)},
+	}, nil, nil, nil)
+	if !errors.Is(err, ValidationError) {
+		t.Errorf("expected ValidationError for unbalanced legs, but got %v", err)
+	}
+
+	_, err = model.PostJournal(ctx, []Leg{
+		{Account: buyer, Currency: DefaultCurrency, Amount: decimal.NewFromInt(-11)},
+		{Account: seller, Currency: DefaultCurrency, Amount: decimal.NewFromInt(11)},
+	}, nil, nil, nil)
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError for insufficient balance, but got %v", err)
+	}
+
+	memo := "order 1234"
+	txId, err := model.PostJournal(ctx, []Leg{
+		{Account: buyer, Currency: DefaultCurrency, Amount: decimal.NewFromInt(-10)},
+		{Account: seller, Currency: DefaultCurrency, Amount: decimal.NewFromInt(9)},
+		{Account: platform, Currency: DefaultCurrency, Amount: decimal.NewFromInt(1)},
+	}, &memo, nil, nil)
+	if err != nil {
+		t.Fatalf("PostJournal: %v", err)
+	}
+
+	for account, expected := range map[int]int64{buyer: 0, seller: 9, platform: 1} {
+		balance, err := model.GetBalance(ctx, account, DefaultCurrency)
+		if err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+		if !balance.Equal(decimal.NewFromInt(expected)) {
+			t.Errorf("expected balance of %d to be %d, but got %s", account, expected, balance)
+		}
+
+		journalType := "journal"
+		transactions, _, err := model.GetTransactions(ctx, account, TransactionFilter{Type: &journalType})
+		if err != nil {
+			t.Fatalf("GetTransactions: %v", err)
+		}
+		if len(transactions) != 1 || transactions[0].(Journal).Id != txId {
+			t.Errorf("expected journal %d in history of %d, but got %+v", txId, account, transactions)
+		}
+	}
+
+	reconciliation, err := model.Reconcile(ctx, false)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if len(reconciliation.Mismatches) != 0 {
+		t.Errorf("expected no mismatches, but got %+v", reconciliation.Mismatches)
+	}
+}
+
+func TestFeeScheduleCalculate(t *testing.T) {
+	min := decimal.RequireFromString("0.5")
+	max := decimal.NewFromInt(3)
+	schedule := FeeSchedule{
+		Flat:       decimal.RequireFromString("0.1"),
+		Percentage: decimal.RequireFromString("1.5"),
+		Min:        &min,
+		Max:        &max,
+	}
+
+	for _, tc := range []struct {
+		amount   string
+		expected string
+	}{
+		{"10", "0.5"},
+		{"100", "1.6"},
+		{"33.33", "0.59"},
+		{"1000", "3"},
+	} {
+		fee := schedule.Calculate(decimal.RequireFromString(tc.amount), 2)
+		if !fee.Equal(decimal.RequireFromString(tc.expected)) {
+			t.Errorf("expected fee for %s to be %s, but got %s", tc.amount, tc.expected, fee)
+		}
+	}
+}
+
+func TestFee(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	sender := registerWithBalance(t, model, "sender", 10)
+	recipient := registerWithBalance(t, model, "recipient", 0)
+	operator := registerWithBalance(t, model, "operator", 0)
+
+	_, err := model.SetFeeSchedule(ctx, FeeSchedule{
+		Operation:  FeeOperationTransfer,
+		Currency:   DefaultCurrency,
+		Percentage: decimal.NewFromInt(101),
+		Account:    operator,
+	})
+	if !errors.Is(err, ValidationError) {
+		t.Errorf("expected ValidationError for percentage over 100, but got %v", err)
+	}
+
+	for _, operation := range []FeeOperation{FeeOperationTransfer, FeeOperationSpend} {
+		_, err = model.SetFeeSchedule(ctx, FeeSchedule{
+			Operation:  operation,
+			Currency:   DefaultCurrency,
+			Flat:       decimal.NewFromInt(1),
+			Percentage: decimal.NewFromInt(10),
+			Account:    operator,
+		})
+		if err != nil {
+			t.Fatalf("SetFeeSchedule: %v", err)
+		}
+	}
+
+	_, err = model.Transfer(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(9), nil, nil, nil, nil)
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError when fee exceeds balance, but got %v", err)
+	}
+
+	txId, err := model.Transfer(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(5), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+
+	_, err = model.Spend(ctx, sender, DefaultCurrency, decimal.NewFromInt(2), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Spend: %v", err)
+	}
+
+	// transferの手数料は1 + 0.5、spendの手数料は1 + 0.2です
+	for account, expected := range map[int]string{sender: "0.3", recipient: "5", operator: "2.7"} {
+		balance, err := model.GetBalance(ctx, account, DefaultCurrency)
+		if err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+		if !balance.Equal(decimal.RequireFromString(expected)) {
+			t.Errorf("expected balance of %d to be %s, but got %s", account, expected, balance)
+		}
+	}
+
+	feeType := "fee"
+	transactions, _, err := model.GetTransactions(ctx, sender, TransactionFilter{Type: &feeType})
+	if err != nil {
+		t.Fatalf("GetTransactions: %v", err)
+	}
+	if len(transactions) != 2 || transactions[0].(Fee).Original != txId || transactions[0].(Fee).Recipient != operator {
+		t.Fatalf("expected fee for transaction %d, but got %+v", txId, transactions)
+	}
+
+	_, err = model.Reverse(ctx, sender, transactions[0].(Fee).Id, nil, nil)
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError for reversing fee, but got %v", err)
+	}
+
+	err = model.DeleteFeeSchedule(ctx, FeeOperationSpend, DefaultCurrency)
+	if err != nil {
+		t.Fatalf("DeleteFeeSchedule: %v", err)
+	}
+	err = model.DeleteFeeSchedule(ctx, FeeOperationSpend, DefaultCurrency)
+	if !errors.Is(err, NotFoundError) {
+		t.Errorf("expected NotFoundError for deleted schedule, but got %v", err)
+	}
+
+	_, err = model.Spend(ctx, sender, DefaultCurrency, decimal.RequireFromString("0.3"), nil, nil, nil, nil)
+	if err != nil {
+		t.Errorf("expected spend without fee after deletion, but got %v", err)
+	}
+
+	reconciliation, err := model.Reconcile(ctx, false)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if len(reconciliation.Mismatches) != 0 {
+		t.Errorf("expected no mismatches, but got %+v", reconciliation.Mismatches)
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	start := time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		frequency sqlc.ScheduleFrequency
+		after     time.Time
+		expected  time.Time
+	}{
+		{sqlc.ScheduleFrequencyDaily, start, time.Date(2023, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{sqlc.ScheduleFrequencyDaily, time.Date(2023, 2, 10, 12, 0, 0, 0, time.UTC), time.Date(2023, 2, 11, 9, 0, 0, 0, time.UTC)},
+		{sqlc.ScheduleFrequencyWeekly, start, time.Date(2023, 2, 7, 9, 0, 0, 0, time.UTC)},
+		{sqlc.ScheduleFrequencyMonthly, start, time.Date(2023, 2, 28, 9, 0, 0, 0, time.UTC)},
+		{sqlc.ScheduleFrequencyMonthly, time.Date(2023, 2, 28, 9, 0, 0, 0, time.UTC), time.Date(2023, 3, 31, 9, 0, 0, 0, time.UTC)},
+		{sqlc.ScheduleFrequencyMonthly, time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC), time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC)},
+		{sqlc.ScheduleFrequencyOnce, start.Add(-time.Hour), start},
+	} {
+		next, ok := nextOccurrence(tc.frequency, start, tc.after)
+		if !ok || !next.Equal(tc.expected) {
+			t.Errorf("expected %s after %s to be %s, but got %s", tc.frequency, tc.after, tc.expected, next)
+		}
+	}
+
+	_, ok := nextOccurrence(sqlc.ScheduleFrequencyOnce, start, start)
+	if ok {
+		t.Errorf("expected no occurrence after once schedule")
+	}
+}
+
+func TestSchedules(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	sender := registerWithBalance(t, model, "subscriber", 10)
+	recipient := registerWithBalance(t, model, "service", 0)
+
+	_, err := model.CreateSchedule(ctx, sender, sender, DefaultCurrency, decimal.NewFromInt(4), nil, "daily", nil, nil)
+	if !errors.Is(err, ValidationError) {
+		t.Errorf("expected ValidationError for schedule to self, but got %v", err)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	_, err = model.CreateSchedule(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(4), nil, "daily", nil, &past)
+	if !errors.Is(err, ValidationError) {
+		t.Errorf("expected ValidationError for ends_at before now, but got %v", err)
+	}
+
+	// 直ちに最初の回を実行し、その後は1日ごとに実行します
+	memo := "subscription"
+	schedule, err := model.CreateSchedule(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(4), &memo, "daily", nil, nil)
+	if err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+
+	later := time.Now().Add(time.Hour)
+	once, err := model.CreateSchedule(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(1), nil, "once", &later, nil)
+	if err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+
+	count, err := model.RunDueSchedules(ctx)
+	if err != nil {
+		t.Fatalf("RunDueSchedules: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 run, but got %d", count)
+	}
+
+	count, err = model.RunDueSchedules(ctx)
+	if err != nil || count != 0 {
+		t.Errorf("expected no more runs, but got %d, %v", count, err)
+	}
+
+	schedule, err = model.GetSchedule(ctx, sender, schedule.Id)
+	if err != nil {
+		t.Fatalf("GetSchedule: %v", err)
+	}
+	if !schedule.NextRunAt.Equal(schedule.StartsAt.Add(24 * time.Hour)) {
+		t.Errorf("expected next run a day after %s, but got %s", schedule.StartsAt, schedule.NextRunAt)
+	}
+
+	// 残高不足の回は失敗として記録し、次の回へ進みます
+	_, err = model.Spend(ctx, sender, DefaultCurrency, decimal.NewFromInt(5), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Spend: %v", err)
+	}
+	_, err = model.db.Exec("UPDATE schedules SET next_run_at = now() WHERE id = $1", schedule.Id)
+	if err != nil {
+		t.Fatalf("moving next_run_at: %v", err)
+	}
+	_, err = model.RunDueSchedules(ctx)
+	if err != nil {
+		t.Fatalf("RunDueSchedules: %v", err)
+	}
+
+	runs, err := model.GetScheduleRuns(ctx, sender, schedule.Id, DefaultScheduleRunsLimit)
+	if err != nil {
+		t.Fatalf("GetScheduleRuns: %v", err)
+	}
+	if len(runs) != 2 || runs[0].Status != "failed" || runs[0].Error == nil || runs[1].Status != "succeeded" || runs[1].Transaction == nil {
+		t.Fatalf("expected failed run after succeeded run, but got %+v", runs)
+	}
+
+	// 再実行しても同じ回の送金は一度だけです
+	_, err = model.executeSchedule(ctx, sqlc.Schedule{
+		ID:        int64(schedule.Id),
+		Account:   int64(sender),
+		Recipient: int64(recipient),
+		Currency:  DefaultCurrency,
+		Amount:    "4",
+		NextRunAt: runs[1].DueAt,
+	})
+	if err != nil {
+		t.Fatalf("executeSchedule: %v", err)
+	}
+
+	for account, expected := range map[int]int64{sender: 1, recipient: 4} {
+		balance, err := model.GetBalance(ctx, account, DefaultCurrency)
+		if err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+		if !balance.Equal(decimal.NewFromInt(expected)) {
+			t.Errorf("expected balance of %d to be %d, but got %s", account, expected, balance)
+		}
+	}
+
+	paused, err := model.PauseSchedule(ctx, sender, schedule.Id)
+	if err != nil || paused.Status != "paused" {
+		t.Fatalf("PauseSchedule: %+v, %v", paused, err)
+	}
+	_, err = model.PauseSchedule(ctx, sender, schedule.Id)
+	if !errors.Is(err, ConflictError) {
+		t.Errorf("expected ConflictError for pausing paused schedule, but got %v", err)
+	}
+
+	resumed, err := model.ResumeSchedule(ctx, sender, schedule.Id)
+	if err != nil || resumed.Status != "active" {
+		t.Fatalf("ResumeSchedule: %+v, %v", resumed, err)
+	}
+
+	// 停止している間に実行時刻を過ぎたonceの予約も、再開すると実行します
+	pending, err := model.CreateSchedule(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(1), nil, "once", &later, nil)
+	if err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+	_, err = model.PauseSchedule(ctx, sender, pending.Id)
+	if err != nil {
+		t.Fatalf("PauseSchedule: %v", err)
+	}
+	_, err = model.db.Exec("UPDATE schedules SET starts_at = now(), next_run_at = now() WHERE id = $1", pending.Id)
+	if err != nil {
+		t.Fatalf("moving next_run_at: %v", err)
+	}
+	resumed, err = model.ResumeSchedule(ctx, sender, pending.Id)
+	if err != nil || resumed.Status != "active" {
+		t.Fatalf("ResumeSchedule: %+v, %v", resumed, err)
+	}
+	count, err = model.RunDueSchedules(ctx)
+	if err != nil || count != 1 {
+		t.Errorf("expected the resumed once schedule to run, but got %d, %v", count, err)
+	}
+	runs, err = model.GetScheduleRuns(ctx, sender, pending.Id, DefaultScheduleRunsLimit)
+	if err != nil || len(runs) != 1 || runs[0].Status != "succeeded" {
+		t.Errorf("expected a succeeded run, but got %+v, %v", runs, err)
+	}
+
+	cancelled, err := model.CancelSchedule(ctx, sender, once.Id)
+	if err != nil || cancelled.Status != "cancelled" {
+		t.Fatalf("CancelSchedule: %+v, %v", cancelled, err)
+	}
+	_, err = model.ResumeSchedule(ctx, sender, once.Id)
+	if !errors.Is(err, ConflictError) {
+		t.Errorf("expected ConflictError for resuming cancelled schedule, but got %v", err)
+	}
+}
+
+func TestPaymentRequests(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	requester := registerWithBalance(t, model, "requester", 0)
+	payer := registerWithBalance(t, model, "payer", 10)
+
+	memo := "lunch"
+	request, err := model.CreatePaymentRequest(ctx, requester, payer, DefaultCurrency, decimal.NewFromInt(4), &memo, 60)
+	if err != nil {
+		t.Fatalf("CreatePaymentRequest: %v", err)
+	}
+	if request.Status != Pending {
+		t.Errorf("expected pending, but was %s", request.Status)
+	}
+
+	_, err = model.AcceptPaymentRequest(ctx, requester, request.Id)
+	if !errors.Is(err, NotFoundError) {
+		t.Errorf("expected NotFoundError when requester accepts, but got %v", err)
+	}
+
+	paid, err := model.AcceptPaymentRequest(ctx, payer, request.Id)
+	if err != nil {
+		t.Fatalf("AcceptPaymentRequest: %v", err)
+	}
+	if paid.Status != Paid || paid.Transaction == nil {
+		t.Errorf("expected paid with transaction, but was %s and %v", paid.Status, paid.Transaction)
+	}
+	if !paid.UpdatedAt.After(request.UpdatedAt) {
+		t.Errorf("expected updated_at to advance from %s, but got %s", request.UpdatedAt, paid.UpdatedAt)
+	}
+
+	_, err = model.DeclinePaymentRequest(ctx, payer, request.Id)
+	if !errors.Is(err, ConflictError) {
+		t.Errorf("expected ConflictError for paid request, but got %v", err)
+	}
+
+	balance, err := model.GetBalance(ctx, requester, DefaultCurrency)
+	if err != nil {
+		t.Fatalf("GetBalance: %v", err)
+	}
+	if !balance.Equal(decimal.NewFromInt(4)) {
+		t.Errorf("expected requester balance=4, but was %s", balance)
+	}
+
+	declined, err := model.CreatePaymentRequest(ctx, requester, payer, DefaultCurrency, decimal.NewFromInt(1), nil, 60)
+	if err != nil {
+		t.Fatalf("CreatePaymentRequest: %v", err)
+	}
+	_, err = model.DeclinePaymentRequest(ctx, payer, declined.Id)
+	if err != nil {
+		t.Fatalf("DeclinePaymentRequest: %v", err)
+	}
+
+	incoming := Incoming
+	requests, err := model.GetPaymentRequests(ctx, payer, &incoming, nil, DefaultPaymentRequestsLimit)
+	if err != nil {
+		t.Fatalf("GetPaymentRequests: %v", err)
+	}
+	if len(requests) != 2 || requests[0].Status != Declined {
+		t.Errorf("expected 2 incoming requests with the latest declined, but got %v", requests)
+	}
+
+	outgoing := Outgoing
+	requests, err = model.GetPaymentRequests(ctx, payer, &outgoing, nil, DefaultPaymentRequestsLimit)
+	if err != nil {
+		t.Fatalf("GetPaymentRequests: %v", err)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected no outgoing requests for payer, but got %d", len(requests))
+	}
+}
+
+func TestEscrow(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	sender := registerWithBalance(t, model, "buyer", 10)
+	recipient := registerWithBalance(t, model, "seller", 0)
+
+	escrow, err := model.CreateEscrow(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(6), nil, 60)
+	if err != nil {
+		t.Fatalf("CreateEscrow: %v", err)
+	}
+
+	escrow, err = model.ConfirmEscrow(ctx, sender, escrow.Id)
+	if err != nil {
+		t.Fatalf("ConfirmEscrow: %v", err)
+	}
+	if escrow.Status != Funded || escrow.SenderConfirmedAt == nil {
+		t.Errorf("expected funded escrow confirmed by sender, but was %s", escrow.Status)
+	}
+	if escrow.SenderConfirmedAt != nil && escrow.SenderConfirmedAt.After(escrow.ExpiresAt) {
+		t.Errorf("expected confirmation %s before expiry %s", escrow.SenderConfirmedAt, escrow.ExpiresAt)
+	}
+
+	escrow, err = model.ConfirmEscrow(ctx, recipient, escrow.Id)
+	if err != nil {
+		t.Fatalf("ConfirmEscrow: %v", err)
+	}
+	if escrow.Status != Released {
+		t.Errorf("expected released after both confirmed, but was %s", escrow.Status)
+	}
+
+	// 預け入れと払い出しは、どちらの当事者の履歴にも現れます
+	escrowType := "escrow"
+	for _, id := range []int{sender, recipient} {
+		transactions, _, err := model.GetTransactions(ctx, id, TransactionFilter{Type: &escrowType})
+		if err != nil {
+			t.Fatalf("GetTransactions: %v", err)
+		}
+		if len(transactions) != 2 {
+			t.Fatalf("expected deposit and release in history of %d, but got %d", id, len(transactions))
+		}
+
+		// 残高が動かない側の当事者には、増減0でその時点の残高とともに、もう一方の当事者を相手方とする行になります
+		bystander, counterparty, balance := transactions[1].(EscrowMovement), recipient, decimal.NewFromInt(4)
+		if id == recipient {
+			bystander, counterparty, balance = transactions[0].(EscrowMovement), sender, decimal.Zero
+		}
+		if bystander.Direction != None || !bystander.Delta.IsZero() || !bystander.Balance.Equal(balance) || bystander.Counterparty == nil || *bystander.Counterparty != counterparty {
+			t.Errorf("expected neutral movement with counterparty %d in history of %d, but got %+v", counterparty, id, bystander)
+		}
+	}
+
+	outgoing := "outgoing"
+	transactions, _, err := model.GetTransactions(ctx, recipient, TransactionFilter{Type: &escrowType, Direction: &outgoing})
+	if err != nil {
+		t.Fatalf("GetTransactions: %v", err)
+	}
+	if len(transactions) != 0 {
+		t.Errorf("expected no outgoing movements of recipient, but got %+v", transactions)
+	}
+
+	disputed, err := model.CreateEscrow(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(3), nil, 60)
+	if err != nil {
+		t.Fatalf("CreateEscrow: %v", err)
+	}
+	_, err = model.DisputeEscrow(ctx, recipient, disputed.Id)
+	if err != nil {
+		t.Fatalf("DisputeEscrow: %v", err)
+	}
+	_, err = model.ConfirmEscrow(ctx, sender, disputed.Id)
+	if !errors.Is(err, ConflictError) {
+		t.Errorf("expected ConflictError for disputed escrow, but got %v", err)
+	}
+	refunded, err := model.ResolveEscrow(ctx, disputed.Id, Refund)
+	if err != nil {
+		t.Fatalf("ResolveEscrow: %v", err)
+	}
+	if refunded.Status != Refunded {
+		t.Errorf("expected refunded, but was %s", refunded.Status)
+	}
+
+	for id, expected := range map[int]int64{sender: 4, recipient: 6} {
+		balance, err := model.GetBalance(ctx, id, DefaultCurrency)
+		if err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+		if !balance.Equal(decimal.NewFromInt(expected)) {
+			t.Errorf("expected balance of %d to be %d, but was %s", id, expected, balance)
+		}
+	}
+}
+
+func TestCreditLimit(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	id := registerWithBalance(t, model, "business", 10)
+
+	_, err := model.Spend(ctx, id, DefaultCurrency, decimal.NewFromInt(25), nil, nil, nil, nil)
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError without credit limit, but got %v", err)
+	}
+
+	_, err = model.SetCreditLimit(ctx, id, DefaultCurrency, decimal.NewFromInt(20))
+	if err != nil {
+		t.Fatalf("SetCreditLimit: %v", err)
+	}
+
+	_, err = model.Spend(ctx, id, DefaultCurrency, decimal.NewFromInt(25), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Spend: %v", err)
+	}
+
+	balances, err := model.GetBalances(ctx, id)
+	if err != nil {
+		t.Fatalf("GetBalances: %v", err)
+	}
+	if len(balances) != 1 || !balances[0].Balance.Equal(decimal.NewFromInt(-15)) || !balances[0].Overdrawn {
+		t.Errorf("expected overdrawn balance=-15, but got %v", balances)
+	}
+
+	_, err = model.Spend(ctx, id, DefaultCurrency, decimal.NewFromInt(6), nil, nil, nil, nil)
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError beyond credit limit, but got %v", err)
+	}
+
+	_, err = model.Close(ctx, id, "overdrawn", nil)
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError when closing overdrawn account, but got %v", err)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	sender := registerWithBalance(t, model, "sender", 100)
+	recipient := registerWithBalance(t, model, "recipient", 0)
+
+	_, err := model.SetLimit(ctx, Limit{
+		Operation: LimitOperationTransfer,
+		Currency:  DefaultCurrency,
+		Kind:      LimitKindMaxAmount,
+		Value:     decimal.NewFromInt(50),
+	})
+	if err != nil {
+		t.Fatalf("SetLimit: %v", err)
+	}
+
+	_, err = model.SetLimit(ctx, Limit{
+		Account:   &sender,
+		Operation: LimitOperationTransfer,
+		Currency:  DefaultCurrency,
+		Kind:      LimitKindHourlyCount,
+		Value:     decimal.RequireFromString("1.5"),
+	})
+	if !errors.Is(err, ValidationError) {
+		t.Errorf("expected ValidationError for fractional count, but got %v", err)
+	}
+
+	hourly, err := model.SetLimit(ctx, Limit{
+		Account:   &sender,
+		Operation: LimitOperationTransfer,
+		Currency:  DefaultCurrency,
+		Kind:      LimitKindHourlyCount,
+		Value:     decimal.NewFromInt(2),
+	})
+	if err != nil {
+		t.Fatalf("SetLimit: %v", err)
+	}
+
+	var limitErr *LimitExceededError
+	_, err = model.Transfer(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(60), nil, nil, nil, nil)
+	if !errors.As(err, &limitErr) || limitErr.Limit.Kind != LimitKindMaxAmount {
+		t.Errorf("expected LimitExceededError for max_amount, but got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err = model.Transfer(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(10), nil, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("Transfer: %v", err)
+		}
+	}
+
+	_, err = model.Transfer(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(10), nil, nil, nil, nil)
+	if !errors.As(err, &limitErr) || limitErr.Limit.Kind != LimitKindHourlyCount || !limitErr.Usage.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("expected LimitExceededError for hourly_count with usage 2, but got %v", err)
+	}
+
+	// transferの制限はspendにはかかりません
+	_, err = model.Spend(ctx, sender, DefaultCurrency, decimal.NewFromInt(60), nil, nil, nil, nil)
+	if err != nil {
+		t.Errorf("Spend: %v", err)
+	}
+
+	err = model.DeleteLimit(ctx, hourly.Id)
+	if err != nil {
+		t.Fatalf("DeleteLimit: %v", err)
+	}
+
+	_, err = model.Transfer(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(10), nil, nil, nil, nil)
+	if err != nil {
+		t.Errorf("expected transfer after deleting hourly_count, but got %v", err)
+	}
+}
+
+func TestScheduleOverLimit(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	sender := registerWithBalance(t, model, "subscriber", 100)
+	recipient := registerWithBalance(t, model, "service", 0)
+
+	_, err := model.SetLimit(ctx, Limit{
+		Account:   &sender,
+		Operation: LimitOperationTransfer,
+		Currency:  DefaultCurrency,
+		Kind:      LimitKindMaxAmount,
+		Value:     decimal.NewFromInt(5),
+	})
+	if err != nil {
+		t.Fatalf("SetLimit: %v", err)
+	}
+
+	over, err := model.CreateSchedule(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(10), nil, "daily", nil, nil)
+	if err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+	within, err := model.CreateSchedule(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(3), nil, "daily", nil, nil)
+	if err != nil {
+		t.Fatalf("CreateSchedule: %v", err)
+	}
+
+	// 制限を超える回は失敗として記録し、他の予約の実行を妨げません
+	count, err := model.RunDueSchedules(ctx)
+	if err != nil {
+		t.Fatalf("RunDueSchedules: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 runs, but got %d", count)
+	}
+
+	runs, err := model.GetScheduleRuns(ctx, sender, over.Id, DefaultScheduleRunsLimit)
+	if err != nil {
+		t.Fatalf("GetScheduleRuns: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Status != "failed" || runs[0].Error == nil {
+		t.Errorf("expected failed run over limit, but got %+v", runs)
+	}
+
+	scheduled, err := model.GetSchedule(ctx, sender, over.Id)
+	if err != nil {
+		t.Fatalf("GetSchedule: %v", err)
+	}
+	if !scheduled.NextRunAt.After(over.NextRunAt) {
+		t.Errorf("expected next_run_at to advance from %s, but got %s", over.NextRunAt, scheduled.NextRunAt)
+	}
+
+	runs, err = model.GetScheduleRuns(ctx, sender, within.Id, DefaultScheduleRunsLimit)
+	if err != nil {
+		t.Fatalf("GetScheduleRuns: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Status != "succeeded" {
+		t.Errorf("expected succeeded run within limit, but got %+v", runs)
+	}
+}
+
+func TestCloseWithOpenEscrow(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	sender := registerWithBalance(t, model, "buyer", 10)
+	recipient := registerWithBalance(t, model, "seller", 0)
+
+	escrow, err := model.CreateEscrow(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(6), nil, 60)
+	if err != nil {
+		t.Fatalf("CreateEscrow: %v", err)
+	}
+
+	// どちらの当事者も、払い出しか払い戻しが済むまで閉鎖できません
+	_, err = model.Close(ctx, recipient, "leaving", nil)
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError when closing recipient of open escrow, but got %v", err)
+	}
+	_, err = model.Close(ctx, sender, "leaving", &recipient)
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError when closing sender of open escrow, but got %v", err)
+	}
+
+	_, err = model.DisputeEscrow(ctx, sender, escrow.Id)
+	if err != nil {
+		t.Fatalf("DisputeEscrow: %v", err)
+	}
+	_, err = model.Close(ctx, recipient, "leaving", nil)
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError when closing recipient of disputed escrow, but got %v", err)
+	}
+
+	_, err = model.ResolveEscrow(ctx, escrow.Id, Refund)
+	if err != nil {
+		t.Fatalf("ResolveEscrow: %v", err)
+	}
+	_, err = model.Close(ctx, recipient, "leaving", nil)
+	if err != nil {
+		t.Errorf("expected recipient to be closed after refund, but got %v", err)
+	}
+}
+
+func TestRefundExpiredEscrows(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	frozen := registerWithBalance(t, model, "frozen buyer", 10)
+	sender := registerWithBalance(t, model, "buyer", 10)
+	recipient := registerWithBalance(t, model, "seller", 0)
+
+	stuck, err := model.CreateEscrow(ctx, frozen, recipient, DefaultCurrency, decimal.NewFromInt(5), nil, 60)
+	if err != nil {
+		t.Fatalf("CreateEscrow: %v", err)
+	}
+	escrow, err := model.CreateEscrow(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(5), nil, 60)
+	if err != nil {
+		t.Fatalf("CreateEscrow: %v", err)
+	}
+
+	_, err = model.db.Exec("UPDATE escrows SET expires_at = now() - interval '2 minutes' WHERE id = $1", stuck.Id)
+	if err != nil {
+		t.Fatalf("moving expires_at: %v", err)
+	}
+	_, err = model.db.Exec("UPDATE escrows SET expires_at = now() - interval '1 minute' WHERE id = $1", escrow.Id)
+	if err != nil {
+		t.Fatalf("moving expires_at: %v", err)
+	}
+
+	_, err = model.Freeze(ctx, frozen, "suspicious activity")
+	if err != nil {
+		t.Fatalf("Freeze: %v", err)
+	}
+
+	// 払い戻せないescrowは飛ばし、後続のescrowを払い戻します
+	count, skipped, err := model.RefundExpiredEscrows(ctx)
+	if err != nil {
+		t.Fatalf("RefundExpiredEscrows: %v", err)
+	}
+	if count != 1 || len(skipped) != 1 || !errors.Is(skipped[0], AccountFrozenError) {
+		t.Errorf("expected 1 refund and 1 skip for frozen sender, but got %d, %v", count, skipped)
+	}
+
+	refunded, err := model.GetEscrow(ctx, sender, escrow.Id)
+	if err != nil {
+		t.Fatalf("GetEscrow: %v", err)
+	}
+	if refunded.Status != Refunded {
+		t.Errorf("expected refunded, but was %s", refunded.Status)
+	}
+}
+
+func TestLimitsOnJournalAndEscrow(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	sender := registerWithBalance(t, model, "sender", 100)
+	recipient := registerWithBalance(t, model, "recipient", 0)
+
+	for kind, value := range map[LimitKind]int64{LimitKindMaxAmount: 50, LimitKindDailyVolume: 30} {
+		_, err := model.SetLimit(ctx, Limit{
+			Account:   &sender,
+			Operation: LimitOperationTransfer,
+			Currency:  DefaultCurrency,
+			Kind:      kind,
+			Value:     decimal.NewFromInt(value),
+		})
+		if err != nil {
+			t.Fatalf("SetLimit: %v", err)
+		}
+	}
+
+	legs := func(amount int64) []Leg {
+		return []Leg{
+			{Account: sender, Currency: DefaultCurrency, Amount: decimal.NewFromInt(-amount)},
+			{Account: recipient, Currency: DefaultCurrency, Amount: decimal.NewFromInt(amount)},
+		}
+	}
+
+	// journalでの出金とescrowへの預け入れにも、transferの制限を適用します
+	var limitErr *LimitExceededError
+	_, err := model.PostJournal(ctx, legs(60), nil, nil, nil)
+	if !errors.As(err, &limitErr) || limitErr.Limit.Kind != LimitKindMaxAmount {
+		t.Errorf("expected LimitExceededError for journal over max_amount, but got %v", err)
+	}
+	_, err = model.CreateEscrow(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(60), nil, 60)
+	if !errors.As(err, &limitErr) || limitErr.Limit.Kind != LimitKindMaxAmount {
+		t.Errorf("expected LimitExceededError for escrow over max_amount, but got %v", err)
+	}
+
+	_, err = model.CreateEscrow(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(20), nil, 60)
+	if err != nil {
+		t.Fatalf("CreateEscrow: %v", err)
+	}
+
+	// escrowへの預け入れも、直近の出金の合計に数えます
+	_, err = model.PostJournal(ctx, legs(15), nil, nil, nil)
+	if !errors.As(err, &limitErr) || limitErr.Limit.Kind != LimitKindDailyVolume || !limitErr.Usage.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("expected LimitExceededError for daily_volume with usage 20, but got %v", err)
+	}
+
+	_, err = model.PostJournal(ctx, legs(10), nil, nil, nil)
+	if err != nil {
+		t.Errorf("PostJournal: %v", err)
+	}
+}
+
+func TestTransferReversal(t *testing.T) {
+	model := setupModel(t)
+	ctx := context.Background()
+	sender := registerWithBalance(t, model, "sender", 20)
+	recipient := registerWithBalance(t, model, "recipient", 0)
+	operator := registerWithBalance(t, model, "operator", 0)
+
+	_, err := model.SetFeeSchedule(ctx, FeeSchedule{
+		Operation: FeeOperationTransfer,
+		Currency:  DefaultCurrency,
+		Flat:      decimal.NewFromInt(1),
+		Account:   operator,
+	})
+	if err != nil {
+		t.Fatalf("SetFeeSchedule: %v", err)
+	}
+
+	_, err = model.SetCreditLimit(ctx, recipient, DefaultCurrency, decimal.NewFromInt(100))
+	if err != nil {
+		t.Fatalf("SetCreditLimit: %v", err)
+	}
+
+	txId, err := model.Transfer(ctx, sender, recipient, DefaultCurrency, decimal.NewFromInt(10), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+
+	// recipientが使ってしまった分は、与信枠があっても取り消せません
+	_, err = model.Spend(ctx, recipient, DefaultCurrency, decimal.NewFromInt(5), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Spend: %v", err)
+	}
+	_, err = model.Reverse(ctx, sender, txId, nil, nil)
+	if !errors.Is(err, DomainError) {
+		t.Errorf("expected DomainError beyond recipient's balance, but got %v", err)
+	}
+
+	partial := decimal.NewFromInt(5)
+	_, err = model.Reverse(ctx, sender, txId, &partial, nil)
+	if err != nil {
+		t.Fatalf("Reverse: %v", err)
+	}
+
+	// 手数料は払い戻しません
+	for id, expected := range map[int]int64{sender: 14, recipient: 0, operator: 1} {
+		balance, err := model.GetBalance(ctx, id, DefaultCurrency)
+		if err != nil {
+			t.Fatalf("GetBalance: %v", err)
+		}
+		if !balance.Equal(decimal.NewFromInt(expected)) {
+			t.Errorf("expected balance of %d to be %d, but was %s", id, expected, balance)
+		}
+	}
+}
@@ -12,6 +12,77 @@ import (
 
 	"github.com/deepmap/oapi-codegen/pkg/runtime"
 	"github.com/go-chi/chi/v5"
+	"github.com/shopspring/decimal"
+)
+
+// Defines values for AccountStatus.
+const (
+	AccountStatusActive AccountStatus = "active"
+	AccountStatusClosed AccountStatus = "closed"
+	AccountStatusFrozen AccountStatus = "frozen"
+)
+
+// Defines values for ConversionType.
+const (
+	ConversionTypeConversion ConversionType = "conversion"
+)
+
+// Defines values for Direction.
+const (
+	Incoming Direction = "incoming"
+	None     Direction = "none"
+	Outgoing Direction = "outgoing"
+)
+
+// Defines values for EscrowMovementAction.
+const (
+	Deposit EscrowMovementAction = "deposit"
+	Refund  EscrowMovementAction = "refund"
+	Release EscrowMovementAction = "release"
+)
+
+// Defines values for EscrowMovementType.
+const (
+	EscrowMovementTypeEscrow EscrowMovementType = "escrow"
+)
+
+// Defines values for EscrowStatus.
+const (
+	Disputed EscrowStatus = "disputed"
+	Funded   EscrowStatus = "funded"
+	Refunded EscrowStatus = "refunded"
+	Released EscrowStatus = "released"
+)
+
+// Defines values for FeeType.
+const (
+	FeeTypeFee FeeType = "fee"
+)
+
+// Defines values for HoldStatus.
+const (
+	HoldStatusActive   HoldStatus = "active"
+	HoldStatusCaptured HoldStatus = "captured"
+	HoldStatusExpired  HoldStatus = "expired"
+	HoldStatusReleased HoldStatus = "released"
+)
+
+// Defines values for JournalType.
+const (
+	JournalTypeJournal JournalType = "journal"
+)
+
+// Defines values for LimitExceededKind.
+const (
+	DailyVolume LimitExceededKind = "daily_volume"
+	HourlyCount LimitExceededKind = "hourly_count"
+	MaxAmount   LimitExceededKind = "max_amount"
+)
+
+// Defines values for LimitExceededOperation.
+const (
+	LimitExceededOperationSpend    LimitExceededOperation = "spend"
+	LimitExceededOperationTransfer LimitExceededOperation = "transfer"
 )
 
 // Defines values for MintType.
@@ -19,6 +90,41 @@ const (
 	MintTypeMint MintType = "mint"
 )
 
+// Defines values for PaymentRequestStatus.
+const (
+	Declined PaymentRequestStatus = "declined"
+	Expired  PaymentRequestStatus = "expired"
+	Paid     PaymentRequestStatus = "paid"
+	Pending  PaymentRequestStatus = "pending"
+)
+
+// Defines values for ReversalType.
+const (
+	ReversalTypeReversal ReversalType = "reversal"
+)
+
+// Defines values for ScheduleStatus.
+const (
+	Active    ScheduleStatus = "active"
+	Cancelled ScheduleStatus = "cancelled"
+	Completed ScheduleStatus = "completed"
+	Paused    ScheduleStatus = "paused"
+)
+
+// Defines values for ScheduleFrequency.
+const (
+	Daily   ScheduleFrequency = "daily"
+	Monthly ScheduleFrequency = "monthly"
+	Once    ScheduleFrequency = "once"
+	Weekly  ScheduleFrequency = "weekly"
+)
+
+// Defines values for ScheduleRunStatus.
+const (
+	Failed    ScheduleRunStatus = "failed"
+	Succeeded ScheduleRunStatus = "succeeded"
+)
+
 // Defines values for SpendType.
 const (
 	SpendTypeSpend SpendType = "spend"
@@ -29,25 +135,359 @@ const (
 	TransferTypeTransfer TransferType = "transfer"
 )
 
+// Defines values for TransactionsParamsType.
+const (
+	TransactionsParamsTypeConversion TransactionsParamsType = "conversion"
+	TransactionsParamsTypeEscrow     TransactionsParamsType = "escrow"
+	TransactionsParamsTypeFee        TransactionsParamsType = "fee"
+	TransactionsParamsTypeJournal    TransactionsParamsType = "journal"
+	TransactionsParamsTypeMint       TransactionsParamsType = "mint"
+	TransactionsParamsTypeReversal   TransactionsParamsType = "reversal"
+	TransactionsParamsTypeSpend      TransactionsParamsType = "spend"
+	TransactionsParamsTypeTransfer   TransactionsParamsType = "transfer"
+)
+
+// Account defines model for Account.
+type Account struct {
+	// Balances 一覧では省略されます
+	Balances   *[]Balance    `json:"balances,omitempty"`
+	ExternalId *string       `json:"external_id,omitempty"`
+	Id         int           `json:"id"`
+	InsertedAt time.Time     `json:"inserted_at"`
+	Metadata   Metadata      `json:"metadata"`
+	Name       string        `json:"name"`
+	Status     AccountStatus `json:"status"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+// AccountStatus defines model for Account.Status.
+type AccountStatus string
+
+// Amount defines model for Amount.
+type Amount = decimal.Decimal
+
+// Balance defines model for Balance.
+type Balance struct {
+	Available   Amount `json:"available"`
+	Balance     Amount `json:"balance"`
+	CreditLimit Amount `json:"credit_limit"`
+	Currency    string `json:"currency"`
+
+	// Overdrawn 与信枠を使ってbalanceが負になっていればtrue
+	Overdrawn bool `json:"overdrawn"`
+}
+
+// Conversion defines model for Conversion.
+type Conversion struct {
+	Account         int    `json:"account"`
+	Amount          Amount `json:"amount"`
+	Balance         Amount `json:"balance"`
+	ConvertedAmount Amount `json:"converted_amount"`
+
+	// Counterparty 取引の相手方のaccount。mintやspendのように相手方がいなければ省略されます
+	Counterparty *int `json:"counterparty,omitempty"`
+
+	// Currency この行が表す側のcurrency。換算元と換算先でそれぞれ1行になります
+	Currency   string         `json:"currency"`
+	Delta      Amount         `json:"delta"`
+	Direction  Direction      `json:"direction"`
+	Id         int            `json:"id"`
+	InsertedAt time.Time      `json:"inserted_at"`
+	Metadata   Metadata       `json:"metadata"`
+	Rate       Amount         `json:"rate"`
+	Recipient  int            `json:"recipient"`
+	Source     string         `json:"source"`
+	Target     string         `json:"target"`
+	Type       ConversionType `json:"type"`
+}
+
+// ConversionType defines model for Conversion.Type.
+type ConversionType string
+
+// Direction defines model for Direction.
+type Direction string
+
+// Escrow defines model for Escrow.
+type Escrow struct {
+	Amount               Amount     `json:"amount"`
+	Currency             string     `json:"currency"`
+	ExpiresAt            time.Time  `json:"expires_at"`
+	Id                   int        `json:"id"`
+	InsertedAt           time.Time  `json:"inserted_at"`
+	Memo                 *string    `json:"memo,omitempty"`
+	Recipient            int        `json:"recipient"`
+	RecipientConfirmedAt *time.Time `json:"recipient_confirmed_at,omitempty"`
+	Sender               int        `json:"sender"`
+	SenderConfirmedAt    *time.Time `json:"sender_confirmed_at,omitempty"`
+
+	// Status disputedは異議が申し立てられ、管理者による解決を待っているものです
+	Status    EscrowStatus `json:"status"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// EscrowMovement defines model for EscrowMovement.
+type EscrowMovement struct {
+	// Account 残高が動いた当事者のaccount
+	Account int                  `json:"account"`
+	Action  EscrowMovementAction `json:"action"`
+	Amount  Amount               `json:"amount"`
+	Balance Amount               `json:"balance"`
+
+	// Counterparty 取引の相手方のaccount
+	Counterparty *int               `json:"counterparty,omitempty"`
+	Currency     string             `json:"currency"`
+	Delta        Amount             `json:"delta"`
+	Direction    Direction          `json:"direction"`
+	Escrow       int                `json:"escrow"`
+	Id           int                `json:"id"`
+	InsertedAt   time.Time          `json:"inserted_at"`
+	Memo         *string            `json:"memo,omitempty"`
+	Metadata     Metadata           `json:"metadata"`
+	Recipient    int                `json:"recipient"`
+	Sender       int                `json:"sender"`
+	Type         EscrowMovementType `json:"type"`
+}
+
+// EscrowMovementAction defines model for EscrowMovement.Action.
+type EscrowMovementAction string
+
+// EscrowMovementType defines model for EscrowMovement.Type.
+type EscrowMovementType string
+
+// EscrowStatus disputedは異議が申し立てられ、管理者による解決を待っているものです
+type EscrowStatus string
+
+// Fee defines model for Fee.
+type Fee struct {
+	// Account 手数料を支払ったaccount
+	Account int    `json:"account"`
+	Amount  Amount `json:"amount"`
+	Balance Amount `json:"balance"`
+
+	// Counterparty 取引の相手方のaccount
+	Counterparty *int      `json:"counterparty,omitempty"`
+	Currency     string    `json:"currency"`
+	Delta        Amount    `json:"delta"`
+	Direction    Direction `json:"direction"`
+	Id           int       `json:"id"`
+	InsertedAt   time.Time `json:"inserted_at"`
+	Metadata     Metadata  `json:"metadata"`
+
+	// Original 手数料の対象になったtransaction
+	Original int `json:"original"`
+
+	// Recipient 手数料を受け取ったaccount
+	Recipient int     `json:"recipient"`
+	Type      FeeType `json:"type"`
+}
+
+// FeeType defines model for Fee.Type.
+type FeeType string
+
+// Hold defines model for Hold.
+type Hold struct {
+	Account     int        `json:"account"`
+	Amount      Amount     `json:"amount"`
+	Currency    string     `json:"currency"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	Id          int        `json:"id"`
+	InsertedAt  time.Time  `json:"inserted_at"`
+	Status      HoldStatus `json:"status"`
+	Transaction *int       `json:"transaction,omitempty"`
+}
+
+// HoldStatus defines model for Hold.Status.
+type HoldStatus string
+
+// Journal defines model for Journal.
+type Journal struct {
+	// Account 最初に出金したaccount
+	Account int    `json:"account"`
+	Balance Amount `json:"balance"`
+
+	// Counterparty 取引の相手方のaccount。同じcurrencyを動かしたaccountを優先します
+	Counterparty *int        `json:"counterparty,omitempty"`
+	Currency     string      `json:"currency"`
+	Delta        Amount      `json:"delta"`
+	Direction    Direction   `json:"direction"`
+	Id           int         `json:"id"`
+	InsertedAt   time.Time   `json:"inserted_at"`
+	Memo         *string     `json:"memo,omitempty"`
+	Metadata     Metadata    `json:"metadata"`
+	Type         JournalType `json:"type"`
+}
+
+// JournalType defines model for Journal.Type.
+type JournalType string
+
+// LimitExceeded defines model for LimitExceeded.
+type LimitExceeded struct {
+	// Account 制限を定めたaccount。全てのaccountsに対する既定値であれば省略します
+	Account  *int   `json:"account,omitempty"`
+	Currency string `json:"currency"`
+
+	// Kind max_amountは1回の金額、daily_volumeは直近24時間の金額の合計、hourly_countは直近1時間の回数の上限です。transferの制限はjournalでの出金とescrowへの預け入れにも適用します
+	Kind LimitExceededKind `json:"kind"`
+
+	// Limit 超えた制限のid
+	Limit     int                    `json:"limit"`
+	Message   string                 `json:"message"`
+	Operation LimitExceededOperation `json:"operation"`
+	Requested Amount                 `json:"requested"`
+	Usage     Amount                 `json:"usage"`
+	Value     Amount                 `json:"value"`
+}
+
+// LimitExceededKind max_amountは1回の金額、daily_volumeは直近24時間の金額の合計、hourly_countは直近1時間の回数の上限です。transferの制限はjournalでの出金とescrowへの預け入れにも適用します
+type LimitExceededKind string
+
+// LimitExceededOperation defines model for LimitExceeded.Operation.
+type LimitExceededOperation string
+
+// Metadata defines model for Metadata.
+type Metadata map[string]string
+
 // Mint defines model for Mint.
 type Mint struct {
-	Account    int       `json:"account"`
-	Amount     int       `json:"amount"`
-	Id         int       `json:"id"`
-	InsertedAt time.Time `json:"inserted_at"`
-	Type       MintType  `json:"type"`
+	Account int    `json:"account"`
+	Amount  Amount `json:"amount"`
+	Balance Amount `json:"balance"`
+
+	// Counterparty 取引の相手方のaccount。mintやspendのように相手方がいなければ省略されます
+	Counterparty *int       `json:"counterparty,omitempty"`
+	Currency     string     `json:"currency"`
+	Delta        Amount     `json:"delta"`
+	Direction    Direction  `json:"direction"`
+	Id           int        `json:"id"`
+	InsertedAt   time.Time  `json:"inserted_at"`
+	Memo         *string    `json:"memo,omitempty"`
+	Metadata     Metadata   `json:"metadata"`
+	Reference    *Reference `json:"reference,omitempty"`
+	Type         MintType   `json:"type"`
 }
 
 // MintType defines model for Mint.Type.
 type MintType string
 
-// Spend defines model for Spend.
-type Spend struct {
+// PaymentRequest defines model for PaymentRequest.
+type PaymentRequest struct {
+	Amount     Amount    `json:"amount"`
+	Currency   string    `json:"currency"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Id         int       `json:"id"`
+	InsertedAt time.Time `json:"inserted_at"`
+	Memo       *string   `json:"memo,omitempty"`
+	Payer      int       `json:"payer"`
+	Requester  int       `json:"requester"`
+
+	// Status expiredは、expires_atを過ぎても支払われなかったpendingのものです
+	Status      PaymentRequestStatus `json:"status"`
+	Transaction *int                 `json:"transaction,omitempty"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// PaymentRequestStatus expiredは、expires_atを過ぎても支払われなかったpendingのものです
+type PaymentRequestStatus string
+
+// Quote defines model for Quote.
+type Quote struct {
 	Account    int       `json:"account"`
-	Amount     int       `json:"amount"`
+	ExpiresAt  time.Time `json:"expires_at"`
 	Id         int       `json:"id"`
 	InsertedAt time.Time `json:"inserted_at"`
-	Type       SpendType `json:"type"`
+	Rate       Amount    `json:"rate"`
+	Source     string    `json:"source"`
+	Target     string    `json:"target"`
+
+	// Transaction このquoteを使ったconversionのtransaction
+	Transaction *int `json:"transaction,omitempty"`
+}
+
+// Reference defines model for Reference.
+type Reference struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// Reversal defines model for Reversal.
+type Reversal struct {
+	Account int    `json:"account"`
+	Amount  Amount `json:"amount"`
+	Balance Amount `json:"balance"`
+
+	// Counterparty 取引の相手方のaccount。mintやspendのように相手方がいなければ省略されます
+	Counterparty *int         `json:"counterparty,omitempty"`
+	Currency     string       `json:"currency"`
+	Delta        Amount       `json:"delta"`
+	Direction    Direction    `json:"direction"`
+	Id           int          `json:"id"`
+	InsertedAt   time.Time    `json:"inserted_at"`
+	Metadata     Metadata     `json:"metadata"`
+	Original     int          `json:"original"`
+	Type         ReversalType `json:"type"`
+}
+
+// ReversalType defines model for Reversal.Type.
+type ReversalType string
+
+// Schedule defines model for Schedule.
+type Schedule struct {
+	Account  int        `json:"account"`
+	Amount   Amount     `json:"amount"`
+	Currency string     `json:"currency"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+
+	// Frequency onceは一度だけ実行します
+	Frequency  ScheduleFrequency `json:"frequency"`
+	Id         int               `json:"id"`
+	InsertedAt time.Time         `json:"inserted_at"`
+	Memo       *string           `json:"memo,omitempty"`
+
+	// NextRunAt 次の回の実行予定。completedやcancelledでは意味を持ちません
+	NextRunAt time.Time      `json:"next_run_at"`
+	Recipient int            `json:"recipient"`
+	StartsAt  time.Time      `json:"starts_at"`
+	Status    ScheduleStatus `json:"status"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// ScheduleStatus defines model for Schedule.Status.
+type ScheduleStatus string
+
+// ScheduleFrequency onceは一度だけ実行します
+type ScheduleFrequency string
+
+// ScheduleRun defines model for ScheduleRun.
+type ScheduleRun struct {
+	DueAt       time.Time         `json:"due_at"`
+	Error       *string           `json:"error,omitempty"`
+	Id          int               `json:"id"`
+	InsertedAt  time.Time         `json:"inserted_at"`
+	Schedule    int               `json:"schedule"`
+	Status      ScheduleRunStatus `json:"status"`
+	Transaction *int              `json:"transaction,omitempty"`
+}
+
+// ScheduleRunStatus defines model for ScheduleRun.Status.
+type ScheduleRunStatus string
+
+// Spend defines model for Spend.
+type Spend struct {
+	Account int    `json:"account"`
+	Amount  Amount `json:"amount"`
+	Balance Amount `json:"balance"`
+
+	// Counterparty 取引の相手方のaccount。mintやspendのように相手方がいなければ省略されます
+	Counterparty *int       `json:"counterparty,omitempty"`
+	Currency     string     `json:"currency"`
+	Delta        Amount     `json:"delta"`
+	Direction    Direction  `json:"direction"`
+	Id           int        `json:"id"`
+	InsertedAt   time.Time  `json:"inserted_at"`
+	Memo         *string    `json:"memo,omitempty"`
+	Metadata     Metadata   `json:"metadata"`
+	Reference    *Reference `json:"reference,omitempty"`
+	Type         SpendType  `json:"type"`
 }
 
 // SpendType defines model for Spend.Type.
@@ -55,73 +495,459 @@ type SpendType string
 
 // Transfer defines model for Transfer.
 type Transfer struct {
-	Account    int          `json:"account"`
-	Amount     int          `json:"amount"`
-	Id         int          `json:"id"`
-	InsertedAt time.Time    `json:"inserted_at"`
-	Recipient  int          `json:"recipient"`
-	Type       TransferType `json:"type"`
+	Account int    `json:"account"`
+	Amount  Amount `json:"amount"`
+	Balance Amount `json:"balance"`
+
+	// Counterparty 取引の相手方のaccount。mintやspendのように相手方がいなければ省略されます
+	Counterparty *int         `json:"counterparty,omitempty"`
+	Currency     string       `json:"currency"`
+	Delta        Amount       `json:"delta"`
+	Direction    Direction    `json:"direction"`
+	Id           int          `json:"id"`
+	InsertedAt   time.Time    `json:"inserted_at"`
+	Memo         *string      `json:"memo,omitempty"`
+	Metadata     Metadata     `json:"metadata"`
+	Recipient    int          `json:"recipient"`
+	Reference    *Reference   `json:"reference,omitempty"`
+	Type         TransferType `json:"type"`
 }
 
 // TransferType defines model for Transfer.Type.
 type TransferType string
 
+// TransferItem defines model for TransferItem.
+type TransferItem struct {
+	Amount    Amount  `json:"amount"`
+	Memo      *string `json:"memo,omitempty"`
+	Recipient int     `json:"recipient"`
+}
+
+// TransferItemError defines model for TransferItemError.
+type TransferItemError struct {
+	Error string `json:"error"`
+
+	// Index transfersの中での位置
+	Index int `json:"index"`
+}
+
 // AccountId defines model for AccountId.
 type AccountId = int
 
+// EscrowId defines model for EscrowId.
+type EscrowId = int
+
+// HoldId defines model for HoldId.
+type HoldId = int
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// PaymentRequestId defines model for PaymentRequestId.
+type PaymentRequestId = int
+
+// ScheduleId defines model for ScheduleId.
+type ScheduleId = int
+
+// TransactionId defines model for TransactionId.
+type TransactionId = int
+
+// AccountsParams defines parameters for Accounts.
+type AccountsParams struct {
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// After 前のページのレスポンスのNext-Cursorヘッダの値
+	After      *string `form:"after,omitempty" json:"after,omitempty"`
+	NamePrefix *string `form:"name_prefix,omitempty" json:"name_prefix,omitempty"`
+
+	// Since この日時以降に作られたaccountsに絞り込みます
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+
+	// Until この日時より前に作られたaccountsに絞り込みます
+	Until      *time.Time `form:"until,omitempty" json:"until,omitempty"`
+	ExternalId *string    `form:"external_id,omitempty" json:"external_id,omitempty"`
+
+	// Metadata key:valueの形式で、指定した全てのmetadataをもつものに絞り込みます
+	Metadata *[]string `form:"metadata,omitempty" json:"metadata,omitempty"`
+}
+
 // RegisterJSONBody defines parameters for Register.
 type RegisterJSONBody struct {
+	// ExternalId 連携先のシステムが付与するid。accountsの間で一意です
+	ExternalId *string   `json:"external_id,omitempty"`
+	Metadata   *Metadata `json:"metadata,omitempty"`
+	Name       string    `json:"name"`
+}
+
+// UpdateAccountJSONBody defines parameters for UpdateAccount.
+type UpdateAccountJSONBody struct {
 	Name string `json:"name"`
 }
 
+// BalanceParams defines parameters for Balance.
+type BalanceParams struct {
+	// Currency 省略した場合は既定の通貨
+	Currency *string `form:"currency,omitempty" json:"currency,omitempty"`
+}
+
+// ConvertJSONBody defines parameters for Convert.
+type ConvertJSONBody struct {
+	Amount Amount `json:"amount"`
+
+	// Quote 省略した場合はその時点で有効なレートを使います
+	Quote *int `json:"quote,omitempty"`
+
+	// Recipient 省略した場合は自身に入金します
+	Recipient *int   `json:"recipient,omitempty"`
+	Source    string `json:"source"`
+	Target    string `json:"target"`
+}
+
+// ConvertParams defines parameters for Convert.
+type ConvertParams struct {
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// EscrowsParams defines parameters for Escrows.
+type EscrowsParams struct {
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// CreateEscrowJSONBody defines parameters for CreateEscrow.
+type CreateEscrowJSONBody struct {
+	Amount Amount `json:"amount"`
+
+	// Currency 省略した場合は既定の通貨
+	Currency  *string `json:"currency,omitempty"`
+	Memo      *string `json:"memo,omitempty"`
+	Recipient int     `json:"recipient"`
+
+	// Ttl 省略した場合は7日間
+	Ttl *int `json:"ttl,omitempty"`
+}
+
+// HoldJSONBody defines parameters for Hold.
+type HoldJSONBody struct {
+	Amount Amount `json:"amount"`
+
+	// Currency 省略した場合は既定の通貨
+	Currency *string `json:"currency,omitempty"`
+	Ttl      int     `json:"ttl"`
+}
+
+// CaptureJSONBody defines parameters for Capture.
+type CaptureJSONBody struct {
+	Amount    *Amount `json:"amount,omitempty"`
+	Recipient *int    `json:"recipient,omitempty"`
+}
+
 // MintJSONBody defines parameters for Mint.
 type MintJSONBody struct {
-	Amount int `json:"amount"`
+	Amount Amount `json:"amount"`
+
+	// Currency 省略した場合は既定の通貨
+	Currency *string `json:"currency,omitempty"`
+
+	// Memo 取引の目的などを書く自由記述
+	Memo      *string    `json:"memo,omitempty"`
+	Metadata  *Metadata  `json:"metadata,omitempty"`
+	Reference *Reference `json:"reference,omitempty"`
+}
+
+// MintParams defines parameters for Mint.
+type MintParams struct {
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// PaymentRequestsParams defines parameters for PaymentRequests.
+type PaymentRequestsParams struct {
+	Direction *Direction            `form:"direction,omitempty" json:"direction,omitempty"`
+	Status    *PaymentRequestStatus `form:"status,omitempty" json:"status,omitempty"`
+	Limit     *int                  `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// CreatePaymentRequestJSONBody defines parameters for CreatePaymentRequest.
+type CreatePaymentRequestJSONBody struct {
+	Amount Amount `json:"amount"`
+
+	// Currency 省略した場合は既定の通貨
+	Currency *string `json:"currency,omitempty"`
+	Memo     *string `json:"memo,omitempty"`
+	Payer    int     `json:"payer"`
+
+	// Ttl 省略した場合は7日間
+	Ttl *int `json:"ttl,omitempty"`
+}
+
+// QuoteJSONBody defines parameters for Quote.
+type QuoteJSONBody struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+
+	// Ttl レートを固定しておく秒数
+	Ttl int `json:"ttl"`
+}
+
+// CreateScheduleJSONBody defines parameters for CreateSchedule.
+type CreateScheduleJSONBody struct {
+	Amount Amount `json:"amount"`
+
+	// Currency 省略した場合は既定の通貨
+	Currency *string `json:"currency,omitempty"`
+
+	// EndsAt この時刻より後の回は実行しません
+	EndsAt *time.Time `json:"ends_at,omitempty"`
+
+	// Frequency onceは一度だけ実行します
+	Frequency ScheduleFrequency `json:"frequency"`
+	Memo      *string           `json:"memo,omitempty"`
+	Recipient int               `json:"recipient"`
+	StartsAt  *time.Time        `json:"starts_at,omitempty"`
+}
+
+// UpdateScheduleJSONBody defines parameters for UpdateSchedule.
+type UpdateScheduleJSONBody struct {
+	Amount *Amount    `json:"amount,omitempty"`
+	EndsAt *time.Time `json:"ends_at,omitempty"`
+	Memo   *string    `json:"memo,omitempty"`
+}
+
+// ScheduleRunsParams defines parameters for ScheduleRuns.
+type ScheduleRunsParams struct {
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
 // SpendJSONBody defines parameters for Spend.
 type SpendJSONBody struct {
-	Amount int `json:"amount"`
+	Amount Amount `json:"amount"`
+
+	// Currency 省略した場合は既定の通貨
+	Currency *string `json:"currency,omitempty"`
+
+	// Memo 取引の目的などを書く自由記述
+	Memo      *string    `json:"memo,omitempty"`
+	Metadata  *Metadata  `json:"metadata,omitempty"`
+	Reference *Reference `json:"reference,omitempty"`
+}
+
+// SpendParams defines parameters for Spend.
+type SpendParams struct {
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// TransactionsParams defines parameters for Transactions.
+type TransactionsParams struct {
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// After 前のページのレスポンスのNext-Cursorヘッダの値
+	After        *string                 `form:"after,omitempty" json:"after,omitempty"`
+	Type         *TransactionsParamsType `form:"type,omitempty" json:"type,omitempty"`
+	Direction    *Direction              `form:"direction,omitempty" json:"direction,omitempty"`
+	Counterparty *int                    `form:"counterparty,omitempty" json:"counterparty,omitempty"`
+	MinAmount    *string                 `form:"min_amount,omitempty" json:"min_amount,omitempty"`
+	MaxAmount    *string                 `form:"max_amount,omitempty" json:"max_amount,omitempty"`
+	Currency     *string                 `form:"currency,omitempty" json:"currency,omitempty"`
+	Since        *time.Time              `form:"since,omitempty" json:"since,omitempty"`
+	Until        *time.Time              `form:"until,omitempty" json:"until,omitempty"`
+
+	// Metadata key:valueの形式で、指定した全てのmetadataをもつものに絞り込みます
+	Metadata *[]string `form:"metadata,omitempty" json:"metadata,omitempty"`
+
+	// Memo 大文字と小文字を区別せず、memoにこの文字列を含むものに絞り込みます
+	Memo *string `form:"memo,omitempty" json:"memo,omitempty"`
+}
+
+// TransactionsParamsType defines parameters for Transactions.
+type TransactionsParamsType string
+
+// ReverseJSONBody defines parameters for Reverse.
+type ReverseJSONBody struct {
+	Amount *Amount `json:"amount,omitempty"`
+}
+
+// ReverseParams defines parameters for Reverse.
+type ReverseParams struct {
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
 }
 
 // TransferJSONBody defines parameters for Transfer.
 type TransferJSONBody struct {
-	Amount    int `json:"amount"`
-	Recipient int `json:"recipient"`
+	Amount Amount `json:"amount"`
+
+	// Currency 省略した場合は既定の通貨
+	Currency *string `json:"currency,omitempty"`
+
+	// Memo 取引の目的などを書く自由記述
+	Memo      *string    `json:"memo,omitempty"`
+	Metadata  *Metadata  `json:"metadata,omitempty"`
+	Recipient int        `json:"recipient"`
+	Reference *Reference `json:"reference,omitempty"`
+}
+
+// TransferParams defines parameters for Transfer.
+type TransferParams struct {
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// TransferBatchJSONBody defines parameters for TransferBatch.
+type TransferBatchJSONBody struct {
+	// Currency 省略した場合は既定の通貨
+	Currency  *string        `json:"currency,omitempty"`
+	Transfers []TransferItem `json:"transfers"`
 }
 
 // RegisterJSONRequestBody defines body for Register for application/json ContentType.
 type RegisterJSONRequestBody RegisterJSONBody
 
+// UpdateAccountJSONRequestBody defines body for UpdateAccount for application/json ContentType.
+type UpdateAccountJSONRequestBody UpdateAccountJSONBody
+
+// ConvertJSONRequestBody defines body for Convert for application/json ContentType.
+type ConvertJSONRequestBody ConvertJSONBody
+
+// CreateEscrowJSONRequestBody defines body for CreateEscrow for application/json ContentType.
+type CreateEscrowJSONRequestBody CreateEscrowJSONBody
+
+// HoldJSONRequestBody defines body for Hold for application/json ContentType.
+type HoldJSONRequestBody HoldJSONBody
+
+// CaptureJSONRequestBody defines body for Capture for application/json ContentType.
+type CaptureJSONRequestBody CaptureJSONBody
+
 // MintJSONRequestBody defines body for Mint for application/json ContentType.
 type MintJSONRequestBody MintJSONBody
 
+// CreatePaymentRequestJSONRequestBody defines body for CreatePaymentRequest for application/json ContentType.
+type CreatePaymentRequestJSONRequestBody CreatePaymentRequestJSONBody
+
+// QuoteJSONRequestBody defines body for Quote for application/json ContentType.
+type QuoteJSONRequestBody QuoteJSONBody
+
+// CreateScheduleJSONRequestBody defines body for CreateSchedule for application/json ContentType.
+type CreateScheduleJSONRequestBody CreateScheduleJSONBody
+
+// UpdateScheduleJSONRequestBody defines body for UpdateSchedule for application/json ContentType.
+type UpdateScheduleJSONRequestBody UpdateScheduleJSONBody
+
 // SpendJSONRequestBody defines body for Spend for application/json ContentType.
 type SpendJSONRequestBody SpendJSONBody
 
+// ReverseJSONRequestBody defines body for Reverse for application/json ContentType.
+type ReverseJSONRequestBody ReverseJSONBody
+
 // TransferJSONRequestBody defines body for Transfer for application/json ContentType.
 type TransferJSONRequestBody TransferJSONBody
 
+// TransferBatchJSONRequestBody defines body for TransferBatch for application/json ContentType.
+type TransferBatchJSONRequestBody TransferBatchJSONBody
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
+	// (GET /)
+	Accounts(w http.ResponseWriter, r *http.Request, params AccountsParams)
+
 	// (POST /)
 	Register(w http.ResponseWriter, r *http.Request)
 
+	// (GET /{id})
+	Account(w http.ResponseWriter, r *http.Request, id AccountId)
+
+	// (PATCH /{id})
+	UpdateAccount(w http.ResponseWriter, r *http.Request, id AccountId)
+
 	// (GET /{id}/balance)
-	Balance(w http.ResponseWriter, r *http.Request, id AccountId)
+	Balance(w http.ResponseWriter, r *http.Request, id AccountId, params BalanceParams)
+
+	// (GET /{id}/balances)
+	Balances(w http.ResponseWriter, r *http.Request, id AccountId)
+
+	// (POST /{id}/convert)
+	Convert(w http.ResponseWriter, r *http.Request, id AccountId, params ConvertParams)
+
+	// (GET /{id}/escrows)
+	Escrows(w http.ResponseWriter, r *http.Request, id AccountId, params EscrowsParams)
+
+	// (POST /{id}/escrows)
+	CreateEscrow(w http.ResponseWriter, r *http.Request, id AccountId)
+
+	// (GET /{id}/escrows/{escrowId})
+	Escrow(w http.ResponseWriter, r *http.Request, id AccountId, escrowId EscrowId)
+
+	// (POST /{id}/escrows/{escrowId}/confirm)
+	ConfirmEscrow(w http.ResponseWriter, r *http.Request, id AccountId, escrowId EscrowId)
+
+	// (POST /{id}/escrows/{escrowId}/dispute)
+	DisputeEscrow(w http.ResponseWriter, r *http.Request, id AccountId, escrowId EscrowId)
+
+	// (POST /{id}/holds)
+	Hold(w http.ResponseWriter, r *http.Request, id AccountId)
+
+	// (POST /{id}/holds/{holdId}/capture)
+	Capture(w http.ResponseWriter, r *http.Request, id AccountId, holdId HoldId)
+
+	// (POST /{id}/holds/{holdId}/release)
+	Release(w http.ResponseWriter, r *http.Request, id AccountId, holdId HoldId)
 
 	// (POST /{id}/mint)
-	Mint(w http.ResponseWriter, r *http.Request, id AccountId)
+	Mint(w http.ResponseWriter, r *http.Request, id AccountId, params MintParams)
+
+	// (GET /{id}/payment_requests)
+	PaymentRequests(w http.ResponseWriter, r *http.Request, id AccountId, params PaymentRequestsParams)
+
+	// (POST /{id}/payment_requests)
+	CreatePaymentRequest(w http.ResponseWriter, r *http.Request, id AccountId)
+
+	// (GET /{id}/payment_requests/{requestId})
+	PaymentRequest(w http.ResponseWriter, r *http.Request, id AccountId, requestId PaymentRequestId)
+
+	// (POST /{id}/payment_requests/{requestId}/accept)
+	AcceptPaymentRequest(w http.ResponseWriter, r *http.Request, id AccountId, requestId PaymentRequestId)
+
+	// (POST /{id}/payment_requests/{requestId}/decline)
+	DeclinePaymentRequest(w http.ResponseWriter, r *http.Request, id AccountId, requestId PaymentRequestId)
+
+	// (POST /{id}/quotes)
+	Quote(w http.ResponseWriter, r *http.Request, id AccountId)
+
+	// (GET /{id}/schedules)
+	Schedules(w http.ResponseWriter, r *http.Request, id AccountId)
+
+	// (POST /{id}/schedules)
+	CreateSchedule(w http.ResponseWriter, r *http.Request, id AccountId)
+
+	// (DELETE /{id}/schedules/{scheduleId})
+	CancelSchedule(w http.ResponseWriter, r *http.Request, id AccountId, scheduleId ScheduleId)
+
+	// (GET /{id}/schedules/{scheduleId})
+	Schedule(w http.ResponseWriter, r *http.Request, id AccountId, scheduleId ScheduleId)
+
+	// (PATCH /{id}/schedules/{scheduleId})
+	UpdateSchedule(w http.ResponseWriter, r *http.Request, id AccountId, scheduleId ScheduleId)
+
+	// (POST /{id}/schedules/{scheduleId}/pause)
+	PauseSchedule(w http.ResponseWriter, r *http.Request, id AccountId, scheduleId ScheduleId)
+
+	// (POST /{id}/schedules/{scheduleId}/resume)
+	ResumeSchedule(w http.ResponseWriter, r *http.Request, id AccountId, scheduleId ScheduleId)
+
+	// (GET /{id}/schedules/{scheduleId}/runs)
+	ScheduleRuns(w http.ResponseWriter, r *http.Request, id AccountId, scheduleId ScheduleId, params ScheduleRunsParams)
 
 	// (POST /{id}/spend)
-	Spend(w http.ResponseWriter, r *http.Request, id AccountId)
+	Spend(w http.ResponseWriter, r *http.Request, id AccountId, params SpendParams)
 
 	// (GET /{id}/transactions)
-	Transactions(w http.ResponseWriter, r *http.Request, id AccountId)
+	Transactions(w http.ResponseWriter, r *http.Request, id AccountId, params TransactionsParams)
+
+	// (POST /{id}/transactions/{txId}/reverse)
+	Reverse(w http.ResponseWriter, r *http.Request, id AccountId, txId TransactionId, params ReverseParams)
 
 	// (POST /{id}/transfer)
-	Transfer(w http.ResponseWriter, r *http.Request, id AccountId)
+	Transfer(w http.ResponseWriter, r *http.Request, id AccountId, params TransferParams)
+
+	// (POST /{id}/transfers/batch)
+	TransferBatch(w http.ResponseWriter, r *http.Request, id AccountId)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -133,64 +959,73 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
-// Register operation middleware
-func (siw *ServerInterfaceWrapper) Register(w http.ResponseWriter, r *http.Request) {
+// Accounts operation middleware
+func (siw *ServerInterfaceWrapper) Accounts(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.Register(w, r)
-	})
-
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+	var err error
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
-}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AccountsParams
 
-// Balance operation middleware
-func (siw *ServerInterfaceWrapper) Balance(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	// ------------- Optional query parameter "limit" -------------
 
-	var err error
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
 
-	// ------------- Path parameter "id" -------------
-	var id AccountId
+	// ------------- Optional query parameter "after" -------------
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	err = runtime.BindQueryParameter("form", true, false, "after", r.URL.Query(), &params.After)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "after", Err: err})
 		return
 	}
 
-	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.Balance(w, r, id)
-	})
+	// ------------- Optional query parameter "name_prefix" -------------
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
+	err = runtime.BindQueryParameter("form", true, false, "name_prefix", r.URL.Query(), &params.NamePrefix)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "name_prefix", Err: err})
+		return
 	}
 
-	handler.ServeHTTP(w, r.WithContext(ctx))
-}
+	// ------------- Optional query parameter "since" -------------
 
-// Mint operation middleware
-func (siw *ServerInterfaceWrapper) Mint(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	err = runtime.BindQueryParameter("form", true, false, "since", r.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "since", Err: err})
+		return
+	}
 
-	var err error
+	// ------------- Optional query parameter "until" -------------
 
-	// ------------- Path parameter "id" -------------
-	var id AccountId
+	err = runtime.BindQueryParameter("form", true, false, "until", r.URL.Query(), &params.Until)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "until", Err: err})
+		return
+	}
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	// ------------- Optional query parameter "external_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "external_id", r.URL.Query(), &params.ExternalId)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "external_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "metadata" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "metadata", r.URL.Query(), &params.Metadata)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "metadata", Err: err})
 		return
 	}
 
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.Mint(w, r, id)
+		siw.Handler.Accounts(w, r, params)
 	})
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -200,8 +1035,23 @@ func (siw *ServerInterfaceWrapper) Mint(w http.ResponseWriter, r *http.Request)
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// Spend operation middleware
-func (siw *ServerInterfaceWrapper) Spend(w http.ResponseWriter, r *http.Request) {
+// Register operation middleware
+func (siw *ServerInterfaceWrapper) Register(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Register(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Account operation middleware
+func (siw *ServerInterfaceWrapper) Account(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -216,7 +1066,7 @@ func (siw *ServerInterfaceWrapper) Spend(w http.ResponseWriter, r *http.Request)
 	}
 
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.Spend(w, r, id)
+		siw.Handler.Account(w, r, id)
 	})
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -226,8 +1076,8 @@ func (siw *ServerInterfaceWrapper) Spend(w http.ResponseWriter, r *http.Request)
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// Transactions operation middleware
-func (siw *ServerInterfaceWrapper) Transactions(w http.ResponseWriter, r *http.Request) {
+// UpdateAccount operation middleware
+func (siw *ServerInterfaceWrapper) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -242,7 +1092,7 @@ func (siw *ServerInterfaceWrapper) Transactions(w http.ResponseWriter, r *http.R
 	}
 
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.Transactions(w, r, id)
+		siw.Handler.UpdateAccount(w, r, id)
 	})
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -252,8 +1102,8 @@ func (siw *ServerInterfaceWrapper) Transactions(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// Transfer operation middleware
-func (siw *ServerInterfaceWrapper) Transfer(w http.ResponseWriter, r *http.Request) {
+// Balance operation middleware
+func (siw *ServerInterfaceWrapper) Balance(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -267,8 +1117,19 @@ func (siw *ServerInterfaceWrapper) Transfer(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params BalanceParams
+
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", r.URL.Query(), &params.Currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
 	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.Transfer(w, r, id)
+		siw.Handler.Balance(w, r, id, params)
 	})
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -278,313 +1139,3040 @@ func (siw *ServerInterfaceWrapper) Transfer(w http.ResponseWriter, r *http.Reque
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
-}
-
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
-}
+// Balances operation middleware
+func (siw *ServerInterfaceWrapper) Balances(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
-}
+	var err error
 
-type UnmarshallingParamError struct {
-	ParamName string
-	Err       error
-}
+	// ------------- Path parameter "id" -------------
+	var id AccountId
 
-func (e *UnmarshallingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshalling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
-}
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-func (e *UnmarshallingParamError) Unwrap() error {
-	return e.Err
-}
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Balances(w, r, id)
+	})
 
-type RequiredParamError struct {
-	ParamName string
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-func (e *RequiredParamError) Error() string {
-	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type RequiredHeaderError struct {
-	ParamName string
-	Err       error
-}
+// Convert operation middleware
+func (siw *ServerInterfaceWrapper) Convert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-func (e *RequiredHeaderError) Error() string {
-	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
-}
+	var err error
 
-func (e *RequiredHeaderError) Unwrap() error {
-	return e.Err
-}
+	// ------------- Path parameter "id" -------------
+	var id AccountId
 
-type InvalidParamFormatError struct {
-	ParamName string
-	Err       error
-}
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-func (e *InvalidParamFormatError) Error() string {
-	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
-}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ConvertParams
 
-func (e *InvalidParamFormatError) Unwrap() error {
-	return e.Err
-}
+	headers := r.Header
 
-type TooManyValuesForParamError struct {
-	ParamName string
-	Count     int
-}
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
 
-func (e *TooManyValuesForParamError) Error() string {
-	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
-}
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{})
-}
+		params.IdempotencyKey = &IdempotencyKey
 
-type ChiServerOptions struct {
-	BaseURL          string
-	BaseRouter       chi.Router
-	Middlewares      []MiddlewareFunc
-	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
-}
+	}
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
-func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{
-		BaseRouter: r,
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Convert(w, r, id, params)
 	})
-}
 
-func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
-	return HandlerWithOptions(si, ChiServerOptions{
-		BaseURL:    baseURL,
-		BaseRouter: r,
-	})
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// HandlerWithOptions creates http.Handler with additional options
-func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
-	r := options.BaseRouter
+// Escrows operation middleware
+func (siw *ServerInterfaceWrapper) Escrows(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-	if r == nil {
-		r = chi.NewRouter()
-	}
-	if options.ErrorHandlerFunc == nil {
-		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		}
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
 	}
-	wrapper := ServerInterfaceWrapper{
-		Handler:            si,
-		HandlerMiddlewares: options.Middlewares,
-		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params EscrowsParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
 	}
 
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/", wrapper.Register)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/{id}/balance", wrapper.Balance)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/{id}/mint", wrapper.Mint)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/{id}/spend", wrapper.Spend)
-	})
-	r.Group(func(r chi.Router) {
-		r.Get(options.BaseURL+"/{id}/transactions", wrapper.Transactions)
-	})
-	r.Group(func(r chi.Router) {
-		r.Post(options.BaseURL+"/{id}/transfer", wrapper.Transfer)
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Escrows(w, r, id, params)
 	})
 
-	return r
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type RegisterRequestObject struct {
-	Body *RegisterJSONRequestBody
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-type RegisterResponseObject interface {
-	VisitRegisterResponse(w http.ResponseWriter) error
-}
+// CreateEscrow operation middleware
+func (siw *ServerInterfaceWrapper) CreateEscrow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-type Register200JSONResponse struct {
-	AccountId *int `json:"accountId,omitempty"`
-}
+	var err error
 
-func (response Register200JSONResponse) VisitRegisterResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	// ------------- Path parameter "id" -------------
+	var id AccountId
 
-	return json.NewEncoder(w).Encode(response)
-}
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-type BalanceRequestObject struct {
-	Id AccountId `json:"id"`
-}
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateEscrow(w, r, id)
+	})
 
-type BalanceResponseObject interface {
-	VisitBalanceResponse(w http.ResponseWriter) error
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type Balance200JSONResponse struct {
-	Balance int `json:"balance"`
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-func (response Balance200JSONResponse) VisitBalanceResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+// Escrow operation middleware
+func (siw *ServerInterfaceWrapper) Escrow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-	return json.NewEncoder(w).Encode(response)
-}
+	var err error
 
-type MintRequestObject struct {
-	Id   AccountId `json:"id"`
-	Body *MintJSONRequestBody
-}
+	// ------------- Path parameter "id" -------------
+	var id AccountId
 
-type MintResponseObject interface {
-	VisitMintResponse(w http.ResponseWriter) error
-}
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
 
-type Mint200JSONResponse struct {
-	TransactionId int `json:"transactionId"`
-}
+	// ------------- Path parameter "escrowId" -------------
+	var escrowId EscrowId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "escrowId", runtime.ParamLocationPath, chi.URLParam(r, "escrowId"), &escrowId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "escrowId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Escrow(w, r, id, escrowId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ConfirmEscrow operation middleware
+func (siw *ServerInterfaceWrapper) ConfirmEscrow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "escrowId" -------------
+	var escrowId EscrowId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "escrowId", runtime.ParamLocationPath, chi.URLParam(r, "escrowId"), &escrowId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "escrowId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ConfirmEscrow(w, r, id, escrowId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DisputeEscrow operation middleware
+func (siw *ServerInterfaceWrapper) DisputeEscrow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "escrowId" -------------
+	var escrowId EscrowId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "escrowId", runtime.ParamLocationPath, chi.URLParam(r, "escrowId"), &escrowId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "escrowId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DisputeEscrow(w, r, id, escrowId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Hold operation middleware
+func (siw *ServerInterfaceWrapper) Hold(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Hold(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Capture operation middleware
+func (siw *ServerInterfaceWrapper) Capture(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "holdId" -------------
+	var holdId HoldId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "holdId", runtime.ParamLocationPath, chi.URLParam(r, "holdId"), &holdId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "holdId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Capture(w, r, id, holdId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Release operation middleware
+func (siw *ServerInterfaceWrapper) Release(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "holdId" -------------
+	var holdId HoldId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "holdId", runtime.ParamLocationPath, chi.URLParam(r, "holdId"), &holdId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "holdId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Release(w, r, id, holdId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Mint operation middleware
+func (siw *ServerInterfaceWrapper) Mint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params MintParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Mint(w, r, id, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PaymentRequests operation middleware
+func (siw *ServerInterfaceWrapper) PaymentRequests(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PaymentRequestsParams
+
+	// ------------- Optional query parameter "direction" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "direction", r.URL.Query(), &params.Direction)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "direction", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PaymentRequests(w, r, id, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreatePaymentRequest operation middleware
+func (siw *ServerInterfaceWrapper) CreatePaymentRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreatePaymentRequest(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PaymentRequest operation middleware
+func (siw *ServerInterfaceWrapper) PaymentRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "requestId" -------------
+	var requestId PaymentRequestId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "requestId", runtime.ParamLocationPath, chi.URLParam(r, "requestId"), &requestId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "requestId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PaymentRequest(w, r, id, requestId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// AcceptPaymentRequest operation middleware
+func (siw *ServerInterfaceWrapper) AcceptPaymentRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "requestId" -------------
+	var requestId PaymentRequestId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "requestId", runtime.ParamLocationPath, chi.URLParam(r, "requestId"), &requestId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "requestId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.AcceptPaymentRequest(w, r, id, requestId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeclinePaymentRequest operation middleware
+func (siw *ServerInterfaceWrapper) DeclinePaymentRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "requestId" -------------
+	var requestId PaymentRequestId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "requestId", runtime.ParamLocationPath, chi.URLParam(r, "requestId"), &requestId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "requestId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeclinePaymentRequest(w, r, id, requestId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Quote operation middleware
+func (siw *ServerInterfaceWrapper) Quote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Quote(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Schedules operation middleware
+func (siw *ServerInterfaceWrapper) Schedules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Schedules(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateSchedule operation middleware
+func (siw *ServerInterfaceWrapper) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateSchedule(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CancelSchedule operation middleware
+func (siw *ServerInterfaceWrapper) CancelSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "scheduleId" -------------
+	var scheduleId ScheduleId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "scheduleId", runtime.ParamLocationPath, chi.URLParam(r, "scheduleId"), &scheduleId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "scheduleId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CancelSchedule(w, r, id, scheduleId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Schedule operation middleware
+func (siw *ServerInterfaceWrapper) Schedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "scheduleId" -------------
+	var scheduleId ScheduleId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "scheduleId", runtime.ParamLocationPath, chi.URLParam(r, "scheduleId"), &scheduleId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "scheduleId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Schedule(w, r, id, scheduleId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// UpdateSchedule operation middleware
+func (siw *ServerInterfaceWrapper) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "scheduleId" -------------
+	var scheduleId ScheduleId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "scheduleId", runtime.ParamLocationPath, chi.URLParam(r, "scheduleId"), &scheduleId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "scheduleId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateSchedule(w, r, id, scheduleId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PauseSchedule operation middleware
+func (siw *ServerInterfaceWrapper) PauseSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "scheduleId" -------------
+	var scheduleId ScheduleId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "scheduleId", runtime.ParamLocationPath, chi.URLParam(r, "scheduleId"), &scheduleId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "scheduleId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PauseSchedule(w, r, id, scheduleId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ResumeSchedule operation middleware
+func (siw *ServerInterfaceWrapper) ResumeSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "scheduleId" -------------
+	var scheduleId ScheduleId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "scheduleId", runtime.ParamLocationPath, chi.URLParam(r, "scheduleId"), &scheduleId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "scheduleId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ResumeSchedule(w, r, id, scheduleId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ScheduleRuns operation middleware
+func (siw *ServerInterfaceWrapper) ScheduleRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "scheduleId" -------------
+	var scheduleId ScheduleId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "scheduleId", runtime.ParamLocationPath, chi.URLParam(r, "scheduleId"), &scheduleId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "scheduleId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ScheduleRunsParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ScheduleRuns(w, r, id, scheduleId, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Spend operation middleware
+func (siw *ServerInterfaceWrapper) Spend(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SpendParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Spend(w, r, id, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Transactions operation middleware
+func (siw *ServerInterfaceWrapper) Transactions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params TransactionsParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "after", r.URL.Query(), &params.After)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "type" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "type", r.URL.Query(), &params.Type)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "type", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "direction" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "direction", r.URL.Query(), &params.Direction)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "direction", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "counterparty" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "counterparty", r.URL.Query(), &params.Counterparty)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "counterparty", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "min_amount" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "min_amount", r.URL.Query(), &params.MinAmount)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "min_amount", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "max_amount" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_amount", r.URL.Query(), &params.MaxAmount)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "max_amount", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", r.URL.Query(), &params.Currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", r.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "since", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "until" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "until", r.URL.Query(), &params.Until)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "until", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "metadata" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "metadata", r.URL.Query(), &params.Metadata)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "metadata", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "memo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "memo", r.URL.Query(), &params.Memo)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "memo", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Transactions(w, r, id, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Reverse operation middleware
+func (siw *ServerInterfaceWrapper) Reverse(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "txId" -------------
+	var txId TransactionId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "txId", runtime.ParamLocationPath, chi.URLParam(r, "txId"), &txId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "txId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ReverseParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Reverse(w, r, id, txId, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Transfer operation middleware
+func (siw *ServerInterfaceWrapper) Transfer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params TransferParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Transfer(w, r, id, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// TransferBatch operation middleware
+func (siw *ServerInterfaceWrapper) TransferBatch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.TransferBatch(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshallingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshallingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshalling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshallingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/", wrapper.Accounts)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/", wrapper.Register)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/{id}", wrapper.Account)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/{id}", wrapper.UpdateAccount)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/{id}/balance", wrapper.Balance)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/{id}/balances", wrapper.Balances)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/convert", wrapper.Convert)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/{id}/escrows", wrapper.Escrows)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/escrows", wrapper.CreateEscrow)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/{id}/escrows/{escrowId}", wrapper.Escrow)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/escrows/{escrowId}/confirm", wrapper.ConfirmEscrow)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/escrows/{escrowId}/dispute", wrapper.DisputeEscrow)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/holds", wrapper.Hold)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/holds/{holdId}/capture", wrapper.Capture)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/holds/{holdId}/release", wrapper.Release)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/mint", wrapper.Mint)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/{id}/payment_requests", wrapper.PaymentRequests)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/payment_requests", wrapper.CreatePaymentRequest)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/{id}/payment_requests/{requestId}", wrapper.PaymentRequest)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/payment_requests/{requestId}/accept", wrapper.AcceptPaymentRequest)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/payment_requests/{requestId}/decline", wrapper.DeclinePaymentRequest)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/quotes", wrapper.Quote)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/{id}/schedules", wrapper.Schedules)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/schedules", wrapper.CreateSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/{id}/schedules/{scheduleId}", wrapper.CancelSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/{id}/schedules/{scheduleId}", wrapper.Schedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/{id}/schedules/{scheduleId}", wrapper.UpdateSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/schedules/{scheduleId}/pause", wrapper.PauseSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/schedules/{scheduleId}/resume", wrapper.ResumeSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/{id}/schedules/{scheduleId}/runs", wrapper.ScheduleRuns)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/spend", wrapper.Spend)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/{id}/transactions", wrapper.Transactions)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/transactions/{txId}/reverse", wrapper.Reverse)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/transfer", wrapper.Transfer)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/{id}/transfers/batch", wrapper.TransferBatch)
+	})
+
+	return r
+}
+
+type AccountsRequestObject struct {
+	Params AccountsParams
+}
+
+type AccountsResponseObject interface {
+	VisitAccountsResponse(w http.ResponseWriter) error
+}
+
+type Accounts200ResponseHeaders struct {
+	NextCursor string
+}
+
+type Accounts200JSONResponse struct {
+	Body    []Account
+	Headers Accounts200ResponseHeaders
+}
+
+func (response Accounts200JSONResponse) VisitAccountsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Next-Cursor", fmt.Sprint(response.Headers.NextCursor))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RegisterRequestObject struct {
+	Body *RegisterJSONRequestBody
+}
+
+type RegisterResponseObject interface {
+	VisitRegisterResponse(w http.ResponseWriter) error
+}
+
+type Register200JSONResponse struct {
+	AccountId *int `json:"accountId,omitempty"`
+}
+
+func (response Register200JSONResponse) VisitRegisterResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AccountRequestObject struct {
+	Id AccountId `json:"id"`
+}
+
+type AccountResponseObject interface {
+	VisitAccountResponse(w http.ResponseWriter) error
+}
+
+type Account200JSONResponse Account
+
+func (response Account200JSONResponse) VisitAccountResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateAccountRequestObject struct {
+	Id   AccountId `json:"id"`
+	Body *UpdateAccountJSONRequestBody
+}
+
+type UpdateAccountResponseObject interface {
+	VisitUpdateAccountResponse(w http.ResponseWriter) error
+}
+
+type UpdateAccount200JSONResponse Account
+
+func (response UpdateAccount200JSONResponse) VisitUpdateAccountResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type BalanceRequestObject struct {
+	Id     AccountId `json:"id"`
+	Params BalanceParams
+}
+
+type BalanceResponseObject interface {
+	VisitBalanceResponse(w http.ResponseWriter) error
+}
+
+type Balance200JSONResponse Balance
+
+func (response Balance200JSONResponse) VisitBalanceResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type BalancesRequestObject struct {
+	Id AccountId `json:"id"`
+}
+
+type BalancesResponseObject interface {
+	VisitBalancesResponse(w http.ResponseWriter) error
+}
+
+type Balances200JSONResponse []Balance
+
+func (response Balances200JSONResponse) VisitBalancesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ConvertRequestObject struct {
+	Id     AccountId `json:"id"`
+	Params ConvertParams
+	Body   *ConvertJSONRequestBody
+}
+
+type ConvertResponseObject interface {
+	VisitConvertResponse(w http.ResponseWriter) error
+}
+
+type Convert200JSONResponse struct {
+	TransactionId int `json:"transactionId"`
+}
+
+func (response Convert200JSONResponse) VisitConvertResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type EscrowsRequestObject struct {
+	Id     AccountId `json:"id"`
+	Params EscrowsParams
+}
+
+type EscrowsResponseObject interface {
+	VisitEscrowsResponse(w http.ResponseWriter) error
+}
+
+type Escrows200JSONResponse []Escrow
+
+func (response Escrows200JSONResponse) VisitEscrowsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateEscrowRequestObject struct {
+	Id   AccountId `json:"id"`
+	Body *CreateEscrowJSONRequestBody
+}
+
+type CreateEscrowResponseObject interface {
+	VisitCreateEscrowResponse(w http.ResponseWriter) error
+}
+
+type CreateEscrow200JSONResponse Escrow
+
+func (response CreateEscrow200JSONResponse) VisitCreateEscrowResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateEscrow422JSONResponse LimitExceeded
+
+func (response CreateEscrow422JSONResponse) VisitCreateEscrowResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type EscrowRequestObject struct {
+	Id       AccountId `json:"id"`
+	EscrowId EscrowId  `json:"escrowId"`
+}
+
+type EscrowResponseObject interface {
+	VisitEscrowResponse(w http.ResponseWriter) error
+}
+
+type Escrow200JSONResponse Escrow
+
+func (response Escrow200JSONResponse) VisitEscrowResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ConfirmEscrowRequestObject struct {
+	Id       AccountId `json:"id"`
+	EscrowId EscrowId  `json:"escrowId"`
+}
+
+type ConfirmEscrowResponseObject interface {
+	VisitConfirmEscrowResponse(w http.ResponseWriter) error
+}
+
+type ConfirmEscrow200JSONResponse Escrow
+
+func (response ConfirmEscrow200JSONResponse) VisitConfirmEscrowResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DisputeEscrowRequestObject struct {
+	Id       AccountId `json:"id"`
+	EscrowId EscrowId  `json:"escrowId"`
+}
+
+type DisputeEscrowResponseObject interface {
+	VisitDisputeEscrowResponse(w http.ResponseWriter) error
+}
+
+type DisputeEscrow200JSONResponse Escrow
+
+func (response DisputeEscrow200JSONResponse) VisitDisputeEscrowResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type HoldRequestObject struct {
+	Id   AccountId `json:"id"`
+	Body *HoldJSONRequestBody
+}
+
+type HoldResponseObject interface {
+	VisitHoldResponse(w http.ResponseWriter) error
+}
+
+type Hold200JSONResponse Hold
+
+func (response Hold200JSONResponse) VisitHoldResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CaptureRequestObject struct {
+	Id     AccountId `json:"id"`
+	HoldId HoldId    `json:"holdId"`
+	Body   *CaptureJSONRequestBody
+}
+
+type CaptureResponseObject interface {
+	VisitCaptureResponse(w http.ResponseWriter) error
+}
+
+type Capture200JSONResponse struct {
+	TransactionId int `json:"transactionId"`
+}
+
+func (response Capture200JSONResponse) VisitCaptureResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ReleaseRequestObject struct {
+	Id     AccountId `json:"id"`
+	HoldId HoldId    `json:"holdId"`
+}
+
+type ReleaseResponseObject interface {
+	VisitReleaseResponse(w http.ResponseWriter) error
+}
+
+type Release200JSONResponse Hold
+
+func (response Release200JSONResponse) VisitReleaseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type MintRequestObject struct {
+	Id     AccountId `json:"id"`
+	Params MintParams
+	Body   *MintJSONRequestBody
+}
+
+type MintResponseObject interface {
+	VisitMintResponse(w http.ResponseWriter) error
+}
+
+type Mint200JSONResponse struct {
+	TransactionId int `json:"transactionId"`
+}
+
+func (response Mint200JSONResponse) VisitMintResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PaymentRequestsRequestObject struct {
+	Id     AccountId `json:"id"`
+	Params PaymentRequestsParams
+}
+
+type PaymentRequestsResponseObject interface {
+	VisitPaymentRequestsResponse(w http.ResponseWriter) error
+}
+
+type PaymentRequests200JSONResponse []PaymentRequest
+
+func (response PaymentRequests200JSONResponse) VisitPaymentRequestsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreatePaymentRequestRequestObject struct {
+	Id   AccountId `json:"id"`
+	Body *CreatePaymentRequestJSONRequestBody
+}
+
+type CreatePaymentRequestResponseObject interface {
+	VisitCreatePaymentRequestResponse(w http.ResponseWriter) error
+}
+
+type CreatePaymentRequest200JSONResponse PaymentRequest
+
+func (response CreatePaymentRequest200JSONResponse) VisitCreatePaymentRequestResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PaymentRequestRequestObject struct {
+	Id        AccountId        `json:"id"`
+	RequestId PaymentRequestId `json:"requestId"`
+}
+
+type PaymentRequestResponseObject interface {
+	VisitPaymentRequestResponse(w http.ResponseWriter) error
+}
+
+type PaymentRequest200JSONResponse PaymentRequest
+
+func (response PaymentRequest200JSONResponse) VisitPaymentRequestResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AcceptPaymentRequestRequestObject struct {
+	Id        AccountId        `json:"id"`
+	RequestId PaymentRequestId `json:"requestId"`
+}
+
+type AcceptPaymentRequestResponseObject interface {
+	VisitAcceptPaymentRequestResponse(w http.ResponseWriter) error
+}
+
+type AcceptPaymentRequest200JSONResponse PaymentRequest
+
+func (response AcceptPaymentRequest200JSONResponse) VisitAcceptPaymentRequestResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type AcceptPaymentRequest422JSONResponse LimitExceeded
+
+func (response AcceptPaymentRequest422JSONResponse) VisitAcceptPaymentRequestResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeclinePaymentRequestRequestObject struct {
+	Id        AccountId        `json:"id"`
+	RequestId PaymentRequestId `json:"requestId"`
+}
+
+type DeclinePaymentRequestResponseObject interface {
+	VisitDeclinePaymentRequestResponse(w http.ResponseWriter) error
+}
+
+type DeclinePaymentRequest200JSONResponse PaymentRequest
+
+func (response DeclinePaymentRequest200JSONResponse) VisitDeclinePaymentRequestResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type QuoteRequestObject struct {
+	Id   AccountId `json:"id"`
+	Body *QuoteJSONRequestBody
+}
+
+type QuoteResponseObject interface {
+	VisitQuoteResponse(w http.ResponseWriter) error
+}
+
+type Quote200JSONResponse Quote
+
+func (response Quote200JSONResponse) VisitQuoteResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SchedulesRequestObject struct {
+	Id AccountId `json:"id"`
+}
+
+type SchedulesResponseObject interface {
+	VisitSchedulesResponse(w http.ResponseWriter) error
+}
+
+type Schedules200JSONResponse []Schedule
+
+func (response Schedules200JSONResponse) VisitSchedulesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateScheduleRequestObject struct {
+	Id   AccountId `json:"id"`
+	Body *CreateScheduleJSONRequestBody
+}
+
+type CreateScheduleResponseObject interface {
+	VisitCreateScheduleResponse(w http.ResponseWriter) error
+}
+
+type CreateSchedule200JSONResponse Schedule
+
+func (response CreateSchedule200JSONResponse) VisitCreateScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CancelScheduleRequestObject struct {
+	Id         AccountId  `json:"id"`
+	ScheduleId ScheduleId `json:"scheduleId"`
+}
+
+type CancelScheduleResponseObject interface {
+	VisitCancelScheduleResponse(w http.ResponseWriter) error
+}
+
+type CancelSchedule200JSONResponse Schedule
+
+func (response CancelSchedule200JSONResponse) VisitCancelScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ScheduleRequestObject struct {
+	Id         AccountId  `json:"id"`
+	ScheduleId ScheduleId `json:"scheduleId"`
+}
+
+type ScheduleResponseObject interface {
+	VisitScheduleResponse(w http.ResponseWriter) error
+}
+
+type Schedule200JSONResponse Schedule
+
+func (response Schedule200JSONResponse) VisitScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateScheduleRequestObject struct {
+	Id         AccountId  `json:"id"`
+	ScheduleId ScheduleId `json:"scheduleId"`
+	Body       *UpdateScheduleJSONRequestBody
+}
+
+type UpdateScheduleResponseObject interface {
+	VisitUpdateScheduleResponse(w http.ResponseWriter) error
+}
+
+type UpdateSchedule200JSONResponse Schedule
+
+func (response UpdateSchedule200JSONResponse) VisitUpdateScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PauseScheduleRequestObject struct {
+	Id         AccountId  `json:"id"`
+	ScheduleId ScheduleId `json:"scheduleId"`
+}
+
+type PauseScheduleResponseObject interface {
+	VisitPauseScheduleResponse(w http.ResponseWriter) error
+}
+
+type PauseSchedule200JSONResponse Schedule
+
+func (response PauseSchedule200JSONResponse) VisitPauseScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ResumeScheduleRequestObject struct {
+	Id         AccountId  `json:"id"`
+	ScheduleId ScheduleId `json:"scheduleId"`
+}
+
+type ResumeScheduleResponseObject interface {
+	VisitResumeScheduleResponse(w http.ResponseWriter) error
+}
+
+type ResumeSchedule200JSONResponse Schedule
+
+func (response ResumeSchedule200JSONResponse) VisitResumeScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ScheduleRunsRequestObject struct {
+	Id         AccountId  `json:"id"`
+	ScheduleId ScheduleId `json:"scheduleId"`
+	Params     ScheduleRunsParams
+}
+
+type ScheduleRunsResponseObject interface {
+	VisitScheduleRunsResponse(w http.ResponseWriter) error
+}
+
+type ScheduleRuns200JSONResponse []ScheduleRun
+
+func (response ScheduleRuns200JSONResponse) VisitScheduleRunsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SpendRequestObject struct {
+	Id     AccountId `json:"id"`
+	Params SpendParams
+	Body   *SpendJSONRequestBody
+}
+
+type SpendResponseObject interface {
+	VisitSpendResponse(w http.ResponseWriter) error
+}
+
+type Spend200JSONResponse struct {
+	TransactionId int `json:"transactionId"`
+}
+
+func (response Spend200JSONResponse) VisitSpendResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type Spend422JSONResponse LimitExceeded
+
+func (response Spend422JSONResponse) VisitSpendResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type TransactionsRequestObject struct {
+	Id     AccountId `json:"id"`
+	Params TransactionsParams
+}
+
+type TransactionsResponseObject interface {
+	VisitTransactionsResponse(w http.ResponseWriter) error
+}
+
+type Transactions200ResponseHeaders struct {
+	NextCursor string
+}
+
+type Transactions200JSONResponse struct {
+	Body    []interface{}
+	Headers Transactions200ResponseHeaders
+}
+
+func (response Transactions200JSONResponse) VisitTransactionsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Next-Cursor", fmt.Sprint(response.Headers.NextCursor))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ReverseRequestObject struct {
+	Id     AccountId     `json:"id"`
+	TxId   TransactionId `json:"txId"`
+	Params ReverseParams
+	Body   *ReverseJSONRequestBody
+}
+
+type ReverseResponseObject interface {
+	VisitReverseResponse(w http.ResponseWriter) error
+}
+
+type Reverse200JSONResponse struct {
+	TransactionId int `json:"transactionId"`
+}
+
+func (response Reverse200JSONResponse) VisitReverseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type TransferRequestObject struct {
+	Id     AccountId `json:"id"`
+	Params TransferParams
+	Body   *TransferJSONRequestBody
+}
+
+type TransferResponseObject interface {
+	VisitTransferResponse(w http.ResponseWriter) error
+}
+
+type Transfer200JSONResponse struct {
+	TransactionId int `json:"transactionId"`
+}
+
+func (response Transfer200JSONResponse) VisitTransferResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type Transfer422JSONResponse LimitExceeded
+
+func (response Transfer422JSONResponse) VisitTransferResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type TransferBatchRequestObject struct {
+	Id   AccountId `json:"id"`
+	Body *TransferBatchJSONRequestBody
+}
+
+type TransferBatchResponseObject interface {
+	VisitTransferBatchResponse(w http.ResponseWriter) error
+}
+
+type TransferBatch200JSONResponse struct {
+	// TransactionIds transfersと同じ順序の、それぞれの送金のtransaction
+	TransactionIds []int `json:"transactionIds"`
+}
+
+func (response TransferBatch200JSONResponse) VisitTransferBatchResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type TransferBatch400JSONResponse struct {
+	Errors []TransferItemError `json:"errors"`
+}
+
+func (response TransferBatch400JSONResponse) VisitTransferBatchResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type TransferBatch422JSONResponse LimitExceeded
+
+func (response TransferBatch422JSONResponse) VisitTransferBatchResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+
+	// (GET /)
+	Accounts(ctx context.Context, request AccountsRequestObject) (AccountsResponseObject, error)
+
+	// (POST /)
+	Register(ctx context.Context, request RegisterRequestObject) (RegisterResponseObject, error)
+
+	// (GET /{id})
+	Account(ctx context.Context, request AccountRequestObject) (AccountResponseObject, error)
+
+	// (PATCH /{id})
+	UpdateAccount(ctx context.Context, request UpdateAccountRequestObject) (UpdateAccountResponseObject, error)
+
+	// (GET /{id}/balance)
+	Balance(ctx context.Context, request BalanceRequestObject) (BalanceResponseObject, error)
+
+	// (GET /{id}/balances)
+	Balances(ctx context.Context, request BalancesRequestObject) (BalancesResponseObject, error)
+
+	// (POST /{id}/convert)
+	Convert(ctx context.Context, request ConvertRequestObject) (ConvertResponseObject, error)
+
+	// (GET /{id}/escrows)
+	Escrows(ctx context.Context, request EscrowsRequestObject) (EscrowsResponseObject, error)
+
+	// (POST /{id}/escrows)
+	CreateEscrow(ctx context.Context, request CreateEscrowRequestObject) (CreateEscrowResponseObject, error)
+
+	// (GET /{id}/escrows/{escrowId})
+	Escrow(ctx context.Context, request EscrowRequestObject) (EscrowResponseObject, error)
+
+	// (POST /{id}/escrows/{escrowId}/confirm)
+	ConfirmEscrow(ctx context.Context, request ConfirmEscrowRequestObject) (ConfirmEscrowResponseObject, error)
+
+	// (POST /{id}/escrows/{escrowId}/dispute)
+	DisputeEscrow(ctx context.Context, request DisputeEscrowRequestObject) (DisputeEscrowResponseObject, error)
+
+	// (POST /{id}/holds)
+	Hold(ctx context.Context, request HoldRequestObject) (HoldResponseObject, error)
+
+	// (POST /{id}/holds/{holdId}/capture)
+	Capture(ctx context.Context, request CaptureRequestObject) (CaptureResponseObject, error)
+
+	// (POST /{id}/holds/{holdId}/release)
+	Release(ctx context.Context, request ReleaseRequestObject) (ReleaseResponseObject, error)
+
+	// (POST /{id}/mint)
+	Mint(ctx context.Context, request MintRequestObject) (MintResponseObject, error)
+
+	// (GET /{id}/payment_requests)
+	PaymentRequests(ctx context.Context, request PaymentRequestsRequestObject) (PaymentRequestsResponseObject, error)
+
+	// (POST /{id}/payment_requests)
+	CreatePaymentRequest(ctx context.Context, request CreatePaymentRequestRequestObject) (CreatePaymentRequestResponseObject, error)
+
+	// (GET /{id}/payment_requests/{requestId})
+	PaymentRequest(ctx context.Context, request PaymentRequestRequestObject) (PaymentRequestResponseObject, error)
+
+	// (POST /{id}/payment_requests/{requestId}/accept)
+	AcceptPaymentRequest(ctx context.Context, request AcceptPaymentRequestRequestObject) (AcceptPaymentRequestResponseObject, error)
+
+	// (POST /{id}/payment_requests/{requestId}/decline)
+	DeclinePaymentRequest(ctx context.Context, request DeclinePaymentRequestRequestObject) (DeclinePaymentRequestResponseObject, error)
+
+	// (POST /{id}/quotes)
+	Quote(ctx context.Context, request QuoteRequestObject) (QuoteResponseObject, error)
+
+	// (GET /{id}/schedules)
+	Schedules(ctx context.Context, request SchedulesRequestObject) (SchedulesResponseObject, error)
+
+	// (POST /{id}/schedules)
+	CreateSchedule(ctx context.Context, request CreateScheduleRequestObject) (CreateScheduleResponseObject, error)
+
+	// (DELETE /{id}/schedules/{scheduleId})
+	CancelSchedule(ctx context.Context, request CancelScheduleRequestObject) (CancelScheduleResponseObject, error)
+
+	// (GET /{id}/schedules/{scheduleId})
+	Schedule(ctx context.Context, request ScheduleRequestObject) (ScheduleResponseObject, error)
+
+	// (PATCH /{id}/schedules/{scheduleId})
+	UpdateSchedule(ctx context.Context, request UpdateScheduleRequestObject) (UpdateScheduleResponseObject, error)
+
+	// (POST /{id}/schedules/{scheduleId}/pause)
+	PauseSchedule(ctx context.Context, request PauseScheduleRequestObject) (PauseScheduleResponseObject, error)
+
+	// (POST /{id}/schedules/{scheduleId}/resume)
+	ResumeSchedule(ctx context.Context, request ResumeScheduleRequestObject) (ResumeScheduleResponseObject, error)
+
+	// (GET /{id}/schedules/{scheduleId}/runs)
+	ScheduleRuns(ctx context.Context, request ScheduleRunsRequestObject) (ScheduleRunsResponseObject, error)
+
+	// (POST /{id}/spend)
+	Spend(ctx context.Context, request SpendRequestObject) (SpendResponseObject, error)
+
+	// (GET /{id}/transactions)
+	Transactions(ctx context.Context, request TransactionsRequestObject) (TransactionsResponseObject, error)
+
+	// (POST /{id}/transactions/{txId}/reverse)
+	Reverse(ctx context.Context, request ReverseRequestObject) (ReverseResponseObject, error)
+
+	// (POST /{id}/transfer)
+	Transfer(ctx context.Context, request TransferRequestObject) (TransferResponseObject, error)
+
+	// (POST /{id}/transfers/batch)
+	TransferBatch(ctx context.Context, request TransferBatchRequestObject) (TransferBatchResponseObject, error)
+}
+
+type StrictHandlerFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request, args interface{}) (interface{}, error)
+
+type StrictMiddlewareFunc func(f StrictHandlerFunc, operationID string) StrictHandlerFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// Accounts operation middleware
+func (sh *strictHandler) Accounts(w http.ResponseWriter, r *http.Request, params AccountsParams) {
+	var request AccountsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Accounts(ctx, request.(AccountsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Accounts")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(AccountsResponseObject); ok {
+		if err := validResponse.VisitAccountsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Register operation middleware
+func (sh *strictHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var request RegisterRequestObject
+
+	var body RegisterJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Register(ctx, request.(RegisterRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Register")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RegisterResponseObject); ok {
+		if err := validResponse.VisitRegisterResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Account operation middleware
+func (sh *strictHandler) Account(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request AccountRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Account(ctx, request.(AccountRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Account")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(AccountResponseObject); ok {
+		if err := validResponse.VisitAccountResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// UpdateAccount operation middleware
+func (sh *strictHandler) UpdateAccount(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request UpdateAccountRequestObject
+
+	request.Id = id
+
+	var body UpdateAccountJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateAccount(ctx, request.(UpdateAccountRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateAccount")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateAccountResponseObject); ok {
+		if err := validResponse.VisitUpdateAccountResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Balance operation middleware
+func (sh *strictHandler) Balance(w http.ResponseWriter, r *http.Request, id AccountId, params BalanceParams) {
+	var request BalanceRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Balance(ctx, request.(BalanceRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Balance")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(BalanceResponseObject); ok {
+		if err := validResponse.VisitBalanceResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Balances operation middleware
+func (sh *strictHandler) Balances(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request BalancesRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Balances(ctx, request.(BalancesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Balances")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(BalancesResponseObject); ok {
+		if err := validResponse.VisitBalancesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Convert operation middleware
+func (sh *strictHandler) Convert(w http.ResponseWriter, r *http.Request, id AccountId, params ConvertParams) {
+	var request ConvertRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	var body ConvertJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Convert(ctx, request.(ConvertRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Convert")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ConvertResponseObject); ok {
+		if err := validResponse.VisitConvertResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Escrows operation middleware
+func (sh *strictHandler) Escrows(w http.ResponseWriter, r *http.Request, id AccountId, params EscrowsParams) {
+	var request EscrowsRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Escrows(ctx, request.(EscrowsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Escrows")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(EscrowsResponseObject); ok {
+		if err := validResponse.VisitEscrowsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// CreateEscrow operation middleware
+func (sh *strictHandler) CreateEscrow(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request CreateEscrowRequestObject
+
+	request.Id = id
+
+	var body CreateEscrowJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateEscrow(ctx, request.(CreateEscrowRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateEscrow")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateEscrowResponseObject); ok {
+		if err := validResponse.VisitCreateEscrowResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Escrow operation middleware
+func (sh *strictHandler) Escrow(w http.ResponseWriter, r *http.Request, id AccountId, escrowId EscrowId) {
+	var request EscrowRequestObject
+
+	request.Id = id
+	request.EscrowId = escrowId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Escrow(ctx, request.(EscrowRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Escrow")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(EscrowResponseObject); ok {
+		if err := validResponse.VisitEscrowResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// ConfirmEscrow operation middleware
+func (sh *strictHandler) ConfirmEscrow(w http.ResponseWriter, r *http.Request, id AccountId, escrowId EscrowId) {
+	var request ConfirmEscrowRequestObject
+
+	request.Id = id
+	request.EscrowId = escrowId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ConfirmEscrow(ctx, request.(ConfirmEscrowRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ConfirmEscrow")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ConfirmEscrowResponseObject); ok {
+		if err := validResponse.VisitConfirmEscrowResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// DisputeEscrow operation middleware
+func (sh *strictHandler) DisputeEscrow(w http.ResponseWriter, r *http.Request, id AccountId, escrowId EscrowId) {
+	var request DisputeEscrowRequestObject
+
+	request.Id = id
+	request.EscrowId = escrowId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DisputeEscrow(ctx, request.(DisputeEscrowRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DisputeEscrow")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DisputeEscrowResponseObject); ok {
+		if err := validResponse.VisitDisputeEscrowResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Hold operation middleware
+func (sh *strictHandler) Hold(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request HoldRequestObject
+
+	request.Id = id
+
+	var body HoldJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Hold(ctx, request.(HoldRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Hold")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(HoldResponseObject); ok {
+		if err := validResponse.VisitHoldResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Capture operation middleware
+func (sh *strictHandler) Capture(w http.ResponseWriter, r *http.Request, id AccountId, holdId HoldId) {
+	var request CaptureRequestObject
+
+	request.Id = id
+	request.HoldId = holdId
+
+	var body CaptureJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Capture(ctx, request.(CaptureRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Capture")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CaptureResponseObject); ok {
+		if err := validResponse.VisitCaptureResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Release operation middleware
+func (sh *strictHandler) Release(w http.ResponseWriter, r *http.Request, id AccountId, holdId HoldId) {
+	var request ReleaseRequestObject
+
+	request.Id = id
+	request.HoldId = holdId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Release(ctx, request.(ReleaseRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Release")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ReleaseResponseObject); ok {
+		if err := validResponse.VisitReleaseResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Mint operation middleware
+func (sh *strictHandler) Mint(w http.ResponseWriter, r *http.Request, id AccountId, params MintParams) {
+	var request MintRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	var body MintJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Mint(ctx, request.(MintRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Mint")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(MintResponseObject); ok {
+		if err := validResponse.VisitMintResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// PaymentRequests operation middleware
+func (sh *strictHandler) PaymentRequests(w http.ResponseWriter, r *http.Request, id AccountId, params PaymentRequestsParams) {
+	var request PaymentRequestsRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PaymentRequests(ctx, request.(PaymentRequestsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PaymentRequests")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PaymentRequestsResponseObject); ok {
+		if err := validResponse.VisitPaymentRequestsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// CreatePaymentRequest operation middleware
+func (sh *strictHandler) CreatePaymentRequest(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request CreatePaymentRequestRequestObject
+
+	request.Id = id
+
+	var body CreatePaymentRequestJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreatePaymentRequest(ctx, request.(CreatePaymentRequestRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreatePaymentRequest")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreatePaymentRequestResponseObject); ok {
+		if err := validResponse.VisitCreatePaymentRequestResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// PaymentRequest operation middleware
+func (sh *strictHandler) PaymentRequest(w http.ResponseWriter, r *http.Request, id AccountId, requestId PaymentRequestId) {
+	var request PaymentRequestRequestObject
+
+	request.Id = id
+	request.RequestId = requestId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PaymentRequest(ctx, request.(PaymentRequestRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PaymentRequest")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PaymentRequestResponseObject); ok {
+		if err := validResponse.VisitPaymentRequestResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// AcceptPaymentRequest operation middleware
+func (sh *strictHandler) AcceptPaymentRequest(w http.ResponseWriter, r *http.Request, id AccountId, requestId PaymentRequestId) {
+	var request AcceptPaymentRequestRequestObject
+
+	request.Id = id
+	request.RequestId = requestId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.AcceptPaymentRequest(ctx, request.(AcceptPaymentRequestRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "AcceptPaymentRequest")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(AcceptPaymentRequestResponseObject); ok {
+		if err := validResponse.VisitAcceptPaymentRequestResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// DeclinePaymentRequest operation middleware
+func (sh *strictHandler) DeclinePaymentRequest(w http.ResponseWriter, r *http.Request, id AccountId, requestId PaymentRequestId) {
+	var request DeclinePaymentRequestRequestObject
+
+	request.Id = id
+	request.RequestId = requestId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeclinePaymentRequest(ctx, request.(DeclinePaymentRequestRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeclinePaymentRequest")
+	}
 
-func (response Mint200JSONResponse) VisitMintResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	response, err := handler(r.Context(), w, r, request)
 
-	return json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeclinePaymentRequestResponseObject); ok {
+		if err := validResponse.VisitDeclinePaymentRequestResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-type SpendRequestObject struct {
-	Id   AccountId `json:"id"`
-	Body *SpendJSONRequestBody
-}
+// Quote operation middleware
+func (sh *strictHandler) Quote(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request QuoteRequestObject
 
-type SpendResponseObject interface {
-	VisitSpendResponse(w http.ResponseWriter) error
-}
+	request.Id = id
 
-type Spend200JSONResponse struct {
-	TransactionId int `json:"transactionId"`
-}
+	var body QuoteJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
-func (response Spend200JSONResponse) VisitSpendResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Quote(ctx, request.(QuoteRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Quote")
+	}
 
-	return json.NewEncoder(w).Encode(response)
-}
+	response, err := handler(r.Context(), w, r, request)
 
-type TransactionsRequestObject struct {
-	Id AccountId `json:"id"`
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(QuoteResponseObject); ok {
+		if err := validResponse.VisitQuoteResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-type TransactionsResponseObject interface {
-	VisitTransactionsResponse(w http.ResponseWriter) error
-}
+// Schedules operation middleware
+func (sh *strictHandler) Schedules(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request SchedulesRequestObject
 
-type Transactions200JSONResponse []interface{}
+	request.Id = id
 
-func (response Transactions200JSONResponse) VisitTransactionsResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Schedules(ctx, request.(SchedulesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Schedules")
+	}
 
-	return json.NewEncoder(w).Encode(response)
-}
+	response, err := handler(r.Context(), w, r, request)
 
-type TransferRequestObject struct {
-	Id   AccountId `json:"id"`
-	Body *TransferJSONRequestBody
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SchedulesResponseObject); ok {
+		if err := validResponse.VisitSchedulesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-type TransferResponseObject interface {
-	VisitTransferResponse(w http.ResponseWriter) error
-}
+// CreateSchedule operation middleware
+func (sh *strictHandler) CreateSchedule(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request CreateScheduleRequestObject
 
-type Transfer200JSONResponse struct {
-	TransactionId int `json:"transactionId"`
-}
+	request.Id = id
 
-func (response Transfer200JSONResponse) VisitTransferResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	var body CreateScheduleJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
-	return json.NewEncoder(w).Encode(response)
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateSchedule(ctx, request.(CreateScheduleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateSchedule")
+	}
 
-// StrictServerInterface represents all server handlers.
-type StrictServerInterface interface {
+	response, err := handler(r.Context(), w, r, request)
 
-	// (POST /)
-	Register(ctx context.Context, request RegisterRequestObject) (RegisterResponseObject, error)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateScheduleResponseObject); ok {
+		if err := validResponse.VisitCreateScheduleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
 
-	// (GET /{id}/balance)
-	Balance(ctx context.Context, request BalanceRequestObject) (BalanceResponseObject, error)
+// CancelSchedule operation middleware
+func (sh *strictHandler) CancelSchedule(w http.ResponseWriter, r *http.Request, id AccountId, scheduleId ScheduleId) {
+	var request CancelScheduleRequestObject
 
-	// (POST /{id}/mint)
-	Mint(ctx context.Context, request MintRequestObject) (MintResponseObject, error)
+	request.Id = id
+	request.ScheduleId = scheduleId
 
-	// (POST /{id}/spend)
-	Spend(ctx context.Context, request SpendRequestObject) (SpendResponseObject, error)
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CancelSchedule(ctx, request.(CancelScheduleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CancelSchedule")
+	}
 
-	// (GET /{id}/transactions)
-	Transactions(ctx context.Context, request TransactionsRequestObject) (TransactionsResponseObject, error)
+	response, err := handler(r.Context(), w, r, request)
 
-	// (POST /{id}/transfer)
-	Transfer(ctx context.Context, request TransferRequestObject) (TransferResponseObject, error)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CancelScheduleResponseObject); ok {
+		if err := validResponse.VisitCancelScheduleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-type StrictHandlerFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request, args interface{}) (interface{}, error)
+// Schedule operation middleware
+func (sh *strictHandler) Schedule(w http.ResponseWriter, r *http.Request, id AccountId, scheduleId ScheduleId) {
+	var request ScheduleRequestObject
 
-type StrictMiddlewareFunc func(f StrictHandlerFunc, operationID string) StrictHandlerFunc
+	request.Id = id
+	request.ScheduleId = scheduleId
 
-type StrictHTTPServerOptions struct {
-	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
-	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Schedule(ctx, request.(ScheduleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Schedule")
+	}
 
-func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
-		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		},
-		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		},
-	}}
-}
+	response, err := handler(r.Context(), w, r, request)
 
-func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ScheduleResponseObject); ok {
+		if err := validResponse.VisitScheduleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
 }
 
-type strictHandler struct {
-	ssi         StrictServerInterface
-	middlewares []StrictMiddlewareFunc
-	options     StrictHTTPServerOptions
-}
+// UpdateSchedule operation middleware
+func (sh *strictHandler) UpdateSchedule(w http.ResponseWriter, r *http.Request, id AccountId, scheduleId ScheduleId) {
+	var request UpdateScheduleRequestObject
 
-// Register operation middleware
-func (sh *strictHandler) Register(w http.ResponseWriter, r *http.Request) {
-	var request RegisterRequestObject
+	request.Id = id
+	request.ScheduleId = scheduleId
 
-	var body RegisterJSONRequestBody
+	var body UpdateScheduleJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
 		return
@@ -592,18 +4180,18 @@ func (sh *strictHandler) Register(w http.ResponseWriter, r *http.Request) {
 	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.Register(ctx, request.(RegisterRequestObject))
+		return sh.ssi.UpdateSchedule(ctx, request.(UpdateScheduleRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "Register")
+		handler = middleware(handler, "UpdateSchedule")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(RegisterResponseObject); ok {
-		if err := validResponse.VisitRegisterResponse(w); err != nil {
+	} else if validResponse, ok := response.(UpdateScheduleResponseObject); ok {
+		if err := validResponse.VisitUpdateScheduleResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -611,25 +4199,26 @@ func (sh *strictHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Balance operation middleware
-func (sh *strictHandler) Balance(w http.ResponseWriter, r *http.Request, id AccountId) {
-	var request BalanceRequestObject
+// PauseSchedule operation middleware
+func (sh *strictHandler) PauseSchedule(w http.ResponseWriter, r *http.Request, id AccountId, scheduleId ScheduleId) {
+	var request PauseScheduleRequestObject
 
 	request.Id = id
+	request.ScheduleId = scheduleId
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.Balance(ctx, request.(BalanceRequestObject))
+		return sh.ssi.PauseSchedule(ctx, request.(PauseScheduleRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "Balance")
+		handler = middleware(handler, "PauseSchedule")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(BalanceResponseObject); ok {
-		if err := validResponse.VisitBalanceResponse(w); err != nil {
+	} else if validResponse, ok := response.(PauseScheduleResponseObject); ok {
+		if err := validResponse.VisitPauseScheduleResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -637,32 +4226,54 @@ func (sh *strictHandler) Balance(w http.ResponseWriter, r *http.Request, id Acco
 	}
 }
 
-// Mint operation middleware
-func (sh *strictHandler) Mint(w http.ResponseWriter, r *http.Request, id AccountId) {
-	var request MintRequestObject
+// ResumeSchedule operation middleware
+func (sh *strictHandler) ResumeSchedule(w http.ResponseWriter, r *http.Request, id AccountId, scheduleId ScheduleId) {
+	var request ResumeScheduleRequestObject
 
 	request.Id = id
+	request.ScheduleId = scheduleId
 
-	var body MintJSONRequestBody
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
-		return
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ResumeSchedule(ctx, request.(ResumeScheduleRequestObject))
 	}
-	request.Body = &body
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ResumeSchedule")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ResumeScheduleResponseObject); ok {
+		if err := validResponse.VisitResumeScheduleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// ScheduleRuns operation middleware
+func (sh *strictHandler) ScheduleRuns(w http.ResponseWriter, r *http.Request, id AccountId, scheduleId ScheduleId, params ScheduleRunsParams) {
+	var request ScheduleRunsRequestObject
+
+	request.Id = id
+	request.ScheduleId = scheduleId
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.Mint(ctx, request.(MintRequestObject))
+		return sh.ssi.ScheduleRuns(ctx, request.(ScheduleRunsRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "Mint")
+		handler = middleware(handler, "ScheduleRuns")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(MintResponseObject); ok {
-		if err := validResponse.VisitMintResponse(w); err != nil {
+	} else if validResponse, ok := response.(ScheduleRunsResponseObject); ok {
+		if err := validResponse.VisitScheduleRunsResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -671,10 +4282,11 @@ func (sh *strictHandler) Mint(w http.ResponseWriter, r *http.Request, id Account
 }
 
 // Spend operation middleware
-func (sh *strictHandler) Spend(w http.ResponseWriter, r *http.Request, id AccountId) {
+func (sh *strictHandler) Spend(w http.ResponseWriter, r *http.Request, id AccountId, params SpendParams) {
 	var request SpendRequestObject
 
 	request.Id = id
+	request.Params = params
 
 	var body SpendJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -704,10 +4316,11 @@ func (sh *strictHandler) Spend(w http.ResponseWriter, r *http.Request, id Accoun
 }
 
 // Transactions operation middleware
-func (sh *strictHandler) Transactions(w http.ResponseWriter, r *http.Request, id AccountId) {
+func (sh *strictHandler) Transactions(w http.ResponseWriter, r *http.Request, id AccountId, params TransactionsParams) {
 	var request TransactionsRequestObject
 
 	request.Id = id
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
 		return sh.ssi.Transactions(ctx, request.(TransactionsRequestObject))
@@ -729,11 +4342,47 @@ func (sh *strictHandler) Transactions(w http.ResponseWriter, r *http.Request, id
 	}
 }
 
+// Reverse operation middleware
+func (sh *strictHandler) Reverse(w http.ResponseWriter, r *http.Request, id AccountId, txId TransactionId, params ReverseParams) {
+	var request ReverseRequestObject
+
+	request.Id = id
+	request.TxId = txId
+	request.Params = params
+
+	var body ReverseJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Reverse(ctx, request.(ReverseRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Reverse")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ReverseResponseObject); ok {
+		if err := validResponse.VisitReverseResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
 // Transfer operation middleware
-func (sh *strictHandler) Transfer(w http.ResponseWriter, r *http.Request, id AccountId) {
+func (sh *strictHandler) Transfer(w http.ResponseWriter, r *http.Request, id AccountId, params TransferParams) {
 	var request TransferRequestObject
 
 	request.Id = id
+	request.Params = params
 
 	var body TransferJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
@@ -761,3 +4410,36 @@ func (sh *strictHandler) Transfer(w http.ResponseWriter, r *http.Request, id Acc
 		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
 	}
 }
+
+// TransferBatch operation middleware
+func (sh *strictHandler) TransferBatch(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request TransferBatchRequestObject
+
+	request.Id = id
+
+	var body TransferBatchJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.TransferBatch(ctx, request.(TransferBatchRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "TransferBatch")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(TransferBatchResponseObject); ok {
+		if err := validResponse.VisitTransferBatchResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
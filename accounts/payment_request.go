@@ -0,0 +1,268 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+// 支払いの請求の有効期間(秒)の既定値と上限
+const (
+	DefaultPaymentRequestTtl = 7 * 24 * 60 * 60
+	MaxPaymentRequestTtl     = 90 * 24 * 60 * 60
+)
+
+// 支払いの請求を取得する件数の既定値と上限
+const (
+	DefaultPaymentRequestsLimit = 100
+	MaxPaymentRequestsLimit     = 1000
+)
+
+// 支払いの請求によるtransferのreferenceのtype
+const PaymentRequestReferenceType = "payment_request"
+
+// requesterがpayerにcurrencyのamountの支払いを求めます。ttl秒を過ぎると支払えなくなります
+func (model *Model) CreatePaymentRequest(ctx context.Context, requester int, payer int, currency string, amount decimal.Decimal, memo *string, ttl int) (PaymentRequest, error) {
+	if payer == requester {
+		return PaymentRequest{}, fmt.Errorf("payer should be different from requester %d: %w", requester, ValidationError)
+	}
+
+	if ttl < 1 || ttl > MaxPaymentRequestTtl {
+		return PaymentRequest{}, fmt.Errorf("ttl should be between 1 and %d: %w", MaxPaymentRequestTtl, ValidationError)
+	}
+
+	for _, id := range []int{requester, payer} {
+		err := model.Exists(ctx, id)
+		if err != nil {
+			return PaymentRequest{}, err
+		}
+	}
+
+	_, err := model.validateAmount(ctx, currency, amount)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+
+	columns, err := newMemoColumns(memo, nil)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+
+	entity, err := model.queries.InsertPaymentRequest(ctx, sqlc.InsertPaymentRequestParams{
+		Requester: int64(requester),
+		Payer:     int64(payer),
+		Currency:  currency,
+		Amount:    amount.String(),
+		Memo:      columns.memo,
+		Ttl:       int32(ttl),
+	})
+	if err != nil {
+		return PaymentRequest{}, fmt.Errorf("query InsertPaymentRequest: %w", err)
+	}
+
+	return mapToPaymentRequest(paymentRequestRow(entity))
+}
+
+// accountIdがrequesterかpayerである支払いの請求を返します
+func (model *Model) GetPaymentRequest(ctx context.Context, accountId int, requestId int) (PaymentRequest, error) {
+	entity, err := model.queries.GetPaymentRequest(ctx, sqlc.GetPaymentRequestParams{
+		ID:      int64(requestId),
+		Account: int64(accountId),
+	})
+	if err == sql.ErrNoRows {
+		return PaymentRequest{}, fmt.Errorf("Not found payment request by id %d: %w", requestId, NotFoundError)
+	}
+	if err != nil {
+		return PaymentRequest{}, fmt.Errorf("query GetPaymentRequest: %w", err)
+	}
+
+	return mapToPaymentRequest(entity)
+}
+
+// accountIdの支払いの請求を新しいものから返します
+// directionがincomingなら支払いを求められたもの、outgoingなら求めたもの、nilなら両方です
+func (model *Model) GetPaymentRequests(ctx context.Context, accountId int, direction *Direction, status *PaymentRequestStatus, limit int) ([]PaymentRequest, error) {
+	if limit < 1 || limit > MaxPaymentRequestsLimit {
+		return nil, fmt.Errorf("limit should be between 1 and %d: %w", MaxPaymentRequestsLimit, ValidationError)
+	}
+
+	err := model.Exists(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	params := sqlc.GetPaymentRequestsParams{
+		Account:  int64(accountId),
+		RowLimit: int32(limit),
+	}
+	if direction != nil {
+		err := direction.validate()
+		if err != nil {
+			return nil, err
+		}
+		params.Direction = sql.NullString{String: string(*direction), Valid: true}
+	}
+	if status != nil {
+		params.Status = sql.NullString{String: string(*status), Valid: true}
+	}
+
+	rows, err := model.queries.GetPaymentRequests(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("query GetPaymentRequests: %w", err)
+	}
+
+	requests := make([]PaymentRequest, 0, len(rows))
+	for _, row := range rows {
+		request, err := mapToPaymentRequest(sqlc.GetPaymentRequestRow(row))
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+// payerが支払いの請求に応じ、payerからrequesterへ送金します
+// 送金は通常のTransferと同じく手数料や残高の確認を伴い、referenceで請求と結び付けます
+func (model *Model) AcceptPaymentRequest(ctx context.Context, payer int, requestId int) (PaymentRequest, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (PaymentRequest, error) {
+		model := model.WithTx(tx)
+
+		request, err := model.lockPendingPaymentRequest(ctx, payer, requestId)
+		if err != nil {
+			return PaymentRequest{}, err
+		}
+
+		amount, err := parseDecimal(request.Amount)
+		if err != nil {
+			return PaymentRequest{}, err
+		}
+
+		var memo *string
+		if request.Memo.Valid {
+			memo = &request.Memo.String
+		}
+		reference := &Reference{Type: PaymentRequestReferenceType, Id: strconv.FormatInt(request.ID, 10)}
+
+		txId, err := model.transfer(ctx, payer, int(request.Requester), request.Currency, amount, memo, reference, nil)
+		if err != nil {
+			return PaymentRequest{}, err
+		}
+
+		return model.updatePaymentRequestStatus(ctx, requestId, sqlc.PaymentRequestStatusPaid, txId)
+	})
+}
+
+// payerが支払いの請求を断ります
+func (model *Model) DeclinePaymentRequest(ctx context.Context, payer int, requestId int) (PaymentRequest, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (PaymentRequest, error) {
+		model := model.WithTx(tx)
+
+		_, err := model.lockPendingPaymentRequest(ctx, payer, requestId)
+		if err != nil {
+			return PaymentRequest{}, err
+		}
+
+		return model.updatePaymentRequestStatus(ctx, requestId, sqlc.PaymentRequestStatusDeclined, 0)
+	})
+}
+
+// payerに宛てた、支払いも拒否もされておらず期限内の請求をロックして返します
+func (model *Model) lockPendingPaymentRequest(ctx context.Context, payer int, requestId int) (sqlc.LockPaymentRequestRow, error) {
+	err := model.Exists(ctx, payer)
+	if err != nil {
+		return sqlc.LockPaymentRequestRow{}, err
+	}
+
+	request, err := model.queries.LockPaymentRequest(ctx, sqlc.LockPaymentRequestParams{
+		ID:    int64(requestId),
+		Payer: int64(payer),
+	})
+	if err == sql.ErrNoRows {
+		return sqlc.LockPaymentRequestRow{}, fmt.Errorf("Not found payment request by id %d: %w", requestId, NotFoundError)
+	}
+	if err != nil {
+		return sqlc.LockPaymentRequestRow{}, fmt.Errorf("query LockPaymentRequest: %w", err)
+	}
+
+	if request.Status != sqlc.PaymentRequestStatusPending {
+		return sqlc.LockPaymentRequestRow{}, fmt.Errorf("payment request %d was already %s: %w", requestId, request.Status, ConflictError)
+	}
+	if request.Expired {
+		return sqlc.LockPaymentRequestRow{}, fmt.Errorf("payment request %d has expired: %w", requestId, DomainError)
+	}
+
+	return request, nil
+}
+
+// txIdが0でなければ、支払った際のtransactionとして記録します
+func (model *Model) updatePaymentRequestStatus(ctx context.Context, requestId int, status sqlc.PaymentRequestStatus, txId int) (PaymentRequest, error) {
+	entity, err := model.queries.UpdatePaymentRequestStatus(ctx, sqlc.UpdatePaymentRequestStatusParams{
+		ID:          int64(requestId),
+		Status:      status,
+		Transaction: sql.NullInt64{Int64: int64(txId), Valid: txId != 0},
+	})
+	if err != nil {
+		return PaymentRequest{}, fmt.Errorf("query UpdatePaymentRequestStatus: %w", err)
+	}
+	return mapToPaymentRequest(paymentRequestRow(entity))
+}
+
+// 期限切れかどうかを含まない行を、期限切れでないものとして扱います
+// 作成した直後や、pendingでなくなった後の行に使います
+func paymentRequestRow(entity sqlc.PaymentRequest) sqlc.GetPaymentRequestRow {
+	return sqlc.GetPaymentRequestRow{
+		ID:          entity.ID,
+		Requester:   entity.Requester,
+		Payer:       entity.Payer,
+		Currency:    entity.Currency,
+		Amount:      entity.Amount,
+		Memo:        entity.Memo,
+		Status:      entity.Status,
+		ExpiresAt:   entity.ExpiresAt,
+		Transaction: entity.Transaction,
+		InsertedAt:  entity.InsertedAt,
+		UpdatedAt:   entity.UpdatedAt,
+	}
+}
+
+func mapToPaymentRequest(entity sqlc.GetPaymentRequestRow) (PaymentRequest, error) {
+	amount, err := parseDecimal(entity.Amount)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+
+	status := PaymentRequestStatus(entity.Status)
+	if entity.Status == sqlc.PaymentRequestStatusPending && entity.Expired {
+		status = Expired
+	}
+
+	var memo *string
+	if entity.Memo.Valid {
+		memo = &entity.Memo.String
+	}
+
+	var transaction *int
+	if entity.Transaction.Valid {
+		id := int(entity.Transaction.Int64)
+		transaction = &id
+	}
+
+	return PaymentRequest{
+		Id:          int(entity.ID),
+		Requester:   int(entity.Requester),
+		Payer:       int(entity.Payer),
+		Amount:      amount,
+		Currency:    entity.Currency,
+		Memo:        memo,
+		Status:      status,
+		ExpiresAt:   entity.ExpiresAt,
+		Transaction: transaction,
+		InsertedAt:  entity.InsertedAt,
+		UpdatedAt:   entity.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+// balancesに記録された残高と、取引の履歴から導出した残高の食い違い
+type Mismatch struct {
+	Account  int    `json:"account"`
+	Currency string `json:"currency"`
+	// balancesに記録されている残高
+	Balance decimal.Decimal `json:"balance"`
+	// mints, spends, transfers, reversals, conversions, journal_legs, fees, escrowsの履歴から導出した残高
+	Expected decimal.Decimal `json:"expected"`
+	// postingsの合計
+	Posted decimal.Decimal `json:"posted"`
+}
+
+type Reconciliation struct {
+	Mismatches []Mismatch `json:"mismatches"`
+	// repairを指定して、balancesを履歴に合わせて修正したかどうか
+	Repaired bool `json:"repaired"`
+}
+
+// 全てのaccountsについて残高を履歴から計算しなおし、食い違っているものを返します
+// repairがtrueであれば、食い違っていたbalancesを単一のトランザクションで履歴に合わせ、reconciliationsに記録を残します
+func (model *Model) Reconcile(ctx context.Context, repair bool) (Reconciliation, error) {
+	mismatches, err := model.findMismatches(ctx)
+	if err != nil {
+		return Reconciliation{}, err
+	}
+
+	if !repair || len(mismatches) == 0 {
+		return Reconciliation{Mismatches: mismatches}, nil
+	}
+
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (Reconciliation, error) {
+		model := model.WithTx(tx)
+
+		// 修正の対象をロックしてから計算しなおし、その間に進んだ取引を取りこぼさないようにします
+		// mismatchesは(account, currency)の昇順に並んでいるので、他の取引とロックの順序が揃います
+		for _, m := range mismatches {
+			_, err := model.LockBalance(ctx, m.Account, m.Currency)
+			if err != nil {
+				return Reconciliation{}, err
+			}
+		}
+
+		mismatches, err := model.findMismatches(ctx)
+		if err != nil {
+			return Reconciliation{}, err
+		}
+
+		for _, m := range mismatches {
+			err = model.queries.SetBalance(ctx, sqlc.SetBalanceParams{
+				Account:  int64(m.Account),
+				Currency: m.Currency,
+				Balance:  m.Expected.String(),
+			})
+			if err != nil {
+				return Reconciliation{}, fmt.Errorf("query SetBalance: %w", err)
+			}
+		}
+
+		encoded, err := json.Marshal(mismatches)
+		if err != nil {
+			return Reconciliation{}, fmt.Errorf("encoding mismatches: %w", err)
+		}
+
+		_, err = model.queries.InsertReconciliation(ctx, encoded)
+		if err != nil {
+			return Reconciliation{}, fmt.Errorf("query InsertReconciliation: %w", err)
+		}
+
+		return Reconciliation{Mismatches: mismatches, Repaired: true}, nil
+	})
+}
+
+func (model *Model) findMismatches(ctx context.Context) ([]Mismatch, error) {
+	rows, err := model.queries.GetExpectedBalances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query GetExpectedBalances: %w", err)
+	}
+
+	mismatches := []Mismatch{}
+	for _, row := range rows {
+		m, err := mapToMismatch(row)
+		if err != nil {
+			return nil, err
+		}
+
+		if m.Balance.Equal(m.Expected) && m.Posted.Equal(m.Expected) {
+			continue
+		}
+		mismatches = append(mismatches, m)
+	}
+
+	return mismatches, nil
+}
+
+func mapToMismatch(row sqlc.GetExpectedBalancesRow) (Mismatch, error) {
+	balance, err := parseDecimal(row.Balance)
+	if err != nil {
+		return Mismatch{}, err
+	}
+
+	expected, err := parseDecimal(row.Expected)
+	if err != nil {
+		return Mismatch{}, err
+	}
+
+	posted, err := parseDecimal(row.Posted)
+	if err != nil {
+		return Mismatch{}, err
+	}
+
+	return Mismatch{
+		Account:  int(row.Account),
+		Currency: row.Currency,
+		Balance:  balance,
+		Expected: expected,
+		Posted:   posted,
+	}, nil
+}
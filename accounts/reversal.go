@@ -0,0 +1,165 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+// accountIdが行ったtxIdのtransactionを取り消すreversalを記録します
+// amountがnilであれば、まだ取り消されていない残りの全額を取り消します
+// transferに課した手数料は払い戻さず、取り消せるのはtransferの金額だけです
+func (model *Model) Reverse(ctx context.Context, accountId int, txId int, amount *decimal.Decimal, idempotency *Idempotency) (int, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (int, error) {
+		model := model.WithTx(tx)
+		return model.idempotent(ctx, accountId, idempotency, func() (int, error) {
+			return model.reverse(ctx, accountId, txId, amount)
+		})
+	})
+}
+
+// Reverseの本体です。WithTxで得たModelから呼び出してください
+func (model *Model) reverse(ctx context.Context, accountId int, txId int, amount *decimal.Decimal) (int, error) {
+	err := model.ensureActive(ctx, accountId)
+	if err != nil {
+		return 0, err
+	}
+
+	// 同じtransactionへの取り消しが同時に来ても、取り消せる残りの金額を超えないようにロックします
+	original, err := model.queries.LockTransaction(ctx, int64(txId))
+	if err == sql.ErrNoRows || (err == nil && int(original.AccountID) != accountId) {
+		return 0, fmt.Errorf("Not found transaction by id %d: %w", txId, NotFoundError)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("query LockTransaction: %w", err)
+	}
+
+	if original.ReversalID.Valid {
+		return 0, fmt.Errorf("transaction %d is a reversal and cannot be reversed: %w", txId, DomainError)
+	}
+	if original.ConversionID.Valid {
+		return 0, fmt.Errorf("transaction %d is a conversion and cannot be reversed: %w", txId, DomainError)
+	}
+	if original.JournalID.Valid {
+		return 0, fmt.Errorf("transaction %d is a journal and cannot be reversed: %w", txId, DomainError)
+	}
+	if original.FeeID.Valid {
+		return 0, fmt.Errorf("transaction %d is a fee and cannot be reversed: %w", txId, DomainError)
+	}
+	if original.EscrowMovementID.Valid {
+		return 0, fmt.Errorf("transaction %d is an escrow movement and cannot be reversed: %w", txId, DomainError)
+	}
+
+	originalAmount, currency, err := originalAmount(original)
+	if err != nil {
+		return 0, err
+	}
+
+	reversedDecimal, err := model.queries.GetReversedAmount(ctx, int64(txId))
+	if err != nil {
+		return 0, fmt.Errorf("query GetReversedAmount: %w", err)
+	}
+	reversed, err := parseDecimal(reversedDecimal)
+	if err != nil {
+		return 0, err
+	}
+
+	remaining := originalAmount.Sub(reversed)
+	if !remaining.IsPositive() {
+		return 0, fmt.Errorf("transaction %d was already reversed: %w", txId, ConflictError)
+	}
+
+	reversal := remaining
+	if amount != nil {
+		_, err = model.validateAmount(ctx, currency, *amount)
+		if err != nil {
+			return 0, err
+		}
+		reversal = *amount
+	}
+	if reversal.GreaterThan(remaining) {
+		return 0, fmt.Errorf("%s amount was requested, but only %s is refundable: %w", reversal, remaining, DomainError)
+	}
+
+	// 元のtransactionの仕訳を同じcurrencyで反対向きにします
+	var postings []posting
+	switch {
+	case original.MintAmount.Valid:
+		err = model.HasEnough(ctx, accountId, currency, reversal)
+		postings = []posting{
+			{account: accountId, currency: currency, amount: reversal.Neg()},
+			{account: IssuanceAccountId, currency: currency, amount: reversal},
+		}
+	case original.SpendAmount.Valid:
+		err = model.LockBalances(ctx, currency, accountId)
+		postings = []posting{
+			{account: SinkAccountId, currency: currency, amount: reversal.Neg()},
+			{account: accountId, currency: currency, amount: reversal},
+		}
+	default:
+		recipient := int(original.TransferRecipient.Int64)
+		err = model.ensureActive(ctx, recipient)
+		if err == nil {
+			err = model.LockBalances(ctx, currency, accountId, recipient)
+		}
+		// recipientが使うと決めていない与信枠で、残高を負にしないようにします
+		if err == nil {
+			err = model.hasEnough(ctx, recipient, currency, reversal, false)
+		}
+		postings = []posting{
+			{account: recipient, currency: currency, amount: reversal.Neg()},
+			{account: accountId, currency: currency, amount: reversal},
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	reversalId, err := model.queries.InsertReversal(ctx, sqlc.InsertReversalParams{
+		Amount:   reversal.String(),
+		Original: int64(txId),
+		Currency: currency,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("query InsertReversal: %w", err)
+	}
+
+	reversalTxId, err := model.queries.InsertTransaction(ctx, sqlc.InsertTransactionParams{
+		Account:  int64(accountId),
+		Reversal: sql.NullInt64{Int64: reversalId, Valid: true},
+		Metadata: emptyMetadata,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("query InsertTransaction: %w", err)
+	}
+
+	err = model.post(ctx, reversalTxId, postings...)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(reversalTxId), nil
+}
+
+// 元のtransactionの金額とcurrencyを返します
+func originalAmount(original sqlc.LockTransactionRow) (decimal.Decimal, string, error) {
+	var amountDecimal, currency sql.NullString
+	switch {
+	case original.MintAmount.Valid:
+		amountDecimal, currency = original.MintAmount, original.MintCurrency
+	case original.SpendAmount.Valid:
+		amountDecimal, currency = original.SpendAmount, original.SpendCurrency
+	default:
+		amountDecimal, currency = original.TransferAmount, original.TransferCurrency
+	}
+
+	amount, err := parseDecimal(amountDecimal.String)
+	if err != nil {
+		return decimal.Decimal{}, "", err
+	}
+
+	return amount, currency.String, nil
+}
@@ -0,0 +1,461 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rail44/g/sqlc/generated"
+	"github.com/shopspring/decimal"
+)
+
+// 実行結果を取得する件数の既定値と上限
+const (
+	DefaultScheduleRunsLimit = 100
+	MaxScheduleRunsLimit     = 1000
+)
+
+// accountIdからrecipientへ、startsAtを起点にfrequency毎に送金する予約を作ります
+// startsAtがnilなら直ちに最初の回を実行し、endsAtがあればその時刻より後の回は実行しません
+func (model *Model) CreateSchedule(ctx context.Context, accountId int, recipient int, currency string, amount decimal.Decimal, memo *string, frequency ScheduleFrequency, startsAt *time.Time, endsAt *time.Time) (Schedule, error) {
+	if recipient == accountId {
+		return Schedule{}, fmt.Errorf("recipient should be different from sender %d: %w", accountId, ValidationError)
+	}
+
+	err := validateFrequency(frequency)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	for _, id := range []int{accountId, recipient} {
+		err = model.Exists(ctx, id)
+		if err != nil {
+			return Schedule{}, err
+		}
+	}
+
+	_, err = model.validateAmount(ctx, currency, amount)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	columns, err := newMemoColumns(memo, nil)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	params := sqlc.InsertScheduleParams{
+		Account:   int64(accountId),
+		Recipient: int64(recipient),
+		Currency:  currency,
+		Amount:    amount.String(),
+		Memo:      columns.memo,
+		Frequency: sqlc.ScheduleFrequency(frequency),
+	}
+	if startsAt != nil {
+		params.StartsAt = sql.NullTime{Time: *startsAt, Valid: true}
+	}
+	if endsAt != nil {
+		params.EndsAt = sql.NullTime{Time: *endsAt, Valid: true}
+	}
+
+	// starts_atを省略した場合の最初の回はDBの時刻で決まるので、ends_atとの比較もDBで行います
+	entity, err := model.queries.InsertSchedule(ctx, params)
+	if err == sql.ErrNoRows {
+		return Schedule{}, fmt.Errorf("ends_at should be after starts_at: %w", ValidationError)
+	}
+	if err != nil {
+		return Schedule{}, fmt.Errorf("query InsertSchedule: %w", err)
+	}
+
+	return mapToSchedule(entity)
+}
+
+func (model *Model) GetSchedules(ctx context.Context, accountId int) ([]Schedule, error) {
+	err := model.Exists(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := model.queries.GetSchedules(ctx, int64(accountId))
+	if err != nil {
+		return nil, fmt.Errorf("query GetSchedules: %w", err)
+	}
+
+	schedules := make([]Schedule, 0, len(rows))
+	for _, row := range rows {
+		schedule, err := mapToSchedule(row)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+func (model *Model) GetSchedule(ctx context.Context, accountId int, scheduleId int) (Schedule, error) {
+	entity, err := model.queries.GetSchedule(ctx, sqlc.GetScheduleParams{
+		ID:      int64(scheduleId),
+		Account: int64(accountId),
+	})
+	if err == sql.ErrNoRows {
+		return Schedule{}, fmt.Errorf("Not found schedule by id %d: %w", scheduleId, NotFoundError)
+	}
+	if err != nil {
+		return Schedule{}, fmt.Errorf("query GetSchedule: %w", err)
+	}
+
+	return mapToSchedule(entity)
+}
+
+// 予約の金額、memo、終了時刻を変更します。nilの項目は変更しません
+// 実行中の回があれば、その回が終わるのを待ってから次の回に反映します
+func (model *Model) UpdateSchedule(ctx context.Context, accountId int, scheduleId int, amount *decimal.Decimal, memo *string, endsAt *time.Time) (Schedule, error) {
+	err := validateMemo(memo, nil)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	return model.changeSchedule(ctx, accountId, scheduleId, func(schedule *sqlc.UpdateScheduleParams, entity sqlc.Schedule) error {
+		if amount != nil {
+			_, err := model.validateAmount(ctx, entity.Currency, *amount)
+			if err != nil {
+				return err
+			}
+			schedule.Amount = amount.String()
+		}
+		if memo != nil {
+			schedule.Memo = sql.NullString{String: *memo, Valid: true}
+		}
+		if endsAt != nil {
+			if !endsAt.After(entity.StartsAt) {
+				return fmt.Errorf("ends_at should be after starts_at: %w", ValidationError)
+			}
+			schedule.EndsAt = sql.NullTime{Time: *endsAt, Valid: true}
+		}
+		return nil
+	})
+}
+
+// 予約を一時停止します。停止している間は実行せず、過ぎた回は再開してから実行します
+func (model *Model) PauseSchedule(ctx context.Context, accountId int, scheduleId int) (Schedule, error) {
+	return model.changeSchedule(ctx, accountId, scheduleId, func(schedule *sqlc.UpdateScheduleParams, entity sqlc.Schedule) error {
+		if entity.Status != sqlc.ScheduleStatusActive {
+			return fmt.Errorf("schedule %d is %s and cannot be paused: %w", scheduleId, entity.Status, ConflictError)
+		}
+		schedule.Status = sqlc.ScheduleStatusPaused
+		return nil
+	})
+}
+
+// 一時停止した予約を再開します
+// 停止していた間に過ぎた回は、workerが止まっていた場合と同じく古い回から順に全て実行します
+// 実行時刻を過ぎたonceの予約も、再開した後に実行します
+func (model *Model) ResumeSchedule(ctx context.Context, accountId int, scheduleId int) (Schedule, error) {
+	return model.changeSchedule(ctx, accountId, scheduleId, func(schedule *sqlc.UpdateScheduleParams, entity sqlc.Schedule) error {
+		if entity.Status != sqlc.ScheduleStatusPaused {
+			return fmt.Errorf("schedule %d is %s and cannot be resumed: %w", scheduleId, entity.Status, ConflictError)
+		}
+
+		schedule.Status = sqlc.ScheduleStatusActive
+		if entity.EndsAt.Valid && schedule.NextRunAt.After(entity.EndsAt.Time) {
+			schedule.Status = sqlc.ScheduleStatusCompleted
+		}
+		return nil
+	})
+}
+
+// 予約を取り消し、以後の回を実行しないようにします。実行結果の記録は残ります
+func (model *Model) CancelSchedule(ctx context.Context, accountId int, scheduleId int) (Schedule, error) {
+	return model.changeSchedule(ctx, accountId, scheduleId, func(schedule *sqlc.UpdateScheduleParams, entity sqlc.Schedule) error {
+		schedule.Status = sqlc.ScheduleStatusCancelled
+		return nil
+	})
+}
+
+// 予約をロックし、fで変更した内容を書き込みます
+// 繰り返しを終えたものや取り消したものは変更できません
+func (model *Model) changeSchedule(ctx context.Context, accountId int, scheduleId int, f func(schedule *sqlc.UpdateScheduleParams, entity sqlc.Schedule) error) (Schedule, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (Schedule, error) {
+		model := model.WithTx(tx)
+
+		entity, err := model.queries.LockSchedule(ctx, sqlc.LockScheduleParams{
+			ID:      int64(scheduleId),
+			Account: int64(accountId),
+		})
+		if err == sql.ErrNoRows {
+			return Schedule{}, fmt.Errorf("Not found schedule by id %d: %w", scheduleId, NotFoundError)
+		}
+		if err != nil {
+			return Schedule{}, fmt.Errorf("query LockSchedule: %w", err)
+		}
+
+		if entity.Status == sqlc.ScheduleStatusCompleted || entity.Status == sqlc.ScheduleStatusCancelled {
+			return Schedule{}, fmt.Errorf("schedule %d was already %s: %w", scheduleId, entity.Status, ConflictError)
+		}
+
+		params := sqlc.UpdateScheduleParams{
+			ID:        entity.ID,
+			Amount:    entity.Amount,
+			Memo:      entity.Memo,
+			EndsAt:    entity.EndsAt,
+			Status:    entity.Status,
+			NextRunAt: entity.NextRunAt,
+		}
+		err = f(&params, entity)
+		if err != nil {
+			return Schedule{}, err
+		}
+
+		updated, err := model.queries.UpdateSchedule(ctx, params)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("query UpdateSchedule: %w", err)
+		}
+
+		return mapToSchedule(updated)
+	})
+}
+
+// 予約の実行結果を新しい回から順にlimit件まで返します
+func (model *Model) GetScheduleRuns(ctx context.Context, accountId int, scheduleId int, limit int) ([]ScheduleRun, error) {
+	if limit < 1 || limit > MaxScheduleRunsLimit {
+		return nil, fmt.Errorf("limit should be between 1 and %d: %w", MaxScheduleRunsLimit, ValidationError)
+	}
+
+	_, err := model.GetSchedule(ctx, accountId, scheduleId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := model.queries.GetScheduleRuns(ctx, sqlc.GetScheduleRunsParams{
+		Schedule: int64(scheduleId),
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query GetScheduleRuns: %w", err)
+	}
+
+	runs := make([]ScheduleRun, 0, len(rows))
+	for _, row := range rows {
+		runs = append(runs, mapToScheduleRun(row))
+	}
+	return runs, nil
+}
+
+// 実行時刻を過ぎた予約を、なくなるまで1回ずつ実行し、実行した回数を返します
+// workerが停止していた間に過ぎた回は、古い回から順に全て実行します
+func (model *Model) RunDueSchedules(ctx context.Context) (int, error) {
+	count := 0
+	for {
+		ran, err := model.runDueSchedule(ctx)
+		if err != nil {
+			return count, err
+		}
+		if !ran {
+			return count, nil
+		}
+		count++
+	}
+}
+
+// 実行時刻を過ぎた予約を1件ロックして、その回を実行します。実行するものがなければfalse
+// 送金はTransferとして予約のロックとは別のトランザクションで行い、(予約, 回)毎のIdempotency-Keyを付けます
+// 送金のコミット後に結果を記録できずに再試行しても、同じ回の送金を二重に行うことはありません
+func (model *Model) runDueSchedule(ctx context.Context) (bool, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (bool, error) {
+		claimed := model.WithTx(tx)
+
+		entity, err := claimed.queries.ClaimDueSchedule(ctx)
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("query ClaimDueSchedule: %w", err)
+		}
+
+		due := entity.NextRunAt
+		params := sqlc.UpdateScheduleParams{
+			ID:        entity.ID,
+			Amount:    entity.Amount,
+			Memo:      entity.Memo,
+			EndsAt:    entity.EndsAt,
+			Status:    entity.Status,
+			NextRunAt: entity.NextRunAt,
+		}
+
+		// ends_atを縮めた場合などは、その回を実行せずに終えます
+		if entity.EndsAt.Valid && due.After(entity.EndsAt.Time) {
+			params.Status = sqlc.ScheduleStatusCompleted
+			_, err = claimed.queries.UpdateSchedule(ctx, params)
+			if err != nil {
+				return false, fmt.Errorf("query UpdateSchedule: %w", err)
+			}
+			return true, nil
+		}
+
+		run := sqlc.InsertScheduleRunParams{Schedule: entity.ID, DueAt: due}
+		txId, err := model.executeSchedule(ctx, entity)
+		switch {
+		case err == nil:
+			run.Status = sqlc.ScheduleRunStatusSucceeded
+			run.Transaction = sql.NullInt64{Int64: int64(txId), Valid: true}
+		case isRejection(err) || errors.Is(err, DomainError):
+			// 残高不足や凍結、出金の制限などは、その回を失敗として記録して次の回へ進みます
+			run.Status = sqlc.ScheduleRunStatusFailed
+			run.Error = sql.NullString{String: err.Error(), Valid: true}
+		default:
+			// それ以外はロールバックし、同じ回を次の機会に再試行します
+			return false, err
+		}
+
+		_, err = claimed.queries.InsertScheduleRun(ctx, run)
+		if err != nil {
+			return false, fmt.Errorf("query InsertScheduleRun: %w", err)
+		}
+
+		next, ok := nextOccurrence(entity.Frequency, entity.StartsAt, due)
+		if !ok || (entity.EndsAt.Valid && next.After(entity.EndsAt.Time)) {
+			params.Status = sqlc.ScheduleStatusCompleted
+		} else {
+			params.NextRunAt = next
+		}
+
+		_, err = claimed.queries.UpdateSchedule(ctx, params)
+		if err != nil {
+			return false, fmt.Errorf("query UpdateSchedule: %w", err)
+		}
+
+		return true, nil
+	})
+}
+
+// 予約のその回の送金を行います
+func (model *Model) executeSchedule(ctx context.Context, entity sqlc.Schedule) (int, error) {
+	amount, err := parseDecimal(entity.Amount)
+	if err != nil {
+		return 0, err
+	}
+
+	var memo *string
+	if entity.Memo.Valid {
+		memo = &entity.Memo.String
+	}
+
+	// 金額などを変更した後に再試行しても同じ回として扱えるよう、予約と回だけから作ります
+	key := IdempotencyKey(fmt.Sprintf("schedule:%d:%d", entity.ID, entity.NextRunAt.UnixNano()))
+	idempotency, err := NewIdempotency(&key, "schedule", int(entity.Account), struct {
+		Schedule int64     `json:"schedule"`
+		DueAt    time.Time `json:"due_at"`
+	}{entity.ID, entity.NextRunAt})
+	if err != nil {
+		return 0, err
+	}
+
+	return model.Transfer(ctx, int(entity.Account), int(entity.Recipient), entity.Currency, amount, memo, nil, nil, idempotency)
+}
+
+// startsAtを起点としたfrequencyの回のうち、afterより後の最初の回を返します
+// monthlyで起点の日がない月は、その月の末日とします。onceのように次の回がなければfalse
+func nextOccurrence(frequency sqlc.ScheduleFrequency, startsAt time.Time, after time.Time) (time.Time, bool) {
+	if after.Before(startsAt) {
+		return startsAt, true
+	}
+
+	switch frequency {
+	case sqlc.ScheduleFrequencyDaily:
+		return nextPeriod(startsAt, after, 24*time.Hour), true
+	case sqlc.ScheduleFrequencyWeekly:
+		return nextPeriod(startsAt, after, 7*24*time.Hour), true
+	case sqlc.ScheduleFrequencyMonthly:
+		months := (after.Year()-startsAt.Year())*12 + int(after.Month()) - int(startsAt.Month())
+		next := addMonths(startsAt, months)
+		for !next.After(after) {
+			months++
+			next = addMonths(startsAt, months)
+		}
+		return next, true
+	}
+
+	return time.Time{}, false
+}
+
+func nextPeriod(startsAt time.Time, after time.Time, period time.Duration) time.Time {
+	n := after.Sub(startsAt)/period + 1
+	return startsAt.Add(n * period)
+}
+
+// tからmonthsヶ月後の同じ日を返します。その日がなければ月末に丸めます
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	day := t.Day()
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+func validateFrequency(frequency ScheduleFrequency) error {
+	switch sqlc.ScheduleFrequency(frequency) {
+	case sqlc.ScheduleFrequencyOnce, sqlc.ScheduleFrequencyDaily, sqlc.ScheduleFrequencyWeekly, sqlc.ScheduleFrequencyMonthly:
+		return nil
+	}
+	return fmt.Errorf("frequency should be once, daily, weekly or monthly %q: %w", frequency, ValidationError)
+}
+
+func mapToSchedule(entity sqlc.Schedule) (Schedule, error) {
+	amount, err := parseDecimal(entity.Amount)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	var memo *string
+	if entity.Memo.Valid {
+		memo = &entity.Memo.String
+	}
+
+	var endsAt *time.Time
+	if entity.EndsAt.Valid {
+		endsAt = &entity.EndsAt.Time
+	}
+
+	return Schedule{
+		Id:         int(entity.ID),
+		Account:    int(entity.Account),
+		Recipient:  int(entity.Recipient),
+		Amount:     amount,
+		Currency:   entity.Currency,
+		Memo:       memo,
+		Frequency:  ScheduleFrequency(entity.Frequency),
+		Status:     ScheduleStatus(entity.Status),
+		StartsAt:   entity.StartsAt,
+		EndsAt:     endsAt,
+		NextRunAt:  entity.NextRunAt,
+		InsertedAt: entity.InsertedAt,
+		UpdatedAt:  entity.UpdatedAt,
+	}, nil
+}
+
+func mapToScheduleRun(entity sqlc.ScheduleRun) ScheduleRun {
+	var transaction *int
+	if entity.Transaction.Valid {
+		id := int(entity.Transaction.Int64)
+		transaction = &id
+	}
+
+	var runError *string
+	if entity.Error.Valid {
+		runError = &entity.Error.String
+	}
+
+	return ScheduleRun{
+		Id:          int(entity.ID),
+		Schedule:    int(entity.Schedule),
+		DueAt:       entity.DueAt,
+		Status:      ScheduleRunStatus(entity.Status),
+		Transaction: transaction,
+		Error:       runError,
+		InsertedAt:  entity.InsertedAt,
+	}
+}
@@ -0,0 +1,237 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rail44/g/sqlc/generated"
+)
+
+// 凍結中のaccountsに対する取引のエラー
+var AccountFrozenError = errors.New("Account Frozen")
+
+// 閉鎖済みのaccountsに対する取引のエラー
+var AccountClosedError = errors.New("Account Closed")
+
+// accountsのstatusを変更した記録
+type StatusChange struct {
+	Id         int       `json:"id"`
+	Account    int       `json:"account"`
+	Previous   string    `json:"previous"`
+	Status     string    `json:"status"`
+	Reason     string    `json:"reason"`
+	InsertedAt time.Time `json:"inserted_at"`
+}
+
+// idのaccountsが取引できる状態でなければ、凍結中ならAccountFrozenError、閉鎖済みならAccountClosedError
+// statusを共有ロックするので、取引の途中で凍結や閉鎖が割り込むことはありません
+func (model *Model) ensureActive(ctx context.Context, id int) error {
+	if isSystemAccount(id) {
+		return fmt.Errorf("Not found account by id %d: %w", id, NotFoundError)
+	}
+
+	status, err := model.queries.ShareAccountStatus(ctx, int64(id))
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("Not found account by id %d: %w", id, NotFoundError)
+	}
+	if err != nil {
+		return fmt.Errorf("query ShareAccountStatus: %w", err)
+	}
+
+	switch status {
+	case sqlc.AccountStatusFrozen:
+		return fmt.Errorf("account %d is frozen: %w", id, AccountFrozenError)
+	case sqlc.AccountStatusClosed:
+		return fmt.Errorf("account %d is closed: %w", id, AccountClosedError)
+	}
+
+	return nil
+}
+
+// idのaccountsを凍結し、送金も入金もできないようにします
+func (model *Model) Freeze(ctx context.Context, id int, reason string) (StatusChange, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (StatusChange, error) {
+		model := model.WithTx(tx)
+		return model.changeStatus(ctx, id, sqlc.AccountStatusActive, sqlc.AccountStatusFrozen, reason)
+	})
+}
+
+// 凍結したidのaccountsを元に戻します
+func (model *Model) Unfreeze(ctx context.Context, id int, reason string) (StatusChange, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (StatusChange, error) {
+		model := model.WithTx(tx)
+		return model.changeStatus(ctx, id, sqlc.AccountStatusFrozen, sqlc.AccountStatusActive, reason)
+	})
+}
+
+// idのaccountsを閉鎖します。閉鎖したaccountsは元に戻せません
+// sweepToがnilであれば全ての残高が0でなければDomainError、指定されていれば残高をsweepToへのtransferとして移します
+// 有効なholdsや、払い出しも払い戻しもされていないescrowsが残っている場合は閉鎖できません
+func (model *Model) Close(ctx context.Context, id int, reason string, sweepTo *int) (StatusChange, error) {
+	return WithTransaction(ctx, model.db, model.txOptions, func(tx *sql.Tx) (StatusChange, error) {
+		model := model.WithTx(tx)
+
+		account, err := model.lockAccount(ctx, id)
+		if err != nil {
+			return StatusChange{}, err
+		}
+		if account.Status == sqlc.AccountStatusClosed {
+			return StatusChange{}, fmt.Errorf("account %d was already closed: %w", id, ConflictError)
+		}
+
+		// 閉鎖した後では、escrowsを払い出すことも払い戻すこともできなくなります
+		escrows, err := model.queries.CountOpenEscrows(ctx, int64(id))
+		if err != nil {
+			return StatusChange{}, fmt.Errorf("query CountOpenEscrows: %w", err)
+		}
+		if escrows > 0 {
+			return StatusChange{}, fmt.Errorf("account %d has %d open escrows: %w", id, escrows, DomainError)
+		}
+
+		ids := []int{id}
+		if sweepTo != nil {
+			ids = append(ids, *sweepTo)
+			if *sweepTo == id {
+				return StatusChange{}, fmt.Errorf("balance cannot be swept to the closing account %d: %w", id, ValidationError)
+			}
+			err = model.ensureActive(ctx, *sweepTo)
+			if err != nil {
+				return StatusChange{}, err
+			}
+		}
+
+		rows, err := model.queries.GetBalances(ctx, int64(id))
+		if err != nil {
+			return StatusChange{}, fmt.Errorf("query GetBalances: %w", err)
+		}
+
+		for _, row := range rows {
+			err = model.LockBalances(ctx, row.Currency, ids...)
+			if err != nil {
+				return StatusChange{}, err
+			}
+
+			balance, err := model.GetBalance(ctx, id, row.Currency)
+			if err != nil {
+				return StatusChange{}, err
+			}
+
+			held, err := model.GetHeldAmount(ctx, id, row.Currency)
+			if err != nil {
+				return StatusChange{}, err
+			}
+			if held.IsPositive() {
+				return StatusChange{}, fmt.Errorf("account %d has %s %s on hold: %w", id, held, row.Currency, DomainError)
+			}
+
+			if balance.IsZero() {
+				continue
+			}
+			// 与信枠を使って負になった残高は、精算されるまで閉鎖できません
+			if balance.IsNegative() {
+				return StatusChange{}, fmt.Errorf("account %d is overdrawn by %s %s: %w", id, balance.Neg(), row.Currency, DomainError)
+			}
+			if sweepTo == nil {
+				return StatusChange{}, fmt.Errorf("account %d still has %s %s: %w", id, balance, row.Currency, DomainError)
+			}
+
+			// 凍結中のaccountsも閉鎖できるよう、ensureActiveを含むtransferを通さずに記録します
+			_, err = model.recordTransfer(ctx, id, *sweepTo, row.Currency, balance, nil, nil, nil)
+			if err != nil {
+				return StatusChange{}, err
+			}
+		}
+
+		return model.recordStatusChange(ctx, id, account.Status, sqlc.AccountStatusClosed, reason)
+	})
+}
+
+// idのaccountsのstatusを変更した記録を古い順に返します
+func (model *Model) GetStatusChanges(ctx context.Context, id int) ([]StatusChange, error) {
+	err := model.Exists(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := model.queries.GetAccountStatusChanges(ctx, int64(id))
+	if err != nil {
+		return nil, fmt.Errorf("query GetAccountStatusChanges: %w", err)
+	}
+
+	changes := make([]StatusChange, 0, len(rows))
+	for _, row := range rows {
+		changes = append(changes, mapToStatusChange(row))
+	}
+	return changes, nil
+}
+
+// idのaccountsがfromであればtoに変更します。そうでなければConflictError
+func (model *Model) changeStatus(ctx context.Context, id int, from sqlc.AccountStatus, to sqlc.AccountStatus, reason string) (StatusChange, error) {
+	account, err := model.lockAccount(ctx, id)
+	if err != nil {
+		return StatusChange{}, err
+	}
+
+	if account.Status != from {
+		return StatusChange{}, fmt.Errorf("account %d is %s, not %s: %w", id, account.Status, from, ConflictError)
+	}
+
+	return model.recordStatusChange(ctx, id, from, to, reason)
+}
+
+// statusを共有ロックしている取引が終わるのを待ってからaccountをロックします
+func (model *Model) lockAccount(ctx context.Context, id int) (sqlc.Account, error) {
+	if isSystemAccount(id) {
+		return sqlc.Account{}, fmt.Errorf("Not found account by id %d: %w", id, NotFoundError)
+	}
+
+	account, err := model.queries.LockAccount(ctx, int64(id))
+	if err == sql.ErrNoRows {
+		return sqlc.Account{}, fmt.Errorf("Not found account by id %d: %w", id, NotFoundError)
+	}
+	if err != nil {
+		return sqlc.Account{}, fmt.Errorf("query LockAccount: %w", err)
+	}
+
+	return account, nil
+}
+
+func (model *Model) recordStatusChange(ctx context.Context, id int, from sqlc.AccountStatus, to sqlc.AccountStatus, reason string) (StatusChange, error) {
+	if len(reason) == 0 {
+		return StatusChange{}, fmt.Errorf("reason is not presented: %w", ValidationError)
+	}
+
+	err := model.queries.UpdateAccountStatus(ctx, sqlc.UpdateAccountStatusParams{
+		ID:     int64(id),
+		Status: to,
+	})
+	if err != nil {
+		return StatusChange{}, fmt.Errorf("query UpdateAccountStatus: %w", err)
+	}
+
+	change, err := model.queries.InsertAccountStatusChange(ctx, sqlc.InsertAccountStatusChangeParams{
+		Account:  int64(id),
+		Previous: from,
+		Status:   to,
+		Reason:   reason,
+	})
+	if err != nil {
+		return StatusChange{}, fmt.Errorf("query InsertAccountStatusChange: %w", err)
+	}
+
+	return mapToStatusChange(change), nil
+}
+
+func mapToStatusChange(entity sqlc.AccountStatusChange) StatusChange {
+	return StatusChange{
+		Id:         int(entity.ID),
+		Account:    int(entity.Account),
+		Previous:   string(entity.Previous),
+		Status:     string(entity.Status),
+		Reason:     entity.Reason,
+		InsertedAt: entity.InsertedAt,
+	}
+}
@@ -0,0 +1,122 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// トランザクションが中断された際に、どのSQLSTATEなら何回まで再試行するか
+type RetryPolicy struct {
+	// 最初の実行を含めた試行回数。1以下なら再試行しません
+	MaxAttempts int
+	// 再試行の対象とするSQLSTATE
+	Codes []pq.ErrorCode
+	// 待ち時間はBaseDelayから倍々に増やし、MaxDelayで頭打ちにします
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// WithTransactionの挙動を指定するオプション
+type TxOptions struct {
+	// 分離レベル。sql.LevelDefaultならPostgreSQLの既定(READ COMMITTED)になります
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+	Retry     RetryPolicy
+	// 0より大きければ、1回の試行をこの時間で打ち切ります
+	Timeout time.Duration
+}
+
+// デッドロック(40P01)とシリアライズ失敗(40001)を再試行します
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	Codes:       []pq.ErrorCode{"40P01", "40001"},
+	BaseDelay:   10 * time.Millisecond,
+	MaxDelay:    200 * time.Millisecond,
+}
+
+var DefaultTxOptions = TxOptions{
+	Retry: DefaultRetryPolicy,
+}
+
+// 関数をラップして単一トランザクションでクエリを実行するためのユーティリティ関数
+// 使用例はmodel.goにあります
+// opts.Retryに該当するエラーで中断した場合は、fを最初から実行しなおします
+// ctxがキャンセルされた場合は、実行中のクエリも含めて中断されます
+func WithTransaction[T interface{}](ctx context.Context, db *sql.DB, opts TxOptions, f func(tx *sql.Tx) (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		v, err := runTransaction(ctx, db, opts, f)
+		if err == nil || !opts.Retry.retryable(err) || attempt >= opts.Retry.MaxAttempts {
+			return v, err
+		}
+
+		select {
+		case <-time.After(opts.Retry.backoff(attempt)):
+		case <-ctx.Done():
+			return v, fmt.Errorf("waiting for retry: %w", ctx.Err())
+		}
+	}
+}
+
+func runTransaction[T interface{}](ctx context.Context, db *sql.DB, opts TxOptions, f func(tx *sql.Tx) (T, error)) (T, error) {
+	var v T
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
+	if err != nil {
+		return v, fmt.Errorf("begining transaction: %w", err)
+	}
+
+	// Commit()まで到達せずにスコープを抜けた場合はRollback
+	defer tx.Rollback()
+
+	v, err = f(tx)
+	if err != nil {
+		return v, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return v, fmt.Errorf("commit: %w", err)
+	}
+
+	return v, nil
+}
+
+// 再試行すれば成功する可能性のあるエラーかどうか
+func (policy RetryPolicy) retryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	for _, code := range policy.Codes {
+		if pqErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// 待ち時間に揺らぎを加えて、同時に中断されたトランザクション同士が再び衝突しにくくします
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	d := policy.BaseDelay << (attempt - 1)
+	if d <= 0 || d > policy.MaxDelay {
+		d = policy.MaxDelay
+	}
+	if d <= 1 {
+		return d
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)))
+}
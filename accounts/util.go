@@ -3,55 +3,255 @@ package accounts
 import (
 	"fmt"
 	"github.com/rail44/g/sqlc/generated"
-	"strconv"
+	"github.com/shopspring/decimal"
 )
 
+// 照会したaccountから見た取引の向きと増減、直後の残高
+type perspective struct {
+	direction    Direction
+	delta        decimal.Decimal
+	counterparty *int
+	balance      decimal.Decimal
+}
+
+func mapToPerspective(entity sqlc.GetTransactionsRow) (perspective, error) {
+	delta, err := parseDecimal(entity.Delta)
+	if err != nil {
+		return perspective{}, err
+	}
+
+	balance, err := parseDecimal(entity.Balance)
+	if err != nil {
+		return perspective{}, err
+	}
+
+	p := perspective{
+		direction: None,
+		delta:     delta,
+		balance:   balance,
+	}
+	switch {
+	case delta.IsPositive():
+		p.direction = Incoming
+	case delta.IsNegative():
+		p.direction = Outgoing
+	}
+	if entity.Counterparty.Valid {
+		counterparty := int(entity.Counterparty.Int64)
+		p.counterparty = &counterparty
+	}
+
+	return p, nil
+}
+
 func mapToSubtype(entity sqlc.GetTransactionsRow) (interface{}, error) {
 	accountId := int(entity.AccountID)
+	p, err := mapToPerspective(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := decodeMetadata(entity.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	memo, reference := mapToMemo(entity.Memo, entity.ReferenceType, entity.ReferenceID)
+
 	if entity.MintID.Valid {
-		amount, err := strconv.Atoi(entity.MintAmount.String)
+		amount, err := parseDecimal(entity.MintAmount.String)
 		if err != nil {
-			return nil, fmt.Errorf("parse amount as decimal: %w", err)
+			return nil, err
 		}
 
 		return Mint{
-			Id:         int(entity.TransactionID),
-			Account:    accountId,
-			Amount:     amount,
-			InsertedAt: entity.InsertedAt,
-			Type:       MintTypeMint,
+			Id:           int(entity.TransactionID),
+			Account:      accountId,
+			Amount:       amount,
+			Currency:     entity.Currency,
+			InsertedAt:   entity.InsertedAt,
+			Direction:    p.direction,
+			Delta:        p.delta,
+			Counterparty: p.counterparty,
+			Balance:      p.balance,
+			Type:         MintTypeMint,
+			Memo:         memo,
+			Reference:    reference,
+			Metadata:     metadata,
 		}, nil
 	}
 
 	if entity.SpendID.Valid {
-		amount, err := strconv.Atoi(entity.SpendAmount.String)
+		amount, err := parseDecimal(entity.SpendAmount.String)
 		if err != nil {
-			return nil, fmt.Errorf("parse amount as decimal: %w", err)
+			return nil, err
 		}
 
 		return Spend{
-			Id:         int(entity.TransactionID),
-			Account:    accountId,
-			Amount:     amount,
-			InsertedAt: entity.InsertedAt,
-			Type:       SpendTypeSpend,
+			Id:           int(entity.TransactionID),
+			Account:      accountId,
+			Amount:       amount,
+			Currency:     entity.Currency,
+			InsertedAt:   entity.InsertedAt,
+			Direction:    p.direction,
+			Delta:        p.delta,
+			Counterparty: p.counterparty,
+			Balance:      p.balance,
+			Type:         SpendTypeSpend,
+			Memo:         memo,
+			Reference:    reference,
+			Metadata:     metadata,
 		}, nil
 	}
 
 	if entity.TransferID.Valid {
-		amount, err := strconv.Atoi(entity.TransferAmount.String)
+		amount, err := parseDecimal(entity.TransferAmount.String)
 		if err != nil {
-			return nil, fmt.Errorf("parse amount as decimal: %w", err)
+			return nil, err
 		}
 
 		return Transfer{
-			Id:         int(entity.TransactionID),
-			Account:    accountId,
-			Amount:     amount,
-			InsertedAt: entity.InsertedAt,
-			Type:       TransferTypeTransfer,
-			Recipient:  int(entity.TransferRecipient.Int64),
+			Id:           int(entity.TransactionID),
+			Account:      accountId,
+			Amount:       amount,
+			Currency:     entity.Currency,
+			InsertedAt:   entity.InsertedAt,
+			Direction:    p.direction,
+			Delta:        p.delta,
+			Counterparty: p.counterparty,
+			Balance:      p.balance,
+			Type:         TransferTypeTransfer,
+			Memo:         memo,
+			Reference:    reference,
+			Metadata:     metadata,
+			Recipient:    int(entity.TransferRecipient.Int64),
+		}, nil
+	}
+
+	if entity.ReversalID.Valid {
+		amount, err := parseDecimal(entity.ReversalAmount.String)
+		if err != nil {
+			return nil, err
+		}
+
+		return Reversal{
+			Id:           int(entity.TransactionID),
+			Account:      accountId,
+			Amount:       amount,
+			Currency:     entity.Currency,
+			InsertedAt:   entity.InsertedAt,
+			Direction:    p.direction,
+			Delta:        p.delta,
+			Counterparty: p.counterparty,
+			Balance:      p.balance,
+			Type:         ReversalTypeReversal,
+			Metadata:     metadata,
+			Original:     int(entity.ReversalOriginal.Int64),
+		}, nil
+	}
+	if entity.ConversionID.Valid {
+		amount, err := parseDecimal(entity.ConversionAmount.String)
+		if err != nil {
+			return nil, err
+		}
+
+		convertedAmount, err := parseDecimal(entity.ConversionConvertedAmount.String)
+		if err != nil {
+			return nil, err
+		}
+
+		rate, err := parseDecimal(entity.ConversionRate.String)
+		if err != nil {
+			return nil, err
+		}
+
+		return Conversion{
+			Id:              int(entity.TransactionID),
+			Account:         accountId,
+			Amount:          amount,
+			Source:          entity.ConversionSource.String,
+			ConvertedAmount: convertedAmount,
+			Target:          entity.ConversionTarget.String,
+			Rate:            rate,
+			Currency:        entity.Currency,
+			InsertedAt:      entity.InsertedAt,
+			Direction:       p.direction,
+			Delta:           p.delta,
+			Counterparty:    p.counterparty,
+			Balance:         p.balance,
+			Type:            ConversionTypeConversion,
+			Metadata:        metadata,
+			Recipient:       int(entity.ConversionRecipient.Int64),
+		}, nil
+	}
+	if entity.JournalID.Valid {
+		return Journal{
+			Id:           int(entity.TransactionID),
+			Account:      accountId,
+			Currency:     entity.Currency,
+			InsertedAt:   entity.InsertedAt,
+			Direction:    p.direction,
+			Delta:        p.delta,
+			Counterparty: p.counterparty,
+			Balance:      p.balance,
+			Type:         JournalTypeJournal,
+			Memo:         memo,
+			Metadata:     metadata,
+		}, nil
+	}
+	if entity.FeeID.Valid {
+		amount, err := parseDecimal(entity.FeeAmount.String)
+		if err != nil {
+			return nil, err
+		}
+
+		return Fee{
+			Id:           int(entity.TransactionID),
+			Account:      accountId,
+			Amount:       amount,
+			Currency:     entity.Currency,
+			InsertedAt:   entity.InsertedAt,
+			Direction:    p.direction,
+			Delta:        p.delta,
+			Counterparty: p.counterparty,
+			Balance:      p.balance,
+			Type:         FeeTypeFee,
+			Metadata:     metadata,
+			Original:     int(entity.FeeOriginal.Int64),
+			Recipient:    int(entity.FeeRecipient.Int64),
+		}, nil
+	}
+	if entity.EscrowMovementID.Valid {
+		amount, err := parseDecimal(entity.EscrowAmount.String)
+		if err != nil {
+			return nil, err
+		}
+
+		return EscrowMovement{
+			Id:           int(entity.TransactionID),
+			Account:      accountId,
+			Amount:       amount,
+			Currency:     entity.Currency,
+			InsertedAt:   entity.InsertedAt,
+			Direction:    p.direction,
+			Delta:        p.delta,
+			Counterparty: p.counterparty,
+			Balance:      p.balance,
+			Type:         EscrowMovementTypeEscrow,
+			Memo:         memo,
+			Metadata:     metadata,
+			Escrow:       int(entity.EscrowID.Int64),
+			Action:       EscrowMovementAction(entity.EscrowAction.EscrowAction),
+			Sender:       int(entity.EscrowSender.Int64),
+			Recipient:    int(entity.EscrowRecipient.Int64),
 		}, nil
 	}
 	return nil, fmt.Errorf("failed to determine entity type")
 }
+
+func (direction Direction) validate() error {
+	switch direction {
+	case Incoming, Outgoing:
+		return nil
+	}
+	return fmt.Errorf("direction should be incoming or outgoing %q: %w", direction, ValidationError)
+}
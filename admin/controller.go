@@ -0,0 +1,366 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rail44/g/accounts"
+)
+
+// 各RouteがErrorをreturnした場合のハンドラ
+// エラーはaccountsのModelから返ってくるので、変換はaccountsと共通です
+var ServerOptions = StrictHTTPServerOptions{
+	RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	},
+	ResponseErrorHandlerFunc: accounts.ErrorHandler,
+}
+
+// 運用者向けの操作をまとめたコントローラ
+// 認証は持たないので、外部に公開しない経路からのみ到達できるようにしてください
+func NewController(model *accounts.Model) http.Handler {
+	controller := Controller{model: model}
+	return Handler(NewStrictHandlerWithOptions(controller, nil, ServerOptions))
+}
+
+type Controller struct {
+	model *accounts.Model
+}
+
+// POST /reconciliations
+func (controller Controller) Reconcile(ctx context.Context, req ReconcileRequestObject) (ReconcileResponseObject, error) {
+	repair := req.Body.Repair != nil && *req.Body.Repair
+
+	reconciliation, err := controller.model.Reconcile(ctx, repair)
+	if err != nil {
+		return nil, err
+	}
+
+	mismatches := make([]Mismatch, 0, len(reconciliation.Mismatches))
+	for _, m := range reconciliation.Mismatches {
+		mismatches = append(mismatches, Mismatch{
+			Account:  m.Account,
+			Currency: m.Currency,
+			Balance:  m.Balance,
+			Expected: m.Expected,
+			Posted:   m.Posted,
+		})
+	}
+
+	res := Reconcile200JSONResponse{
+		Mismatches: mismatches,
+		Repaired:   reconciliation.Repaired,
+	}
+	return res, nil
+}
+
+// GET /currencies
+func (controller Controller) Currencies(ctx context.Context, req CurrenciesRequestObject) (CurrenciesResponseObject, error) {
+	currencies, err := controller.model.GetCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(Currencies200JSONResponse, 0, len(currencies))
+	for _, currency := range currencies {
+		res = append(res, Currency(currency))
+	}
+	return res, nil
+}
+
+// POST /currencies
+func (controller Controller) CreateCurrency(ctx context.Context, req CreateCurrencyRequestObject) (CreateCurrencyResponseObject, error) {
+	currency, err := controller.model.CreateCurrency(ctx, accounts.Currency(*req.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateCurrency200JSONResponse(currency), nil
+}
+
+// GET /rates
+func (controller Controller) Rates(ctx context.Context, req RatesRequestObject) (RatesResponseObject, error) {
+	rates, err := controller.model.GetRates(ctx, req.Params.Source, req.Params.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(Rates200JSONResponse, 0, len(rates))
+	for _, rate := range rates {
+		res = append(res, mapToRate(rate))
+	}
+	return res, nil
+}
+
+// POST /rates
+func (controller Controller) PublishRate(ctx context.Context, req PublishRateRequestObject) (PublishRateResponseObject, error) {
+	rate := accounts.Rate{
+		Source:     req.Body.Source,
+		Target:     req.Body.Target,
+		Rate:       req.Body.Rate,
+		ValidUntil: req.Body.ValidUntil,
+	}
+	if req.Body.ValidFrom != nil {
+		rate.ValidFrom = *req.Body.ValidFrom
+	}
+
+	published, err := controller.model.PublishRate(ctx, rate)
+	if err != nil {
+		return nil, err
+	}
+
+	return PublishRate200JSONResponse(mapToRate(published)), nil
+}
+
+func mapToRate(rate accounts.Rate) Rate {
+	return Rate{
+		Id:         rate.Id,
+		Source:     rate.Source,
+		Target:     rate.Target,
+		Rate:       rate.Rate,
+		ValidFrom:  rate.ValidFrom,
+		ValidUntil: rate.ValidUntil,
+	}
+}
+
+// GET /fee_schedules
+func (controller Controller) FeeSchedules(ctx context.Context, req FeeSchedulesRequestObject) (FeeSchedulesResponseObject, error) {
+	schedules, err := controller.model.GetFeeSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(FeeSchedules200JSONResponse, 0, len(schedules))
+	for _, schedule := range schedules {
+		res = append(res, mapToFeeSchedule(schedule))
+	}
+	return res, nil
+}
+
+// PUT /fee_schedules/{operation}/{currency}
+func (controller Controller) SetFeeSchedule(ctx context.Context, req SetFeeScheduleRequestObject) (SetFeeScheduleResponseObject, error) {
+	schedule := accounts.FeeSchedule{
+		Operation: accounts.FeeOperation(req.Operation),
+		Currency:  req.Currency,
+		Min:       req.Body.Min,
+		Max:       req.Body.Max,
+		Account:   req.Body.Account,
+	}
+	if req.Body.Flat != nil {
+		schedule.Flat = *req.Body.Flat
+	}
+	if req.Body.Percentage != nil {
+		schedule.Percentage = *req.Body.Percentage
+	}
+
+	set, err := controller.model.SetFeeSchedule(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return SetFeeSchedule200JSONResponse(mapToFeeSchedule(set)), nil
+}
+
+// DELETE /fee_schedules/{operation}/{currency}
+func (controller Controller) DeleteFeeSchedule(ctx context.Context, req DeleteFeeScheduleRequestObject) (DeleteFeeScheduleResponseObject, error) {
+	err := controller.model.DeleteFeeSchedule(ctx, accounts.FeeOperation(req.Operation), req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return DeleteFeeSchedule204Response{}, nil
+}
+
+func mapToFeeSchedule(schedule accounts.FeeSchedule) FeeSchedule {
+	return FeeSchedule{
+		Id:         schedule.Id,
+		Operation:  FeeOperation(schedule.Operation),
+		Currency:   schedule.Currency,
+		Flat:       schedule.Flat,
+		Percentage: schedule.Percentage,
+		Min:        schedule.Min,
+		Max:        schedule.Max,
+		Account:    schedule.Account,
+		InsertedAt: schedule.InsertedAt,
+		UpdatedAt:  schedule.UpdatedAt,
+	}
+}
+
+// GET /limits
+func (controller Controller) Limits(ctx context.Context, req LimitsRequestObject) (LimitsResponseObject, error) {
+	limits, err := controller.model.GetLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(Limits200JSONResponse, 0, len(limits))
+	for _, limit := range limits {
+		res = append(res, mapToLimit(limit))
+	}
+	return res, nil
+}
+
+// PUT /limits
+func (controller Controller) SetLimit(ctx context.Context, req SetLimitRequestObject) (SetLimitResponseObject, error) {
+	set, err := controller.model.SetLimit(ctx, accounts.Limit{
+		Account:   req.Body.Account,
+		Operation: accounts.LimitOperation(req.Body.Operation),
+		Currency:  req.Body.Currency,
+		Kind:      accounts.LimitKind(req.Body.Kind),
+		Value:     req.Body.Value,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return SetLimit200JSONResponse(mapToLimit(set)), nil
+}
+
+// DELETE /limits/{limitId}
+func (controller Controller) DeleteLimit(ctx context.Context, req DeleteLimitRequestObject) (DeleteLimitResponseObject, error) {
+	err := controller.model.DeleteLimit(ctx, req.LimitId)
+	if err != nil {
+		return nil, err
+	}
+
+	return DeleteLimit204Response{}, nil
+}
+
+func mapToLimit(limit accounts.Limit) Limit {
+	return Limit{
+		Id:         limit.Id,
+		Account:    limit.Account,
+		Operation:  LimitOperation(limit.Operation),
+		Currency:   limit.Currency,
+		Kind:       LimitKind(limit.Kind),
+		Value:      limit.Value,
+		InsertedAt: limit.InsertedAt,
+		UpdatedAt:  limit.UpdatedAt,
+	}
+}
+
+// POST /escrows/{escrowId}/resolve
+func (controller Controller) ResolveEscrow(ctx context.Context, req ResolveEscrowRequestObject) (ResolveEscrowResponseObject, error) {
+	escrow, err := controller.model.ResolveEscrow(ctx, req.EscrowId, accounts.EscrowMovementAction(req.Body.Resolution))
+	if err != nil {
+		return nil, err
+	}
+
+	return ResolveEscrow200JSONResponse(mapToEscrow(escrow)), nil
+}
+
+func mapToEscrow(escrow accounts.Escrow) Escrow {
+	return Escrow{
+		Id:                   escrow.Id,
+		Sender:               escrow.Sender,
+		Recipient:            escrow.Recipient,
+		Amount:               escrow.Amount,
+		Currency:             escrow.Currency,
+		Memo:                 escrow.Memo,
+		Status:               EscrowStatus(escrow.Status),
+		SenderConfirmedAt:    escrow.SenderConfirmedAt,
+		RecipientConfirmedAt: escrow.RecipientConfirmedAt,
+		ExpiresAt:            escrow.ExpiresAt,
+		InsertedAt:           escrow.InsertedAt,
+		UpdatedAt:            escrow.UpdatedAt,
+	}
+}
+
+// POST /accounts/{id}/freeze
+func (controller Controller) Freeze(ctx context.Context, req FreezeRequestObject) (FreezeResponseObject, error) {
+	change, err := controller.model.Freeze(ctx, req.Id, req.Body.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return Freeze200JSONResponse(mapToStatusChange(change)), nil
+}
+
+// POST /accounts/{id}/unfreeze
+func (controller Controller) Unfreeze(ctx context.Context, req UnfreezeRequestObject) (UnfreezeResponseObject, error) {
+	change, err := controller.model.Unfreeze(ctx, req.Id, req.Body.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	return Unfreeze200JSONResponse(mapToStatusChange(change)), nil
+}
+
+// POST /accounts/{id}/close
+func (controller Controller) Close(ctx context.Context, req CloseRequestObject) (CloseResponseObject, error) {
+	change, err := controller.model.Close(ctx, req.Id, req.Body.Reason, req.Body.SweepTo)
+	if err != nil {
+		return nil, err
+	}
+
+	return Close200JSONResponse(mapToStatusChange(change)), nil
+}
+
+// GET /accounts/{id}/credit_limits
+func (controller Controller) CreditLimits(ctx context.Context, req CreditLimitsRequestObject) (CreditLimitsResponseObject, error) {
+	limits, err := controller.model.GetCreditLimits(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(CreditLimits200JSONResponse, 0, len(limits))
+	for _, limit := range limits {
+		res = append(res, mapToCreditLimit(limit))
+	}
+	return res, nil
+}
+
+// PUT /accounts/{id}/credit_limits/{currency}
+func (controller Controller) SetCreditLimit(ctx context.Context, req SetCreditLimitRequestObject) (SetCreditLimitResponseObject, error) {
+	limit, err := controller.model.SetCreditLimit(ctx, req.Id, req.Currency, req.Body.CreditLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return SetCreditLimit200JSONResponse(mapToCreditLimit(limit)), nil
+}
+
+// DELETE /accounts/{id}/credit_limits/{currency}
+func (controller Controller) DeleteCreditLimit(ctx context.Context, req DeleteCreditLimitRequestObject) (DeleteCreditLimitResponseObject, error) {
+	err := controller.model.DeleteCreditLimit(ctx, req.Id, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return DeleteCreditLimit204Response{}, nil
+}
+
+func mapToCreditLimit(limit accounts.CreditLimit) CreditLimit {
+	return CreditLimit{
+		Account:     limit.Account,
+		Currency:    limit.Currency,
+		CreditLimit: limit.CreditLimit,
+		InsertedAt:  limit.InsertedAt,
+		UpdatedAt:   limit.UpdatedAt,
+	}
+}
+
+// GET /accounts/{id}/status_changes
+func (controller Controller) StatusChanges(ctx context.Context, req StatusChangesRequestObject) (StatusChangesResponseObject, error) {
+	changes, err := controller.model.GetStatusChanges(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(StatusChanges200JSONResponse, 0, len(changes))
+	for _, change := range changes {
+		res = append(res, mapToStatusChange(change))
+	}
+	return res, nil
+}
+
+func mapToStatusChange(change accounts.StatusChange) StatusChange {
+	return StatusChange{
+		Id:         change.Id,
+		Account:    change.Account,
+		Previous:   AccountStatus(change.Previous),
+		Status:     AccountStatus(change.Status),
+		Reason:     change.Reason,
+		InsertedAt: change.InsertedAt,
+	}
+}
@@ -0,0 +1,1953 @@
+// Package admin provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/deepmap/oapi-codegen version v1.12.4 DO NOT EDIT.
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/deepmap/oapi-codegen/pkg/runtime"
+	"github.com/go-chi/chi/v5"
+	"github.com/shopspring/decimal"
+)
+
+// Defines values for AccountStatus.
+const (
+	Active AccountStatus = "active"
+	Closed AccountStatus = "closed"
+	Frozen AccountStatus = "frozen"
+)
+
+// Defines values for EscrowStatus.
+const (
+	Disputed EscrowStatus = "disputed"
+	Funded   EscrowStatus = "funded"
+	Refunded EscrowStatus = "refunded"
+	Released EscrowStatus = "released"
+)
+
+// Defines values for FeeOperation.
+const (
+	FeeOperationSpend    FeeOperation = "spend"
+	FeeOperationTransfer FeeOperation = "transfer"
+)
+
+// Defines values for LimitKind.
+const (
+	DailyVolume LimitKind = "daily_volume"
+	HourlyCount LimitKind = "hourly_count"
+	MaxAmount   LimitKind = "max_amount"
+)
+
+// Defines values for LimitOperation.
+const (
+	LimitOperationSpend    LimitOperation = "spend"
+	LimitOperationTransfer LimitOperation = "transfer"
+)
+
+// Defines values for ResolveEscrowJSONBodyResolution.
+const (
+	Refund  ResolveEscrowJSONBodyResolution = "refund"
+	Release ResolveEscrowJSONBodyResolution = "release"
+)
+
+// AccountStatus defines model for AccountStatus.
+type AccountStatus string
+
+// Amount defines model for Amount.
+type Amount = decimal.Decimal
+
+// CreditLimit defines model for CreditLimit.
+type CreditLimit struct {
+	Account     int       `json:"account"`
+	CreditLimit Amount    `json:"credit_limit"`
+	Currency    string    `json:"currency"`
+	InsertedAt  time.Time `json:"inserted_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Currency defines model for Currency.
+type Currency struct {
+	// Code 英大文字と数字で16文字まで
+	Code string `json:"code"`
+	Name string `json:"name"`
+
+	// Scale 金額に許す小数点以下の桁数
+	Scale int32 `json:"scale"`
+}
+
+// Escrow defines model for Escrow.
+type Escrow struct {
+	Amount               Amount       `json:"amount"`
+	Currency             string       `json:"currency"`
+	ExpiresAt            time.Time    `json:"expires_at"`
+	Id                   int          `json:"id"`
+	InsertedAt           time.Time    `json:"inserted_at"`
+	Memo                 *string      `json:"memo,omitempty"`
+	Recipient            int          `json:"recipient"`
+	RecipientConfirmedAt *time.Time   `json:"recipient_confirmed_at,omitempty"`
+	Sender               int          `json:"sender"`
+	SenderConfirmedAt    *time.Time   `json:"sender_confirmed_at,omitempty"`
+	Status               EscrowStatus `json:"status"`
+	UpdatedAt            time.Time    `json:"updated_at"`
+}
+
+// EscrowStatus defines model for Escrow.Status.
+type EscrowStatus string
+
+// FeeOperation defines model for FeeOperation.
+type FeeOperation string
+
+// FeeSchedule defines model for FeeSchedule.
+type FeeSchedule struct {
+	Account    int          `json:"account"`
+	Currency   string       `json:"currency"`
+	Flat       Amount       `json:"flat"`
+	Id         int          `json:"id"`
+	InsertedAt time.Time    `json:"inserted_at"`
+	Max        *Amount      `json:"max,omitempty"`
+	Min        *Amount      `json:"min,omitempty"`
+	Operation  FeeOperation `json:"operation"`
+	Percentage Amount       `json:"percentage"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// Limit defines model for Limit.
+type Limit struct {
+	Account    *int           `json:"account,omitempty"`
+	Currency   string         `json:"currency"`
+	Id         int            `json:"id"`
+	InsertedAt time.Time      `json:"inserted_at"`
+	Kind       LimitKind      `json:"kind"`
+	Operation  LimitOperation `json:"operation"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	Value      Amount         `json:"value"`
+}
+
+// LimitKind defines model for LimitKind.
+type LimitKind string
+
+// LimitOperation defines model for LimitOperation.
+type LimitOperation string
+
+// Mismatch defines model for Mismatch.
+type Mismatch struct {
+	Account  int    `json:"account"`
+	Balance  Amount `json:"balance"`
+	Currency string `json:"currency"`
+	Expected Amount `json:"expected"`
+	Posted   Amount `json:"posted"`
+}
+
+// Rate sourceの1単位がtargetのrate単位に相当する為替レート
+type Rate struct {
+	Id         int        `json:"id"`
+	Rate       Amount     `json:"rate"`
+	Source     string     `json:"source"`
+	Target     string     `json:"target"`
+	ValidFrom  time.Time  `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+}
+
+// Reconciliation defines model for Reconciliation.
+type Reconciliation struct {
+	Mismatches []Mismatch `json:"mismatches"`
+	Repaired   bool       `json:"repaired"`
+}
+
+// StatusChange defines model for StatusChange.
+type StatusChange struct {
+	Account    int           `json:"account"`
+	Id         int           `json:"id"`
+	InsertedAt time.Time     `json:"inserted_at"`
+	Previous   AccountStatus `json:"previous"`
+	Reason     string        `json:"reason"`
+	Status     AccountStatus `json:"status"`
+}
+
+// AccountId defines model for AccountId.
+type AccountId = int
+
+// CloseJSONBody defines parameters for Close.
+type CloseJSONBody struct {
+	Reason  string `json:"reason"`
+	SweepTo *int   `json:"sweep_to,omitempty"`
+}
+
+// SetCreditLimitJSONBody defines parameters for SetCreditLimit.
+type SetCreditLimitJSONBody struct {
+	CreditLimit Amount `json:"credit_limit"`
+}
+
+// FreezeJSONBody defines parameters for Freeze.
+type FreezeJSONBody struct {
+	Reason string `json:"reason"`
+}
+
+// UnfreezeJSONBody defines parameters for Unfreeze.
+type UnfreezeJSONBody struct {
+	Reason string `json:"reason"`
+}
+
+// ResolveEscrowJSONBody defines parameters for ResolveEscrow.
+type ResolveEscrowJSONBody struct {
+	Resolution ResolveEscrowJSONBodyResolution `json:"resolution"`
+}
+
+// ResolveEscrowJSONBodyResolution defines parameters for ResolveEscrow.
+type ResolveEscrowJSONBodyResolution string
+
+// SetFeeScheduleJSONBody defines parameters for SetFeeSchedule.
+type SetFeeScheduleJSONBody struct {
+	// Account 手数料を受け取るaccount
+	Account    int     `json:"account"`
+	Flat       *Amount `json:"flat,omitempty"`
+	Max        *Amount `json:"max,omitempty"`
+	Min        *Amount `json:"min,omitempty"`
+	Percentage *Amount `json:"percentage,omitempty"`
+}
+
+// SetLimitJSONBody defines parameters for SetLimit.
+type SetLimitJSONBody struct {
+	// Account 省略した場合は全てのaccountsに対する既定値です
+	Account   *int           `json:"account,omitempty"`
+	Currency  string         `json:"currency"`
+	Kind      LimitKind      `json:"kind"`
+	Operation LimitOperation `json:"operation"`
+	Value     Amount         `json:"value"`
+}
+
+// RatesParams defines parameters for Rates.
+type RatesParams struct {
+	Source *string `form:"source,omitempty" json:"source,omitempty"`
+	Target *string `form:"target,omitempty" json:"target,omitempty"`
+}
+
+// PublishRateJSONBody defines parameters for PublishRate.
+type PublishRateJSONBody struct {
+	Rate   Amount `json:"rate"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+
+	// ValidFrom 省略した場合は直ちに有効になります
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+}
+
+// ReconcileJSONBody defines parameters for Reconcile.
+type ReconcileJSONBody struct {
+	Repair *bool `json:"repair,omitempty"`
+}
+
+// CloseJSONRequestBody defines body for Close for application/json ContentType.
+type CloseJSONRequestBody CloseJSONBody
+
+// SetCreditLimitJSONRequestBody defines body for SetCreditLimit for application/json ContentType.
+type SetCreditLimitJSONRequestBody SetCreditLimitJSONBody
+
+// FreezeJSONRequestBody defines body for Freeze for application/json ContentType.
+type FreezeJSONRequestBody FreezeJSONBody
+
+// UnfreezeJSONRequestBody defines body for Unfreeze for application/json ContentType.
+type UnfreezeJSONRequestBody UnfreezeJSONBody
+
+// CreateCurrencyJSONRequestBody defines body for CreateCurrency for application/json ContentType.
+type CreateCurrencyJSONRequestBody = Currency
+
+// ResolveEscrowJSONRequestBody defines body for ResolveEscrow for application/json ContentType.
+type ResolveEscrowJSONRequestBody ResolveEscrowJSONBody
+
+// SetFeeScheduleJSONRequestBody defines body for SetFeeSchedule for application/json ContentType.
+type SetFeeScheduleJSONRequestBody SetFeeScheduleJSONBody
+
+// SetLimitJSONRequestBody defines body for SetLimit for application/json ContentType.
+type SetLimitJSONRequestBody SetLimitJSONBody
+
+// PublishRateJSONRequestBody defines body for PublishRate for application/json ContentType.
+type PublishRateJSONRequestBody PublishRateJSONBody
+
+// ReconcileJSONRequestBody defines body for Reconcile for application/json ContentType.
+type ReconcileJSONRequestBody ReconcileJSONBody
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+
+	// (POST /accounts/{id}/close)
+	Close(w http.ResponseWriter, r *http.Request, id AccountId)
+
+	// (GET /accounts/{id}/credit_limits)
+	CreditLimits(w http.ResponseWriter, r *http.Request, id AccountId)
+
+	// (DELETE /accounts/{id}/credit_limits/{currency})
+	DeleteCreditLimit(w http.ResponseWriter, r *http.Request, id AccountId, currency string)
+
+	// (PUT /accounts/{id}/credit_limits/{currency})
+	SetCreditLimit(w http.ResponseWriter, r *http.Request, id AccountId, currency string)
+
+	// (POST /accounts/{id}/freeze)
+	Freeze(w http.ResponseWriter, r *http.Request, id AccountId)
+
+	// (GET /accounts/{id}/status_changes)
+	StatusChanges(w http.ResponseWriter, r *http.Request, id AccountId)
+
+	// (POST /accounts/{id}/unfreeze)
+	Unfreeze(w http.ResponseWriter, r *http.Request, id AccountId)
+
+	// (GET /currencies)
+	Currencies(w http.ResponseWriter, r *http.Request)
+
+	// (POST /currencies)
+	CreateCurrency(w http.ResponseWriter, r *http.Request)
+
+	// (POST /escrows/{escrowId}/resolve)
+	ResolveEscrow(w http.ResponseWriter, r *http.Request, escrowId int)
+
+	// (GET /fee_schedules)
+	FeeSchedules(w http.ResponseWriter, r *http.Request)
+
+	// (DELETE /fee_schedules/{operation}/{currency})
+	DeleteFeeSchedule(w http.ResponseWriter, r *http.Request, operation FeeOperation, currency string)
+
+	// (PUT /fee_schedules/{operation}/{currency})
+	SetFeeSchedule(w http.ResponseWriter, r *http.Request, operation FeeOperation, currency string)
+
+	// (GET /limits)
+	Limits(w http.ResponseWriter, r *http.Request)
+
+	// (PUT /limits)
+	SetLimit(w http.ResponseWriter, r *http.Request)
+
+	// (DELETE /limits/{limitId})
+	DeleteLimit(w http.ResponseWriter, r *http.Request, limitId int)
+
+	// (GET /rates)
+	Rates(w http.ResponseWriter, r *http.Request, params RatesParams)
+
+	// (POST /rates)
+	PublishRate(w http.ResponseWriter, r *http.Request)
+
+	// (POST /reconciliations)
+	Reconcile(w http.ResponseWriter, r *http.Request)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// Close operation middleware
+func (siw *ServerInterfaceWrapper) Close(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Close(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreditLimits operation middleware
+func (siw *ServerInterfaceWrapper) CreditLimits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreditLimits(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteCreditLimit operation middleware
+func (siw *ServerInterfaceWrapper) DeleteCreditLimit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "currency" -------------
+	var currency string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "currency", runtime.ParamLocationPath, chi.URLParam(r, "currency"), &currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteCreditLimit(w, r, id, currency)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SetCreditLimit operation middleware
+func (siw *ServerInterfaceWrapper) SetCreditLimit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "currency" -------------
+	var currency string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "currency", runtime.ParamLocationPath, chi.URLParam(r, "currency"), &currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetCreditLimit(w, r, id, currency)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Freeze operation middleware
+func (siw *ServerInterfaceWrapper) Freeze(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Freeze(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// StatusChanges operation middleware
+func (siw *ServerInterfaceWrapper) StatusChanges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.StatusChanges(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Unfreeze operation middleware
+func (siw *ServerInterfaceWrapper) Unfreeze(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id AccountId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "id", runtime.ParamLocationPath, chi.URLParam(r, "id"), &id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Unfreeze(w, r, id)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Currencies operation middleware
+func (siw *ServerInterfaceWrapper) Currencies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Currencies(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// CreateCurrency operation middleware
+func (siw *ServerInterfaceWrapper) CreateCurrency(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateCurrency(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// ResolveEscrow operation middleware
+func (siw *ServerInterfaceWrapper) ResolveEscrow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "escrowId" -------------
+	var escrowId int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "escrowId", runtime.ParamLocationPath, chi.URLParam(r, "escrowId"), &escrowId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "escrowId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ResolveEscrow(w, r, escrowId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// FeeSchedules operation middleware
+func (siw *ServerInterfaceWrapper) FeeSchedules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.FeeSchedules(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteFeeSchedule operation middleware
+func (siw *ServerInterfaceWrapper) DeleteFeeSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "operation" -------------
+	var operation FeeOperation
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "operation", runtime.ParamLocationPath, chi.URLParam(r, "operation"), &operation)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "operation", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "currency" -------------
+	var currency string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "currency", runtime.ParamLocationPath, chi.URLParam(r, "currency"), &currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteFeeSchedule(w, r, operation, currency)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SetFeeSchedule operation middleware
+func (siw *ServerInterfaceWrapper) SetFeeSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "operation" -------------
+	var operation FeeOperation
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "operation", runtime.ParamLocationPath, chi.URLParam(r, "operation"), &operation)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "operation", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "currency" -------------
+	var currency string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "currency", runtime.ParamLocationPath, chi.URLParam(r, "currency"), &currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetFeeSchedule(w, r, operation, currency)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Limits operation middleware
+func (siw *ServerInterfaceWrapper) Limits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Limits(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SetLimit operation middleware
+func (siw *ServerInterfaceWrapper) SetLimit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetLimit(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteLimit operation middleware
+func (siw *ServerInterfaceWrapper) DeleteLimit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "limitId" -------------
+	var limitId int
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "limitId", runtime.ParamLocationPath, chi.URLParam(r, "limitId"), &limitId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limitId", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteLimit(w, r, limitId)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Rates operation middleware
+func (siw *ServerInterfaceWrapper) Rates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params RatesParams
+
+	// ------------- Optional query parameter "source" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "source", r.URL.Query(), &params.Source)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "source", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "target" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "target", r.URL.Query(), &params.Target)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "target", Err: err})
+		return
+	}
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Rates(w, r, params)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PublishRate operation middleware
+func (siw *ServerInterfaceWrapper) PublishRate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PublishRate(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// Reconcile operation middleware
+func (siw *ServerInterfaceWrapper) Reconcile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.Reconcile(w, r)
+	})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshallingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshallingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshalling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshallingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{})
+}
+
+type ChiServerOptions struct {
+	BaseURL          string
+	BaseRouter       chi.Router
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, r chi.Router) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseRouter: r,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, r chi.Router, baseURL string) http.Handler {
+	return HandlerWithOptions(si, ChiServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: r,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handler {
+	r := options.BaseRouter
+
+	if r == nil {
+		r = chi.NewRouter()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/accounts/{id}/close", wrapper.Close)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/accounts/{id}/credit_limits", wrapper.CreditLimits)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/accounts/{id}/credit_limits/{currency}", wrapper.DeleteCreditLimit)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/accounts/{id}/credit_limits/{currency}", wrapper.SetCreditLimit)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/accounts/{id}/freeze", wrapper.Freeze)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/accounts/{id}/status_changes", wrapper.StatusChanges)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/accounts/{id}/unfreeze", wrapper.Unfreeze)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/currencies", wrapper.Currencies)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/currencies", wrapper.CreateCurrency)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/escrows/{escrowId}/resolve", wrapper.ResolveEscrow)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/fee_schedules", wrapper.FeeSchedules)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/fee_schedules/{operation}/{currency}", wrapper.DeleteFeeSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/fee_schedules/{operation}/{currency}", wrapper.SetFeeSchedule)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/limits", wrapper.Limits)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/limits", wrapper.SetLimit)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/limits/{limitId}", wrapper.DeleteLimit)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/rates", wrapper.Rates)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/rates", wrapper.PublishRate)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/reconciliations", wrapper.Reconcile)
+	})
+
+	return r
+}
+
+type CloseRequestObject struct {
+	Id   AccountId `json:"id"`
+	Body *CloseJSONRequestBody
+}
+
+type CloseResponseObject interface {
+	VisitCloseResponse(w http.ResponseWriter) error
+}
+
+type Close200JSONResponse StatusChange
+
+func (response Close200JSONResponse) VisitCloseResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreditLimitsRequestObject struct {
+	Id AccountId `json:"id"`
+}
+
+type CreditLimitsResponseObject interface {
+	VisitCreditLimitsResponse(w http.ResponseWriter) error
+}
+
+type CreditLimits200JSONResponse []CreditLimit
+
+func (response CreditLimits200JSONResponse) VisitCreditLimitsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteCreditLimitRequestObject struct {
+	Id       AccountId `json:"id"`
+	Currency string    `json:"currency"`
+}
+
+type DeleteCreditLimitResponseObject interface {
+	VisitDeleteCreditLimitResponse(w http.ResponseWriter) error
+}
+
+type DeleteCreditLimit204Response struct {
+}
+
+func (response DeleteCreditLimit204Response) VisitDeleteCreditLimitResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type SetCreditLimitRequestObject struct {
+	Id       AccountId `json:"id"`
+	Currency string    `json:"currency"`
+	Body     *SetCreditLimitJSONRequestBody
+}
+
+type SetCreditLimitResponseObject interface {
+	VisitSetCreditLimitResponse(w http.ResponseWriter) error
+}
+
+type SetCreditLimit200JSONResponse CreditLimit
+
+func (response SetCreditLimit200JSONResponse) VisitSetCreditLimitResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type FreezeRequestObject struct {
+	Id   AccountId `json:"id"`
+	Body *FreezeJSONRequestBody
+}
+
+type FreezeResponseObject interface {
+	VisitFreezeResponse(w http.ResponseWriter) error
+}
+
+type Freeze200JSONResponse StatusChange
+
+func (response Freeze200JSONResponse) VisitFreezeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type StatusChangesRequestObject struct {
+	Id AccountId `json:"id"`
+}
+
+type StatusChangesResponseObject interface {
+	VisitStatusChangesResponse(w http.ResponseWriter) error
+}
+
+type StatusChanges200JSONResponse []StatusChange
+
+func (response StatusChanges200JSONResponse) VisitStatusChangesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UnfreezeRequestObject struct {
+	Id   AccountId `json:"id"`
+	Body *UnfreezeJSONRequestBody
+}
+
+type UnfreezeResponseObject interface {
+	VisitUnfreezeResponse(w http.ResponseWriter) error
+}
+
+type Unfreeze200JSONResponse StatusChange
+
+func (response Unfreeze200JSONResponse) VisitUnfreezeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CurrenciesRequestObject struct {
+}
+
+type CurrenciesResponseObject interface {
+	VisitCurrenciesResponse(w http.ResponseWriter) error
+}
+
+type Currencies200JSONResponse []Currency
+
+func (response Currencies200JSONResponse) VisitCurrenciesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateCurrencyRequestObject struct {
+	Body *CreateCurrencyJSONRequestBody
+}
+
+type CreateCurrencyResponseObject interface {
+	VisitCreateCurrencyResponse(w http.ResponseWriter) error
+}
+
+type CreateCurrency200JSONResponse Currency
+
+func (response CreateCurrency200JSONResponse) VisitCreateCurrencyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ResolveEscrowRequestObject struct {
+	EscrowId int `json:"escrowId"`
+	Body     *ResolveEscrowJSONRequestBody
+}
+
+type ResolveEscrowResponseObject interface {
+	VisitResolveEscrowResponse(w http.ResponseWriter) error
+}
+
+type ResolveEscrow200JSONResponse Escrow
+
+func (response ResolveEscrow200JSONResponse) VisitResolveEscrowResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type FeeSchedulesRequestObject struct {
+}
+
+type FeeSchedulesResponseObject interface {
+	VisitFeeSchedulesResponse(w http.ResponseWriter) error
+}
+
+type FeeSchedules200JSONResponse []FeeSchedule
+
+func (response FeeSchedules200JSONResponse) VisitFeeSchedulesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteFeeScheduleRequestObject struct {
+	Operation FeeOperation `json:"operation"`
+	Currency  string       `json:"currency"`
+}
+
+type DeleteFeeScheduleResponseObject interface {
+	VisitDeleteFeeScheduleResponse(w http.ResponseWriter) error
+}
+
+type DeleteFeeSchedule204Response struct {
+}
+
+func (response DeleteFeeSchedule204Response) VisitDeleteFeeScheduleResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type SetFeeScheduleRequestObject struct {
+	Operation FeeOperation `json:"operation"`
+	Currency  string       `json:"currency"`
+	Body      *SetFeeScheduleJSONRequestBody
+}
+
+type SetFeeScheduleResponseObject interface {
+	VisitSetFeeScheduleResponse(w http.ResponseWriter) error
+}
+
+type SetFeeSchedule200JSONResponse FeeSchedule
+
+func (response SetFeeSchedule200JSONResponse) VisitSetFeeScheduleResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type LimitsRequestObject struct {
+}
+
+type LimitsResponseObject interface {
+	VisitLimitsResponse(w http.ResponseWriter) error
+}
+
+type Limits200JSONResponse []Limit
+
+func (response Limits200JSONResponse) VisitLimitsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SetLimitRequestObject struct {
+	Body *SetLimitJSONRequestBody
+}
+
+type SetLimitResponseObject interface {
+	VisitSetLimitResponse(w http.ResponseWriter) error
+}
+
+type SetLimit200JSONResponse Limit
+
+func (response SetLimit200JSONResponse) VisitSetLimitResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteLimitRequestObject struct {
+	LimitId int `json:"limitId"`
+}
+
+type DeleteLimitResponseObject interface {
+	VisitDeleteLimitResponse(w http.ResponseWriter) error
+}
+
+type DeleteLimit204Response struct {
+}
+
+func (response DeleteLimit204Response) VisitDeleteLimitResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type RatesRequestObject struct {
+	Params RatesParams
+}
+
+type RatesResponseObject interface {
+	VisitRatesResponse(w http.ResponseWriter) error
+}
+
+type Rates200JSONResponse []Rate
+
+func (response Rates200JSONResponse) VisitRatesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PublishRateRequestObject struct {
+	Body *PublishRateJSONRequestBody
+}
+
+type PublishRateResponseObject interface {
+	VisitPublishRateResponse(w http.ResponseWriter) error
+}
+
+type PublishRate200JSONResponse Rate
+
+func (response PublishRate200JSONResponse) VisitPublishRateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ReconcileRequestObject struct {
+	Body *ReconcileJSONRequestBody
+}
+
+type ReconcileResponseObject interface {
+	VisitReconcileResponse(w http.ResponseWriter) error
+}
+
+type Reconcile200JSONResponse Reconciliation
+
+func (response Reconcile200JSONResponse) VisitReconcileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+
+	// (POST /accounts/{id}/close)
+	Close(ctx context.Context, request CloseRequestObject) (CloseResponseObject, error)
+
+	// (GET /accounts/{id}/credit_limits)
+	CreditLimits(ctx context.Context, request CreditLimitsRequestObject) (CreditLimitsResponseObject, error)
+
+	// (DELETE /accounts/{id}/credit_limits/{currency})
+	DeleteCreditLimit(ctx context.Context, request DeleteCreditLimitRequestObject) (DeleteCreditLimitResponseObject, error)
+
+	// (PUT /accounts/{id}/credit_limits/{currency})
+	SetCreditLimit(ctx context.Context, request SetCreditLimitRequestObject) (SetCreditLimitResponseObject, error)
+
+	// (POST /accounts/{id}/freeze)
+	Freeze(ctx context.Context, request FreezeRequestObject) (FreezeResponseObject, error)
+
+	// (GET /accounts/{id}/status_changes)
+	StatusChanges(ctx context.Context, request StatusChangesRequestObject) (StatusChangesResponseObject, error)
+
+	// (POST /accounts/{id}/unfreeze)
+	Unfreeze(ctx context.Context, request UnfreezeRequestObject) (UnfreezeResponseObject, error)
+
+	// (GET /currencies)
+	Currencies(ctx context.Context, request CurrenciesRequestObject) (CurrenciesResponseObject, error)
+
+	// (POST /currencies)
+	CreateCurrency(ctx context.Context, request CreateCurrencyRequestObject) (CreateCurrencyResponseObject, error)
+
+	// (POST /escrows/{escrowId}/resolve)
+	ResolveEscrow(ctx context.Context, request ResolveEscrowRequestObject) (ResolveEscrowResponseObject, error)
+
+	// (GET /fee_schedules)
+	FeeSchedules(ctx context.Context, request FeeSchedulesRequestObject) (FeeSchedulesResponseObject, error)
+
+	// (DELETE /fee_schedules/{operation}/{currency})
+	DeleteFeeSchedule(ctx context.Context, request DeleteFeeScheduleRequestObject) (DeleteFeeScheduleResponseObject, error)
+
+	// (PUT /fee_schedules/{operation}/{currency})
+	SetFeeSchedule(ctx context.Context, request SetFeeScheduleRequestObject) (SetFeeScheduleResponseObject, error)
+
+	// (GET /limits)
+	Limits(ctx context.Context, request LimitsRequestObject) (LimitsResponseObject, error)
+
+	// (PUT /limits)
+	SetLimit(ctx context.Context, request SetLimitRequestObject) (SetLimitResponseObject, error)
+
+	// (DELETE /limits/{limitId})
+	DeleteLimit(ctx context.Context, request DeleteLimitRequestObject) (DeleteLimitResponseObject, error)
+
+	// (GET /rates)
+	Rates(ctx context.Context, request RatesRequestObject) (RatesResponseObject, error)
+
+	// (POST /rates)
+	PublishRate(ctx context.Context, request PublishRateRequestObject) (PublishRateResponseObject, error)
+
+	// (POST /reconciliations)
+	Reconcile(ctx context.Context, request ReconcileRequestObject) (ReconcileResponseObject, error)
+}
+
+type StrictHandlerFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request, args interface{}) (interface{}, error)
+
+type StrictMiddlewareFunc func(f StrictHandlerFunc, operationID string) StrictHandlerFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// Close operation middleware
+func (sh *strictHandler) Close(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request CloseRequestObject
+
+	request.Id = id
+
+	var body CloseJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Close(ctx, request.(CloseRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Close")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CloseResponseObject); ok {
+		if err := validResponse.VisitCloseResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// CreditLimits operation middleware
+func (sh *strictHandler) CreditLimits(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request CreditLimitsRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreditLimits(ctx, request.(CreditLimitsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreditLimits")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreditLimitsResponseObject); ok {
+		if err := validResponse.VisitCreditLimitsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// DeleteCreditLimit operation middleware
+func (sh *strictHandler) DeleteCreditLimit(w http.ResponseWriter, r *http.Request, id AccountId, currency string) {
+	var request DeleteCreditLimitRequestObject
+
+	request.Id = id
+	request.Currency = currency
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteCreditLimit(ctx, request.(DeleteCreditLimitRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteCreditLimit")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteCreditLimitResponseObject); ok {
+		if err := validResponse.VisitDeleteCreditLimitResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// SetCreditLimit operation middleware
+func (sh *strictHandler) SetCreditLimit(w http.ResponseWriter, r *http.Request, id AccountId, currency string) {
+	var request SetCreditLimitRequestObject
+
+	request.Id = id
+	request.Currency = currency
+
+	var body SetCreditLimitJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SetCreditLimit(ctx, request.(SetCreditLimitRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SetCreditLimit")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SetCreditLimitResponseObject); ok {
+		if err := validResponse.VisitSetCreditLimitResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Freeze operation middleware
+func (sh *strictHandler) Freeze(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request FreezeRequestObject
+
+	request.Id = id
+
+	var body FreezeJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Freeze(ctx, request.(FreezeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Freeze")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(FreezeResponseObject); ok {
+		if err := validResponse.VisitFreezeResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// StatusChanges operation middleware
+func (sh *strictHandler) StatusChanges(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request StatusChangesRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.StatusChanges(ctx, request.(StatusChangesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "StatusChanges")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(StatusChangesResponseObject); ok {
+		if err := validResponse.VisitStatusChangesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Unfreeze operation middleware
+func (sh *strictHandler) Unfreeze(w http.ResponseWriter, r *http.Request, id AccountId) {
+	var request UnfreezeRequestObject
+
+	request.Id = id
+
+	var body UnfreezeJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Unfreeze(ctx, request.(UnfreezeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Unfreeze")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UnfreezeResponseObject); ok {
+		if err := validResponse.VisitUnfreezeResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Currencies operation middleware
+func (sh *strictHandler) Currencies(w http.ResponseWriter, r *http.Request) {
+	var request CurrenciesRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Currencies(ctx, request.(CurrenciesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Currencies")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CurrenciesResponseObject); ok {
+		if err := validResponse.VisitCurrenciesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// CreateCurrency operation middleware
+func (sh *strictHandler) CreateCurrency(w http.ResponseWriter, r *http.Request) {
+	var request CreateCurrencyRequestObject
+
+	var body CreateCurrencyJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateCurrency(ctx, request.(CreateCurrencyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateCurrency")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateCurrencyResponseObject); ok {
+		if err := validResponse.VisitCreateCurrencyResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// ResolveEscrow operation middleware
+func (sh *strictHandler) ResolveEscrow(w http.ResponseWriter, r *http.Request, escrowId int) {
+	var request ResolveEscrowRequestObject
+
+	request.EscrowId = escrowId
+
+	var body ResolveEscrowJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ResolveEscrow(ctx, request.(ResolveEscrowRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ResolveEscrow")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ResolveEscrowResponseObject); ok {
+		if err := validResponse.VisitResolveEscrowResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// FeeSchedules operation middleware
+func (sh *strictHandler) FeeSchedules(w http.ResponseWriter, r *http.Request) {
+	var request FeeSchedulesRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.FeeSchedules(ctx, request.(FeeSchedulesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "FeeSchedules")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(FeeSchedulesResponseObject); ok {
+		if err := validResponse.VisitFeeSchedulesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// DeleteFeeSchedule operation middleware
+func (sh *strictHandler) DeleteFeeSchedule(w http.ResponseWriter, r *http.Request, operation FeeOperation, currency string) {
+	var request DeleteFeeScheduleRequestObject
+
+	request.Operation = operation
+	request.Currency = currency
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteFeeSchedule(ctx, request.(DeleteFeeScheduleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteFeeSchedule")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteFeeScheduleResponseObject); ok {
+		if err := validResponse.VisitDeleteFeeScheduleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// SetFeeSchedule operation middleware
+func (sh *strictHandler) SetFeeSchedule(w http.ResponseWriter, r *http.Request, operation FeeOperation, currency string) {
+	var request SetFeeScheduleRequestObject
+
+	request.Operation = operation
+	request.Currency = currency
+
+	var body SetFeeScheduleJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SetFeeSchedule(ctx, request.(SetFeeScheduleRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SetFeeSchedule")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SetFeeScheduleResponseObject); ok {
+		if err := validResponse.VisitSetFeeScheduleResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Limits operation middleware
+func (sh *strictHandler) Limits(w http.ResponseWriter, r *http.Request) {
+	var request LimitsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Limits(ctx, request.(LimitsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Limits")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(LimitsResponseObject); ok {
+		if err := validResponse.VisitLimitsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// SetLimit operation middleware
+func (sh *strictHandler) SetLimit(w http.ResponseWriter, r *http.Request) {
+	var request SetLimitRequestObject
+
+	var body SetLimitJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SetLimit(ctx, request.(SetLimitRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SetLimit")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SetLimitResponseObject); ok {
+		if err := validResponse.VisitSetLimitResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// DeleteLimit operation middleware
+func (sh *strictHandler) DeleteLimit(w http.ResponseWriter, r *http.Request, limitId int) {
+	var request DeleteLimitRequestObject
+
+	request.LimitId = limitId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteLimit(ctx, request.(DeleteLimitRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteLimit")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteLimitResponseObject); ok {
+		if err := validResponse.VisitDeleteLimitResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Rates operation middleware
+func (sh *strictHandler) Rates(w http.ResponseWriter, r *http.Request, params RatesParams) {
+	var request RatesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Rates(ctx, request.(RatesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Rates")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RatesResponseObject); ok {
+		if err := validResponse.VisitRatesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// PublishRate operation middleware
+func (sh *strictHandler) PublishRate(w http.ResponseWriter, r *http.Request) {
+	var request PublishRateRequestObject
+
+	var body PublishRateJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PublishRate(ctx, request.(PublishRateRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PublishRate")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PublishRateResponseObject); ok {
+		if err := validResponse.VisitPublishRateResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
+
+// Reconcile operation middleware
+func (sh *strictHandler) Reconcile(w http.ResponseWriter, r *http.Request) {
+	var request ReconcileRequestObject
+
+	var body ReconcileJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.Reconcile(ctx, request.(ReconcileRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "Reconcile")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ReconcileResponseObject); ok {
+		if err := validResponse.VisitReconcileResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("Unexpected response type: %T", response))
+	}
+}
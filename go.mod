@@ -6,6 +6,7 @@ require (
 	github.com/deepmap/oapi-codegen v1.12.4
 	github.com/go-chi/chi/v5 v5.0.8
 	github.com/lib/pq v1.10.7
+	github.com/shopspring/decimal v1.3.1
 )
 
 require (
@@ -1,16 +1,22 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	_ "github.com/lib/pq"
 
 	"github.com/rail44/g/accounts"
+	"github.com/rail44/g/admin"
+	"github.com/rail44/g/transactions"
 )
 
 type DBConfig struct {
@@ -32,8 +38,18 @@ func (config DBConfig) postgresUri() string {
 	)
 }
 
+var isolationLevels = map[string]sql.IsolationLevel{
+	"read-committed":  sql.LevelReadCommitted,
+	"repeatable-read": sql.LevelRepeatableRead,
+	"serializable":    sql.LevelSerializable,
+}
+
 func main() {
 	port := flag.Int("port", 0, "Port for g daemon")
+	isolation := flag.String("isolation", "read-committed", "Isolation level for write transactions (read-committed, repeatable-read or serializable)")
+	txTimeout := flag.Duration("txtimeout", 0, "Timeout for each write transaction, 0 means no timeout")
+	scheduleInterval := flag.Duration("schedule-interval", 10*time.Second, "Interval to run due scheduled transfers, 0 disables the worker")
+	escrowInterval := flag.Duration("escrow-interval", 10*time.Second, "Interval to refund expired escrows, 0 disables the worker")
 	dbConfig := DBConfig{
 		Host: flag.String("dbhost", "", "Hostname for postgresql"),
 		Port: flag.Int("dbport", 0, "Port number for postgresql"),
@@ -43,18 +59,106 @@ func main() {
 	}
 	flag.Parse()
 
+	isolationLevel, ok := isolationLevels[*isolation]
+	if !ok {
+		log.Fatalf("unknown isolation level: %s", *isolation)
+	}
+
 	db, err := sql.Open("postgres", dbConfig.postgresUri())
 	if err != nil {
-		log.Fatal(fmt.Sprintf("open postgres: %w", err))
+		log.Fatal(fmt.Errorf("open postgres: %w", err))
+	}
+
+	txOptions := accounts.DefaultTxOptions
+	txOptions.Isolation = isolationLevel
+	txOptions.Timeout = *txTimeout
+	model := accounts.NewModel(db).WithTxOptions(txOptions)
+
+	switch flag.Arg(0) {
+	case "":
+	case "reconcile":
+		reconcile(model, flag.Args()[1:])
+		return
+	default:
+		log.Fatalf("unknown subcommand: %s", flag.Arg(0))
 	}
 
 	r := chi.NewRouter()
-	accountsCotroller := accounts.NewController(accounts.NewModel(db))
+	accountsCotroller := accounts.NewController(model)
 	r.Mount("/accounts", accountsCotroller)
+	r.Mount("/transactions", transactions.NewController(model))
+	r.Mount("/admin", admin.NewController(model))
+
+	if *scheduleInterval > 0 {
+		go runSchedules(model, *scheduleInterval)
+	}
+	if *escrowInterval > 0 {
+		go refundEscrows(model, *escrowInterval)
+	}
 
 	listenAddr := fmt.Sprintf(":%d", *port)
 
 	log.Printf("Listening on %s", listenAddr)
 	err = http.ListenAndServe(listenAddr, r)
-	log.Fatal(fmt.Sprintf("listening: %w", err))
+	log.Fatal(fmt.Errorf("listening: %w", err))
+}
+
+// 実行時刻を過ぎた予約送金をintervalごとに実行するworker
+// 予約はFOR UPDATE SKIP LOCKEDで1件ずつ取得するので、複数のプロセスで動かしても同じ回を重ねて実行しません
+func runSchedules(model *accounts.Model, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		count, err := model.RunDueSchedules(context.Background())
+		if err != nil {
+			log.Printf("running schedules: %v", err)
+		}
+		if count > 0 {
+			log.Printf("ran %d schedules", count)
+		}
+	}
+}
+
+// 期限を過ぎたescrowsをintervalごとにsenderへ払い戻すworker
+// escrowsは1件ずつロックしてから状態を確かめるので、複数のプロセスで動かしても二重に払い戻しません
+func refundEscrows(model *accounts.Model, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		count, skipped, err := model.RefundExpiredEscrows(context.Background())
+		if err != nil {
+			log.Printf("refunding escrows: %v", err)
+		}
+		for _, reason := range skipped {
+			log.Printf("skipped refunding %v", reason)
+		}
+		if count > 0 {
+			log.Printf("refunded %d escrows", count)
+		}
+	}
+}
+
+// g reconcile [-repair]
+// 全てのaccountsの残高を履歴と突き合わせ、結果をJSONで標準出力に書き出します
+// 修正されないままの食い違いがあれば終了コード1で終了します
+func reconcile(model *accounts.Model, args []string) {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	repair := flags.Bool("repair", false, "Repair mismatched balances and record it")
+	flags.Parse(args)
+
+	reconciliation, err := model.Reconcile(context.Background(), *repair)
+	if err != nil {
+		log.Fatal(fmt.Errorf("reconcile: %w", err))
+	}
+
+	err = json.NewEncoder(os.Stdout).Encode(reconciliation)
+	if err != nil {
+		log.Fatal(fmt.Errorf("encoding reconciliation: %w", err))
+	}
+
+	if len(reconciliation.Mismatches) > 0 && !reconciliation.Repaired {
+		os.Exit(1)
+	}
 }
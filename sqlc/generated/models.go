@@ -1,47 +1,751 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.16.0
+//   sqlc v1.29.0
 
 package sqlc
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 )
 
+type AccountStatus string
+
+const (
+	AccountStatusActive AccountStatus = "active"
+	AccountStatusFrozen AccountStatus = "frozen"
+	AccountStatusClosed AccountStatus = "closed"
+)
+
+func (e *AccountStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccountStatus(s)
+	case string:
+		*e = AccountStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccountStatus: %T", src)
+	}
+	return nil
+}
+
+type NullAccountStatus struct {
+	AccountStatus AccountStatus
+	Valid         bool // Valid is true if AccountStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAccountStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.AccountStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AccountStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAccountStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AccountStatus), nil
+}
+
+type EscrowAction string
+
+const (
+	EscrowActionDeposit EscrowAction = "deposit"
+	EscrowActionRelease EscrowAction = "release"
+	EscrowActionRefund  EscrowAction = "refund"
+)
+
+func (e *EscrowAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = EscrowAction(s)
+	case string:
+		*e = EscrowAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for EscrowAction: %T", src)
+	}
+	return nil
+}
+
+type NullEscrowAction struct {
+	EscrowAction EscrowAction
+	Valid        bool // Valid is true if EscrowAction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullEscrowAction) Scan(value interface{}) error {
+	if value == nil {
+		ns.EscrowAction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.EscrowAction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullEscrowAction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.EscrowAction), nil
+}
+
+type EscrowStatus string
+
+const (
+	EscrowStatusFunded   EscrowStatus = "funded"
+	EscrowStatusDisputed EscrowStatus = "disputed"
+	EscrowStatusReleased EscrowStatus = "released"
+	EscrowStatusRefunded EscrowStatus = "refunded"
+)
+
+func (e *EscrowStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = EscrowStatus(s)
+	case string:
+		*e = EscrowStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for EscrowStatus: %T", src)
+	}
+	return nil
+}
+
+type NullEscrowStatus struct {
+	EscrowStatus EscrowStatus
+	Valid        bool // Valid is true if EscrowStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullEscrowStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.EscrowStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.EscrowStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullEscrowStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.EscrowStatus), nil
+}
+
+type FeeOperation string
+
+const (
+	FeeOperationTransfer FeeOperation = "transfer"
+	FeeOperationSpend    FeeOperation = "spend"
+)
+
+func (e *FeeOperation) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = FeeOperation(s)
+	case string:
+		*e = FeeOperation(s)
+	default:
+		return fmt.Errorf("unsupported scan type for FeeOperation: %T", src)
+	}
+	return nil
+}
+
+type NullFeeOperation struct {
+	FeeOperation FeeOperation
+	Valid        bool // Valid is true if FeeOperation is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullFeeOperation) Scan(value interface{}) error {
+	if value == nil {
+		ns.FeeOperation, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.FeeOperation.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullFeeOperation) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.FeeOperation), nil
+}
+
+type HoldStatus string
+
+const (
+	HoldStatusActive   HoldStatus = "active"
+	HoldStatusCaptured HoldStatus = "captured"
+	HoldStatusReleased HoldStatus = "released"
+)
+
+func (e *HoldStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = HoldStatus(s)
+	case string:
+		*e = HoldStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for HoldStatus: %T", src)
+	}
+	return nil
+}
+
+type NullHoldStatus struct {
+	HoldStatus HoldStatus
+	Valid      bool // Valid is true if HoldStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullHoldStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.HoldStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.HoldStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullHoldStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.HoldStatus), nil
+}
+
+type LimitKind string
+
+const (
+	LimitKindMaxAmount   LimitKind = "max_amount"
+	LimitKindDailyVolume LimitKind = "daily_volume"
+	LimitKindHourlyCount LimitKind = "hourly_count"
+)
+
+func (e *LimitKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LimitKind(s)
+	case string:
+		*e = LimitKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LimitKind: %T", src)
+	}
+	return nil
+}
+
+type NullLimitKind struct {
+	LimitKind LimitKind
+	Valid     bool // Valid is true if LimitKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLimitKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.LimitKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LimitKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLimitKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LimitKind), nil
+}
+
+type LimitOperation string
+
+const (
+	LimitOperationTransfer LimitOperation = "transfer"
+	LimitOperationSpend    LimitOperation = "spend"
+)
+
+func (e *LimitOperation) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LimitOperation(s)
+	case string:
+		*e = LimitOperation(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LimitOperation: %T", src)
+	}
+	return nil
+}
+
+type NullLimitOperation struct {
+	LimitOperation LimitOperation
+	Valid          bool // Valid is true if LimitOperation is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLimitOperation) Scan(value interface{}) error {
+	if value == nil {
+		ns.LimitOperation, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LimitOperation.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLimitOperation) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LimitOperation), nil
+}
+
+type PaymentRequestStatus string
+
+const (
+	PaymentRequestStatusPending  PaymentRequestStatus = "pending"
+	PaymentRequestStatusPaid     PaymentRequestStatus = "paid"
+	PaymentRequestStatusDeclined PaymentRequestStatus = "declined"
+)
+
+func (e *PaymentRequestStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PaymentRequestStatus(s)
+	case string:
+		*e = PaymentRequestStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PaymentRequestStatus: %T", src)
+	}
+	return nil
+}
+
+type NullPaymentRequestStatus struct {
+	PaymentRequestStatus PaymentRequestStatus
+	Valid                bool // Valid is true if PaymentRequestStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPaymentRequestStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.PaymentRequestStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PaymentRequestStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPaymentRequestStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PaymentRequestStatus), nil
+}
+
+type ScheduleFrequency string
+
+const (
+	ScheduleFrequencyOnce    ScheduleFrequency = "once"
+	ScheduleFrequencyDaily   ScheduleFrequency = "daily"
+	ScheduleFrequencyWeekly  ScheduleFrequency = "weekly"
+	ScheduleFrequencyMonthly ScheduleFrequency = "monthly"
+)
+
+func (e *ScheduleFrequency) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduleFrequency(s)
+	case string:
+		*e = ScheduleFrequency(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduleFrequency: %T", src)
+	}
+	return nil
+}
+
+type NullScheduleFrequency struct {
+	ScheduleFrequency ScheduleFrequency
+	Valid             bool // Valid is true if ScheduleFrequency is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScheduleFrequency) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScheduleFrequency, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScheduleFrequency.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScheduleFrequency) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ScheduleFrequency), nil
+}
+
+type ScheduleRunStatus string
+
+const (
+	ScheduleRunStatusSucceeded ScheduleRunStatus = "succeeded"
+	ScheduleRunStatusFailed    ScheduleRunStatus = "failed"
+)
+
+func (e *ScheduleRunStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduleRunStatus(s)
+	case string:
+		*e = ScheduleRunStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduleRunStatus: %T", src)
+	}
+	return nil
+}
+
+type NullScheduleRunStatus struct {
+	ScheduleRunStatus ScheduleRunStatus
+	Valid             bool // Valid is true if ScheduleRunStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScheduleRunStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScheduleRunStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScheduleRunStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScheduleRunStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ScheduleRunStatus), nil
+}
+
+type ScheduleStatus string
+
+const (
+	ScheduleStatusActive    ScheduleStatus = "active"
+	ScheduleStatusPaused    ScheduleStatus = "paused"
+	ScheduleStatusCompleted ScheduleStatus = "completed"
+	ScheduleStatusCancelled ScheduleStatus = "cancelled"
+)
+
+func (e *ScheduleStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduleStatus(s)
+	case string:
+		*e = ScheduleStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduleStatus: %T", src)
+	}
+	return nil
+}
+
+type NullScheduleStatus struct {
+	ScheduleStatus ScheduleStatus
+	Valid          bool // Valid is true if ScheduleStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScheduleStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScheduleStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScheduleStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScheduleStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ScheduleStatus), nil
+}
+
 type Account struct {
 	ID         int64
 	InsertedAt time.Time
 	UpdatedAt  time.Time
 	Name       sql.NullString
+	Status     AccountStatus
+	ExternalID sql.NullString
+	Metadata   json.RawMessage
+}
+
+type AccountStatusChange struct {
+	ID         int64
+	Account    int64
+	Previous   AccountStatus
+	Status     AccountStatus
+	Reason     string
+	InsertedAt time.Time
 }
 
 type Balance struct {
-	Account int64
-	Balance string
+	Account  int64
+	Balance  string
+	Currency string
 }
 
-type Mint struct {
-	ID     int64
-	Amount string
+type Conversion struct {
+	ID              int64
+	Amount          string
+	Source          string
+	ConvertedAmount string
+	Target          string
+	Rate            string
+	RateID          int64
+	Recipient       int64
 }
 
-type Spend struct {
+type CreditLimit struct {
+	Account     int64
+	Currency    string
+	CreditLimit string
+	InsertedAt  time.Time
+	UpdatedAt   time.Time
+}
+
+type Currency struct {
+	Code       string
+	Name       string
+	Scale      int32
+	InsertedAt time.Time
+}
+
+type Escrow struct {
+	ID                   int64
+	Sender               int64
+	Recipient            int64
+	Currency             string
+	Amount               string
+	Memo                 sql.NullString
+	Status               EscrowStatus
+	SenderConfirmedAt    sql.NullTime
+	RecipientConfirmedAt sql.NullTime
+	ExpiresAt            time.Time
+	InsertedAt           time.Time
+	UpdatedAt            time.Time
+}
+
+type EscrowMovement struct {
 	ID     int64
-	Amount string
+	Escrow int64
+	Action EscrowAction
 }
 
-type Transaction struct {
+type Fee struct {
+	ID        int64
+	Amount    string
+	Currency  string
+	Original  int64
+	Recipient int64
+	Schedule  sql.NullInt64
+}
+
+type FeeSchedule struct {
+	ID         int64
+	Operation  FeeOperation
+	Currency   string
+	Flat       string
+	Percentage string
+	Min        sql.NullString
+	Max        sql.NullString
+	Account    int64
+	InsertedAt time.Time
+	UpdatedAt  time.Time
+}
+
+type Hold struct {
+	ID          int64
+	Account     int64
+	Amount      string
+	Status      HoldStatus
+	ExpiresAt   time.Time
+	InsertedAt  time.Time
+	UpdatedAt   time.Time
+	Transaction sql.NullInt64
+	Currency    string
+}
+
+type IdempotencyKey struct {
+	Account     int64
+	Key         string
+	RequestHash string
+	Transaction int64
+	InsertedAt  time.Time
+}
+
+type Journal struct {
+	ID   int64
+	Memo sql.NullString
+}
+
+type JournalLeg struct {
+	ID       int64
+	Journal  int64
+	Account  int64
+	Currency string
+	Amount   string
+}
+
+type Limit struct {
+	ID         int64
+	Account    sql.NullInt64
+	Operation  LimitOperation
+	Currency   string
+	Kind       LimitKind
+	Value      string
+	InsertedAt time.Time
+	UpdatedAt  time.Time
+}
+
+type Mint struct {
+	ID            int64
+	Amount        string
+	Currency      string
+	Memo          sql.NullString
+	ReferenceType sql.NullString
+	ReferenceID   sql.NullString
+}
+
+type PaymentRequest struct {
+	ID          int64
+	Requester   int64
+	Payer       int64
+	Currency    string
+	Amount      string
+	Memo        sql.NullString
+	Status      PaymentRequestStatus
+	ExpiresAt   time.Time
+	Transaction sql.NullInt64
+	InsertedAt  time.Time
+	UpdatedAt   time.Time
+}
+
+type Posting struct {
+	ID           int64
+	Transaction  int64
+	Account      int64
+	Amount       string
+	Currency     string
+	BalanceAfter sql.NullString
+}
+
+type Quote struct {
+	ID          int64
+	Account     int64
+	RateID      int64
+	Source      string
+	Target      string
+	Rate        string
+	ExpiresAt   time.Time
+	InsertedAt  time.Time
+	Transaction sql.NullInt64
+}
+
+type Rate struct {
+	ID         int64
+	Source     string
+	Target     string
+	Rate       string
+	ValidFrom  time.Time
+	ValidUntil sql.NullTime
+	InsertedAt time.Time
+}
+
+type Reconciliation struct {
+	ID         int64
+	InsertedAt time.Time
+	Mismatches json.RawMessage
+}
+
+type Reversal struct {
+	ID       int64
+	Amount   string
+	Original int64
+	Currency string
+}
+
+type Schedule struct {
 	ID         int64
 	Account    int64
+	Recipient  int64
+	Currency   string
+	Amount     string
+	Memo       sql.NullString
+	Frequency  ScheduleFrequency
+	Status     ScheduleStatus
+	StartsAt   time.Time
+	EndsAt     sql.NullTime
+	NextRunAt  time.Time
 	InsertedAt time.Time
-	Mint       sql.NullInt64
-	Spend      sql.NullInt64
-	Transfer   sql.NullInt64
+	UpdatedAt  time.Time
+}
+
+type ScheduleRun struct {
+	ID          int64
+	Schedule    int64
+	DueAt       time.Time
+	Status      ScheduleRunStatus
+	Transaction sql.NullInt64
+	Error       sql.NullString
+	InsertedAt  time.Time
+}
+
+type Spend struct {
+	ID            int64
+	Amount        string
+	Currency      string
+	Memo          sql.NullString
+	ReferenceType sql.NullString
+	ReferenceID   sql.NullString
+}
+
+type Transaction struct {
+	ID             int64
+	Account        int64
+	InsertedAt     time.Time
+	Mint           sql.NullInt64
+	Spend          sql.NullInt64
+	Transfer       sql.NullInt64
+	Reversal       sql.NullInt64
+	Conversion     sql.NullInt64
+	Journal        sql.NullInt64
+	Fee            sql.NullInt64
+	EscrowMovement sql.NullInt64
+	Metadata       json.RawMessage
 }
 
 type Transfer struct {
-	ID        int64
-	Amount    string
-	Recipient int64
+	ID            int64
+	Amount        string
+	Recipient     int64
+	Currency      string
+	Memo          sql.NullString
+	ReferenceType sql.NullString
+	ReferenceID   sql.NullString
 }
@@ -1,6 +1,6 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.16.0
+//   sqlc v1.29.0
 // source: queries.sql
 
 package sqlc
@@ -8,234 +8,2850 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
-const decrementBalance = `-- name: DecrementBalance :exec
-UPDATE balances SET balance = balance - $2 WHERE account=$1
+const claimDueSchedule = `-- name: ClaimDueSchedule :one
+SELECT id, account, recipient, currency, amount, memo, frequency, status, starts_at, ends_at, next_run_at, inserted_at, updated_at FROM schedules
+WHERE status='active' AND next_run_at <= now()
+ORDER BY next_run_at ASC
+LIMIT 1 FOR UPDATE SKIP LOCKED
+`
+
+// 実行時刻を過ぎたactiveなschedulesを1件ロックして取得します
+// 他のworkerがロックしているものは飛ばすので、複数のworkerが同時に動いても同じ回を重ねて実行しません
+func (q *Queries) ClaimDueSchedule(ctx context.Context) (Schedule, error) {
+	row := q.db.QueryRowContext(ctx, claimDueSchedule)
+	var i Schedule
+	err := row.Scan(
+		&i.ID,
+		&i.Account,
+		&i.Recipient,
+		&i.Currency,
+		&i.Amount,
+		&i.Memo,
+		&i.Frequency,
+		&i.Status,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.NextRunAt,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const confirmEscrow = `-- name: ConfirmEscrow :one
+UPDATE escrows SET
+  sender_confirmed_at=CASE WHEN sender=$2 THEN COALESCE(sender_confirmed_at, now()) ELSE sender_confirmed_at END,
+  recipient_confirmed_at=CASE WHEN recipient=$2 THEN COALESCE(recipient_confirmed_at, now()) ELSE recipient_confirmed_at END,
+  updated_at=timezone('utc':: text, now())
+WHERE id=$1
+RETURNING id, sender, recipient, currency, amount, memo, status, sender_confirmed_at, recipient_confirmed_at, expires_at, inserted_at, updated_at
+`
+
+type ConfirmEscrowParams struct {
+	ID      int64
+	Account int64
+}
+
+// accountの側の確認の日時を、まだなければ記録します
+// 期限切れかどうかと同じく、日時はデータベースの時計で決めます
+func (q *Queries) ConfirmEscrow(ctx context.Context, arg ConfirmEscrowParams) (Escrow, error) {
+	row := q.db.QueryRowContext(ctx, confirmEscrow, arg.ID, arg.Account)
+	var i Escrow
+	err := row.Scan(
+		&i.ID,
+		&i.Sender,
+		&i.Recipient,
+		&i.Currency,
+		&i.Amount,
+		&i.Memo,
+		&i.Status,
+		&i.SenderConfirmedAt,
+		&i.RecipientConfirmedAt,
+		&i.ExpiresAt,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const countOpenEscrows = `-- name: CountOpenEscrows :one
+SELECT COUNT(*) FROM escrows
+WHERE (sender=$1 OR recipient=$1) AND status IN ('funded', 'disputed')
+`
+
+// accountがsenderかrecipientで、まだ払い出しも払い戻しもされていないescrowsの件数
+func (q *Queries) CountOpenEscrows(ctx context.Context, account int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOpenEscrows, account)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const decrementBalance = `-- name: DecrementBalance :one
+UPDATE balances SET balance = balance - $3 WHERE account=$1 AND currency=$2
+RETURNING balance
 `
 
 type DecrementBalanceParams struct {
+	Account  int64
+	Currency string
+	Amount   string
+}
+
+// 減らした後の残高を返します
+func (q *Queries) DecrementBalance(ctx context.Context, arg DecrementBalanceParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, decrementBalance, arg.Account, arg.Currency, arg.Amount)
+	var balance string
+	err := row.Scan(&balance)
+	return balance, err
+}
+
+const deleteCreditLimit = `-- name: DeleteCreditLimit :execrows
+DELETE FROM credit_limits WHERE account=$1 AND currency=$2
+`
+
+type DeleteCreditLimitParams struct {
+	Account  int64
+	Currency string
+}
+
+func (q *Queries) DeleteCreditLimit(ctx context.Context, arg DeleteCreditLimitParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCreditLimit, arg.Account, arg.Currency)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteFeeSchedule = `-- name: DeleteFeeSchedule :execrows
+DELETE FROM fee_schedules WHERE operation=$1 AND currency=$2
+`
+
+type DeleteFeeScheduleParams struct {
+	Operation FeeOperation
+	Currency  string
+}
+
+func (q *Queries) DeleteFeeSchedule(ctx context.Context, arg DeleteFeeScheduleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFeeSchedule, arg.Operation, arg.Currency)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteLimit = `-- name: DeleteLimit :execrows
+DELETE FROM limits WHERE id=$1
+`
+
+func (q *Queries) DeleteLimit(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteLimit, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getAccount = `-- name: GetAccount :one
+SELECT id, inserted_at, updated_at, name, status, external_id, metadata FROM accounts WHERE id=$1 LIMIT 1
+`
+
+func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccount, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Status,
+		&i.ExternalID,
+		&i.Metadata,
+	)
+	return i, err
+}
+
+const getAccountStatusChanges = `-- name: GetAccountStatusChanges :many
+SELECT id, account, previous, status, reason, inserted_at FROM account_status_changes WHERE account=$1 ORDER BY inserted_at ASC, id ASC
+`
+
+func (q *Queries) GetAccountStatusChanges(ctx context.Context, account int64) ([]AccountStatusChange, error) {
+	rows, err := q.db.QueryContext(ctx, getAccountStatusChanges, account)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AccountStatusChange
+	for rows.Next() {
+		var i AccountStatusChange
+		if err := rows.Scan(
+			&i.ID,
+			&i.Account,
+			&i.Previous,
+			&i.Status,
+			&i.Reason,
+			&i.InsertedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAccounts = `-- name: GetAccounts :many
+SELECT id, inserted_at, updated_at, name, status, external_id, metadata FROM accounts
+WHERE
+  id > 0
+  AND ($1::bigint IS NULL OR id > $1)
+  AND ($2::text IS NULL OR starts_with(name, $2))
+  AND ($3::timestamptz IS NULL OR inserted_at >= $3)
+  AND ($4::timestamptz IS NULL OR inserted_at < $4)
+  AND ($5::text IS NULL OR external_id = $5)
+  AND ($6::text IS NULL OR metadata @> $6::jsonb)
+ORDER BY id ASC
+LIMIT $7
+`
+
+type GetAccountsParams struct {
+	AfterID    sql.NullInt64
+	NamePrefix sql.NullString
+	Since      sql.NullTime
+	Until      sql.NullTime
+	ExternalID sql.NullString
+	Metadata   sql.NullString
+	RowLimit   int32
+}
+
+// 利用者のaccountsをidの順に、row_limit件まで取得します。システム勘定(負のid)は含めません
+// after_idを指定すると、そのidより後から取得します
+// metadataは、指定したkeyとvalueを全て含むものに絞り込みます
+func (q *Queries) GetAccounts(ctx context.Context, arg GetAccountsParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, getAccounts,
+		arg.AfterID,
+		arg.NamePrefix,
+		arg.Since,
+		arg.Until,
+		arg.ExternalID,
+		arg.Metadata,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.InsertedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Status,
+			&i.ExternalID,
+			&i.Metadata,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBalance = `-- name: GetBalance :one
+SELECT balance FROM balances WHERE account=$1 AND currency=$2 LIMIT 1
+`
+
+type GetBalanceParams struct {
+	Account  int64
+	Currency string
+}
+
+func (q *Queries) GetBalance(ctx context.Context, arg GetBalanceParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getBalance, arg.Account, arg.Currency)
+	var balance string
+	err := row.Scan(&balance)
+	return balance, err
+}
+
+const getBalances = `-- name: GetBalances :many
+SELECT currency, balance FROM balances WHERE account=$1 ORDER BY currency ASC
+`
+
+type GetBalancesRow struct {
+	Currency string
+	Balance  string
+}
+
+func (q *Queries) GetBalances(ctx context.Context, account int64) ([]GetBalancesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBalances, account)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBalancesRow
+	for rows.Next() {
+		var i GetBalancesRow
+		if err := rows.Scan(&i.Currency, &i.Balance); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCreditLimit = `-- name: GetCreditLimit :one
+SELECT credit_limit FROM credit_limits WHERE account=$1 AND currency=$2 LIMIT 1
+`
+
+type GetCreditLimitParams struct {
+	Account  int64
+	Currency string
+}
+
+func (q *Queries) GetCreditLimit(ctx context.Context, arg GetCreditLimitParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getCreditLimit, arg.Account, arg.Currency)
+	var credit_limit string
+	err := row.Scan(&credit_limit)
+	return credit_limit, err
+}
+
+const getCreditLimits = `-- name: GetCreditLimits :many
+SELECT account, currency, credit_limit, inserted_at, updated_at FROM credit_limits WHERE account=$1 ORDER BY currency ASC
+`
+
+func (q *Queries) GetCreditLimits(ctx context.Context, account int64) ([]CreditLimit, error) {
+	rows, err := q.db.QueryContext(ctx, getCreditLimits, account)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CreditLimit
+	for rows.Next() {
+		var i CreditLimit
+		if err := rows.Scan(
+			&i.Account,
+			&i.Currency,
+			&i.CreditLimit,
+			&i.InsertedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCurrencies = `-- name: GetCurrencies :many
+SELECT code, name, scale, inserted_at FROM currencies ORDER BY code ASC
+`
+
+func (q *Queries) GetCurrencies(ctx context.Context) ([]Currency, error) {
+	rows, err := q.db.QueryContext(ctx, getCurrencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Currency
+	for rows.Next() {
+		var i Currency
+		if err := rows.Scan(
+			&i.Code,
+			&i.Name,
+			&i.Scale,
+			&i.InsertedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCurrency = `-- name: GetCurrency :one
+SELECT code, name, scale, inserted_at FROM currencies WHERE code=$1 LIMIT 1
+`
+
+func (q *Queries) GetCurrency(ctx context.Context, code string) (Currency, error) {
+	row := q.db.QueryRowContext(ctx, getCurrency, code)
+	var i Currency
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.Scale,
+		&i.InsertedAt,
+	)
+	return i, err
+}
+
+const getCurrentRate = `-- name: GetCurrentRate :one
+SELECT id, source, target, rate, valid_from, valid_until, inserted_at FROM rates
+WHERE source=$1 AND target=$2 AND valid_from <= now() AND (valid_until IS NULL OR valid_until > now())
+ORDER BY valid_from DESC, id DESC
+LIMIT 1
+`
+
+type GetCurrentRateParams struct {
+	Source string
+	Target string
+}
+
+// sourceからtargetへの、今有効なレートのうちvalid_fromの最も新しいものを取得します
+func (q *Queries) GetCurrentRate(ctx context.Context, arg GetCurrentRateParams) (Rate, error) {
+	row := q.db.QueryRowContext(ctx, getCurrentRate, arg.Source, arg.Target)
+	var i Rate
+	err := row.Scan(
+		&i.ID,
+		&i.Source,
+		&i.Target,
+		&i.Rate,
+		&i.ValidFrom,
+		&i.ValidUntil,
+		&i.InsertedAt,
+	)
+	return i, err
+}
+
+const getEffectiveLimits = `-- name: GetEffectiveLimits :many
+SELECT DISTINCT ON (kind) id, account, operation, currency, kind, value, inserted_at, updated_at FROM limits
+WHERE (account=$1 OR account IS NULL) AND operation=$2 AND currency=$3
+ORDER BY kind ASC, account ASC NULLS LAST
+`
+
+type GetEffectiveLimitsParams struct {
+	Account   sql.NullInt64
+	Operation LimitOperation
+	Currency  string
+}
+
+// accountがoperationでcurrencyを出金する際の制限を、kind毎にaccountのものを既定値より優先して取得します
+func (q *Queries) GetEffectiveLimits(ctx context.Context, arg GetEffectiveLimitsParams) ([]Limit, error) {
+	rows, err := q.db.QueryContext(ctx, getEffectiveLimits, arg.Account, arg.Operation, arg.Currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Limit
+	for rows.Next() {
+		var i Limit
+		if err := rows.Scan(
+			&i.ID,
+			&i.Account,
+			&i.Operation,
+			&i.Currency,
+			&i.Kind,
+			&i.Value,
+			&i.InsertedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEscrow = `-- name: GetEscrow :one
+SELECT id, sender, recipient, currency, amount, memo, status, sender_confirmed_at, recipient_confirmed_at, expires_at, inserted_at, updated_at FROM escrows WHERE id=$1 AND (sender=$2 OR recipient=$2) LIMIT 1
+`
+
+type GetEscrowParams struct {
+	ID      int64
+	Account int64
+}
+
+// senderとrecipientのどちらからも参照できます
+func (q *Queries) GetEscrow(ctx context.Context, arg GetEscrowParams) (Escrow, error) {
+	row := q.db.QueryRowContext(ctx, getEscrow, arg.ID, arg.Account)
+	var i Escrow
+	err := row.Scan(
+		&i.ID,
+		&i.Sender,
+		&i.Recipient,
+		&i.Currency,
+		&i.Amount,
+		&i.Memo,
+		&i.Status,
+		&i.SenderConfirmedAt,
+		&i.RecipientConfirmedAt,
+		&i.ExpiresAt,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getEscrows = `-- name: GetEscrows :many
+SELECT id, sender, recipient, currency, amount, memo, status, sender_confirmed_at, recipient_confirmed_at, expires_at, inserted_at, updated_at FROM escrows
+WHERE sender=$1 OR recipient=$1
+ORDER BY id DESC
+LIMIT $2
+`
+
+type GetEscrowsParams struct {
+	Account  int64
+	RowLimit int32
+}
+
+func (q *Queries) GetEscrows(ctx context.Context, arg GetEscrowsParams) ([]Escrow, error) {
+	rows, err := q.db.QueryContext(ctx, getEscrows, arg.Account, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Escrow
+	for rows.Next() {
+		var i Escrow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Sender,
+			&i.Recipient,
+			&i.Currency,
+			&i.Amount,
+			&i.Memo,
+			&i.Status,
+			&i.SenderConfirmedAt,
+			&i.RecipientConfirmedAt,
+			&i.ExpiresAt,
+			&i.InsertedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExpectedBalances = `-- name: GetExpectedBalances :many
+SELECT
+  balances.account,
+  balances.currency,
+  balances.balance,
+  COALESCE(history.balance, 0)::DECIMAL AS expected,
+  COALESCE(posted.balance, 0)::DECIMAL AS posted
+FROM balances
+LEFT OUTER JOIN (
+  SELECT entries.account, entries.currency, SUM(entries.amount) AS balance FROM (
+    SELECT transactions.account, mints.currency, mints.amount FROM transactions JOIN mints ON transactions.mint=mints.id
+    UNION ALL
+    SELECT transactions.account, spends.currency, -spends.amount FROM transactions JOIN spends ON transactions.spend=spends.id
+    UNION ALL
+    SELECT transactions.account, transfers.currency, -transfers.amount FROM transactions JOIN transfers ON transactions.transfer=transfers.id
+    UNION ALL
+    SELECT transfers.recipient, transfers.currency, transfers.amount FROM transactions JOIN transfers ON transactions.transfer=transfers.id
+    UNION ALL
+    SELECT originals.account, reversals.currency, CASE WHEN originals.mint IS NULL THEN reversals.amount ELSE -reversals.amount END
+      FROM reversals JOIN transactions AS originals ON reversals.original=originals.id
+    UNION ALL
+    SELECT transfers.recipient, reversals.currency, -reversals.amount
+      FROM reversals JOIN transactions AS originals ON reversals.original=originals.id JOIN transfers ON originals.transfer=transfers.id
+    UNION ALL
+    SELECT transactions.account, conversions.source, -conversions.amount FROM transactions JOIN conversions ON transactions.conversion=conversions.id
+    UNION ALL
+    SELECT conversions.recipient, conversions.target, conversions.converted_amount FROM transactions JOIN conversions ON transactions.conversion=conversions.id
+    UNION ALL
+    SELECT journal_legs.account, journal_legs.currency, journal_legs.amount FROM transactions JOIN journal_legs ON transactions.journal=journal_legs.journal
+    UNION ALL
+    SELECT transactions.account, fees.currency, -fees.amount FROM transactions JOIN fees ON transactions.fee=fees.id
+    UNION ALL
+    SELECT fees.recipient, fees.currency, fees.amount FROM transactions JOIN fees ON transactions.fee=fees.id
+    UNION ALL
+    SELECT CASE WHEN escrow_movements.action='release' THEN escrows.recipient ELSE escrows.sender END, escrows.currency, CASE WHEN escrow_movements.action='deposit' THEN -escrows.amount ELSE escrows.amount END
+      FROM transactions JOIN escrow_movements ON transactions.escrow_movement=escrow_movements.id JOIN escrows ON escrow_movements.escrow=escrows.id
+  ) AS entries GROUP BY entries.account, entries.currency
+) AS history ON balances.account=history.account AND balances.currency=history.currency
+LEFT OUTER JOIN (
+  SELECT postings.account, postings.currency, SUM(postings.amount) AS balance FROM postings GROUP BY postings.account, postings.currency
+) AS posted ON balances.account=posted.account AND balances.currency=posted.currency
+ORDER BY balances.account ASC, balances.currency ASC
+`
+
+type GetExpectedBalancesRow struct {
+	Account  int64
+	Currency string
+	Balance  string
+	Expected string
+	Posted   string
+}
+
+// mints, spends, transfers, reversals, conversions, journal_legs, fees, escrowsの履歴から導出した残高と、balancesに記録された残高、postingsの合計をaccountとcurrency毎に並べます
+func (q *Queries) GetExpectedBalances(ctx context.Context) ([]GetExpectedBalancesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getExpectedBalances)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExpectedBalancesRow
+	for rows.Next() {
+		var i GetExpectedBalancesRow
+		if err := rows.Scan(
+			&i.Account,
+			&i.Currency,
+			&i.Balance,
+			&i.Expected,
+			&i.Posted,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExpiredEscrows = `-- name: GetExpiredEscrows :many
+SELECT id, expires_at FROM escrows
+WHERE status='funded' AND expires_at <= now()
+  AND ($1::timestamptz IS NULL OR (expires_at, id) > ($1, $2::bigint))
+ORDER BY expires_at ASC, id ASC
+LIMIT $3
+`
+
+type GetExpiredEscrowsParams struct {
+	AfterExpiresAt sql.NullTime
+	AfterID        sql.NullInt64
+	RowLimit       int32
+}
+
+type GetExpiredEscrowsRow struct {
+	ID        int64
+	ExpiresAt time.Time
+}
+
+// expires_atを過ぎても双方の確認が揃わなかったfundedのescrowsを、古いものからrow_limit件まで取得します
+// after_expires_at, after_idを指定すると、その位置より後から取得します
+func (q *Queries) GetExpiredEscrows(ctx context.Context, arg GetExpiredEscrowsParams) ([]GetExpiredEscrowsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getExpiredEscrows, arg.AfterExpiresAt, arg.AfterID, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExpiredEscrowsRow
+	for rows.Next() {
+		var i GetExpiredEscrowsRow
+		if err := rows.Scan(&i.ID, &i.ExpiresAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeeSchedule = `-- name: GetFeeSchedule :one
+SELECT id, operation, currency, flat, percentage, min, max, account, inserted_at, updated_at FROM fee_schedules WHERE operation=$1 AND currency=$2 LIMIT 1
+`
+
+type GetFeeScheduleParams struct {
+	Operation FeeOperation
+	Currency  string
+}
+
+func (q *Queries) GetFeeSchedule(ctx context.Context, arg GetFeeScheduleParams) (FeeSchedule, error) {
+	row := q.db.QueryRowContext(ctx, getFeeSchedule, arg.Operation, arg.Currency)
+	var i FeeSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.Operation,
+		&i.Currency,
+		&i.Flat,
+		&i.Percentage,
+		&i.Min,
+		&i.Max,
+		&i.Account,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getFeeSchedules = `-- name: GetFeeSchedules :many
+SELECT id, operation, currency, flat, percentage, min, max, account, inserted_at, updated_at FROM fee_schedules ORDER BY operation ASC, currency ASC
+`
+
+func (q *Queries) GetFeeSchedules(ctx context.Context) ([]FeeSchedule, error) {
+	rows, err := q.db.QueryContext(ctx, getFeeSchedules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FeeSchedule
+	for rows.Next() {
+		var i FeeSchedule
+		if err := rows.Scan(
+			&i.ID,
+			&i.Operation,
+			&i.Currency,
+			&i.Flat,
+			&i.Percentage,
+			&i.Min,
+			&i.Max,
+			&i.Account,
+			&i.InsertedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHeldAmount = `-- name: GetHeldAmount :one
+SELECT COALESCE(SUM(amount), 0)::DECIMAL AS amount FROM holds WHERE account=$1 AND currency=$2 AND status='active' AND expires_at > now()
+`
+
+type GetHeldAmountParams struct {
+	Account  int64
+	Currency string
+}
+
+func (q *Queries) GetHeldAmount(ctx context.Context, arg GetHeldAmountParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getHeldAmount, arg.Account, arg.Currency)
+	var amount string
+	err := row.Scan(&amount)
+	return amount, err
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT account, key, request_hash, transaction, inserted_at FROM idempotency_keys WHERE account=$1 AND key=$2 LIMIT 1
+`
+
+type GetIdempotencyKeyParams struct {
+	Account int64
+	Key     string
+}
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	row := q.db.QueryRowContext(ctx, getIdempotencyKey, arg.Account, arg.Key)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.Account,
+		&i.Key,
+		&i.RequestHash,
+		&i.Transaction,
+		&i.InsertedAt,
+	)
+	return i, err
+}
+
+const getLimits = `-- name: GetLimits :many
+SELECT id, account, operation, currency, kind, value, inserted_at, updated_at FROM limits ORDER BY account ASC NULLS FIRST, operation ASC, currency ASC, kind ASC
+`
+
+func (q *Queries) GetLimits(ctx context.Context) ([]Limit, error) {
+	rows, err := q.db.QueryContext(ctx, getLimits)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Limit
+	for rows.Next() {
+		var i Limit
+		if err := rows.Scan(
+			&i.ID,
+			&i.Account,
+			&i.Operation,
+			&i.Currency,
+			&i.Kind,
+			&i.Value,
+			&i.InsertedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOutgoingUsage = `-- name: GetOutgoingUsage :one
+SELECT
+  COUNT(DISTINCT transactions.id) AS count,
+  COALESCE(SUM(-postings.amount), 0)::DECIMAL AS volume
+FROM postings
+JOIN transactions ON postings.transaction=transactions.id
+WHERE postings.account=$1
+  AND postings.amount < 0
+  AND transactions.inserted_at > timezone('utc':: text, now()) - $2::integer * interval '1 second'
+  AND (CASE $3::text
+    WHEN 'transfer' THEN transactions.transfer IS NOT NULL OR transactions.journal IS NOT NULL OR transactions.escrow_movement IS NOT NULL
+    WHEN 'spend' THEN transactions.spend IS NOT NULL
+  END)
+  AND postings.currency=$4
+`
+
+type GetOutgoingUsageParams struct {
+	Account       int64
+	WindowSeconds int32
+	Operation     string
+	Currency      string
+}
+
+type GetOutgoingUsageRow struct {
+	Count  int64
+	Volume string
+}
+
+// accountが直近window_seconds秒の間にoperationでcurrencyを出金した回数と金額の合計
+// transferにはjournalでの出金とescrowへの預け入れも含めます。手数料や取り消しなどは含めません
+func (q *Queries) GetOutgoingUsage(ctx context.Context, arg GetOutgoingUsageParams) (GetOutgoingUsageRow, error) {
+	row := q.db.QueryRowContext(ctx, getOutgoingUsage,
+		arg.Account,
+		arg.WindowSeconds,
+		arg.Operation,
+		arg.Currency,
+	)
+	var i GetOutgoingUsageRow
+	err := row.Scan(&i.Count, &i.Volume)
+	return i, err
+}
+
+const getPaymentRequest = `-- name: GetPaymentRequest :one
+SELECT id, requester, payer, currency, amount, memo, status, expires_at, transaction, inserted_at, updated_at, (status='pending' AND expires_at <= now())::boolean AS expired FROM payment_requests
+WHERE id=$1 AND (requester=$2 OR payer=$2)
+LIMIT 1
+`
+
+type GetPaymentRequestParams struct {
+	ID      int64
+	Account int64
+}
+
+type GetPaymentRequestRow struct {
+	ID          int64
+	Requester   int64
+	Payer       int64
+	Currency    string
+	Amount      string
+	Memo        sql.NullString
+	Status      PaymentRequestStatus
+	ExpiresAt   time.Time
+	Transaction sql.NullInt64
+	InsertedAt  time.Time
+	UpdatedAt   time.Time
+	Expired     bool
+}
+
+// requesterとpayerのどちらからも参照できます
+func (q *Queries) GetPaymentRequest(ctx context.Context, arg GetPaymentRequestParams) (GetPaymentRequestRow, error) {
+	row := q.db.QueryRowContext(ctx, getPaymentRequest, arg.ID, arg.Account)
+	var i GetPaymentRequestRow
+	err := row.Scan(
+		&i.ID,
+		&i.Requester,
+		&i.Payer,
+		&i.Currency,
+		&i.Amount,
+		&i.Memo,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.Transaction,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+		&i.Expired,
+	)
+	return i, err
+}
+
+const getPaymentRequests = `-- name: GetPaymentRequests :many
+SELECT id, requester, payer, currency, amount, memo, status, expires_at, transaction, inserted_at, updated_at, (status='pending' AND expires_at <= now())::boolean AS expired FROM payment_requests
+WHERE
+  (CASE $1::text
+    WHEN 'incoming' THEN payer=$2
+    WHEN 'outgoing' THEN requester=$2
+    ELSE payer=$2 OR requester=$2
+  END)
+  AND ($3::text IS NULL OR $3 = CASE
+    WHEN status='pending' AND expires_at <= now() THEN 'expired'
+    ELSE status::text
+  END)
+ORDER BY id DESC
+LIMIT $4
+`
+
+type GetPaymentRequestsParams struct {
+	Direction sql.NullString
+	Account   int64
+	Status    sql.NullString
+	RowLimit  int32
+}
+
+type GetPaymentRequestsRow struct {
+	ID          int64
+	Requester   int64
+	Payer       int64
+	Currency    string
+	Amount      string
+	Memo        sql.NullString
+	Status      PaymentRequestStatus
+	ExpiresAt   time.Time
+	Transaction sql.NullInt64
+	InsertedAt  time.Time
+	UpdatedAt   time.Time
+	Expired     bool
+}
+
+// directionがincomingならaccountが支払いを求められたもの、outgoingならaccountが求めたもの、省略すると両方です
+// statusにはexpiredも指定できます
+func (q *Queries) GetPaymentRequests(ctx context.Context, arg GetPaymentRequestsParams) ([]GetPaymentRequestsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPaymentRequests,
+		arg.Direction,
+		arg.Account,
+		arg.Status,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPaymentRequestsRow
+	for rows.Next() {
+		var i GetPaymentRequestsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Requester,
+			&i.Payer,
+			&i.Currency,
+			&i.Amount,
+			&i.Memo,
+			&i.Status,
+			&i.ExpiresAt,
+			&i.Transaction,
+			&i.InsertedAt,
+			&i.UpdatedAt,
+			&i.Expired,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostedBalance = `-- name: GetPostedBalance :one
+SELECT COALESCE(SUM(amount), 0)::DECIMAL AS balance FROM postings WHERE account=$1 AND currency=$2
+`
+
+type GetPostedBalanceParams struct {
+	Account  int64
+	Currency string
+}
+
+func (q *Queries) GetPostedBalance(ctx context.Context, arg GetPostedBalanceParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getPostedBalance, arg.Account, arg.Currency)
+	var balance string
+	err := row.Scan(&balance)
+	return balance, err
+}
+
+const getRates = `-- name: GetRates :many
+SELECT id, source, target, rate, valid_from, valid_until, inserted_at FROM rates
+WHERE
+  ($1::text IS NULL OR source = $1)
+  AND ($2::text IS NULL OR target = $2)
+ORDER BY source ASC, target ASC, valid_from DESC, id DESC
+`
+
+type GetRatesParams struct {
+	Source sql.NullString
+	Target sql.NullString
+}
+
+func (q *Queries) GetRates(ctx context.Context, arg GetRatesParams) ([]Rate, error) {
+	rows, err := q.db.QueryContext(ctx, getRates, arg.Source, arg.Target)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Rate
+	for rows.Next() {
+		var i Rate
+		if err := rows.Scan(
+			&i.ID,
+			&i.Source,
+			&i.Target,
+			&i.Rate,
+			&i.ValidFrom,
+			&i.ValidUntil,
+			&i.InsertedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReversedAmount = `-- name: GetReversedAmount :one
+SELECT COALESCE(SUM(amount), 0)::DECIMAL AS amount FROM reversals WHERE original=$1
+`
+
+func (q *Queries) GetReversedAmount(ctx context.Context, original int64) (string, error) {
+	row := q.db.QueryRowContext(ctx, getReversedAmount, original)
+	var amount string
+	err := row.Scan(&amount)
+	return amount, err
+}
+
+const getSchedule = `-- name: GetSchedule :one
+SELECT id, account, recipient, currency, amount, memo, frequency, status, starts_at, ends_at, next_run_at, inserted_at, updated_at FROM schedules WHERE id=$1 AND account=$2 LIMIT 1
+`
+
+type GetScheduleParams struct {
+	ID      int64
+	Account int64
+}
+
+func (q *Queries) GetSchedule(ctx context.Context, arg GetScheduleParams) (Schedule, error) {
+	row := q.db.QueryRowContext(ctx, getSchedule, arg.ID, arg.Account)
+	var i Schedule
+	err := row.Scan(
+		&i.ID,
+		&i.Account,
+		&i.Recipient,
+		&i.Currency,
+		&i.Amount,
+		&i.Memo,
+		&i.Frequency,
+		&i.Status,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.NextRunAt,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getScheduleRuns = `-- name: GetScheduleRuns :many
+SELECT id, schedule, due_at, status, transaction, error, inserted_at FROM schedule_runs WHERE schedule=$1 ORDER BY due_at DESC LIMIT $2
+`
+
+type GetScheduleRunsParams struct {
+	Schedule int64
+	RowLimit int32
+}
+
+// 新しい回から順にrow_limit件まで取得します
+func (q *Queries) GetScheduleRuns(ctx context.Context, arg GetScheduleRunsParams) ([]ScheduleRun, error) {
+	rows, err := q.db.QueryContext(ctx, getScheduleRuns, arg.Schedule, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScheduleRun
+	for rows.Next() {
+		var i ScheduleRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.Schedule,
+			&i.DueAt,
+			&i.Status,
+			&i.Transaction,
+			&i.Error,
+			&i.InsertedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSchedules = `-- name: GetSchedules :many
+SELECT id, account, recipient, currency, amount, memo, frequency, status, starts_at, ends_at, next_run_at, inserted_at, updated_at FROM schedules WHERE account=$1 ORDER BY id ASC
+`
+
+func (q *Queries) GetSchedules(ctx context.Context, account int64) ([]Schedule, error) {
+	rows, err := q.db.QueryContext(ctx, getSchedules, account)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Schedule
+	for rows.Next() {
+		var i Schedule
+		if err := rows.Scan(
+			&i.ID,
+			&i.Account,
+			&i.Recipient,
+			&i.Currency,
+			&i.Amount,
+			&i.Memo,
+			&i.Frequency,
+			&i.Status,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.NextRunAt,
+			&i.InsertedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTransactions = `-- name: GetTransactions :many
+SELECT
+  transactions.id AS transaction_id,
+  transactions.account AS account_id,
+  transactions.inserted_at AS inserted_at,
+  transactions.metadata AS metadata,
+  COALESCE(mints.memo, spends.memo, transfers.memo, journals.memo, escrows.memo) AS memo,
+  COALESCE(mints.reference_type, spends.reference_type, transfers.reference_type) AS reference_type,
+  COALESCE(mints.reference_id, spends.reference_id, transfers.reference_id) AS reference_id,
+
+  mints.id AS mint_id,
+  mints.amount AS mint_amount,
+
+  spends.id AS spend_id,
+  spends.amount AS spend_amount,
+
+  transfers.id AS transfer_id,
+  transfers.amount AS transfer_amount,
+  transfers.recipient AS transfer_recipient,
+
+  reversals.id AS reversal_id,
+  reversals.amount AS reversal_amount,
+  reversals.original AS reversal_original,
+
+  conversions.id AS conversion_id,
+  conversions.amount AS conversion_amount,
+  conversions.source AS conversion_source,
+  conversions.converted_amount AS conversion_converted_amount,
+  conversions.target AS conversion_target,
+  conversions.rate AS conversion_rate,
+  conversions.recipient AS conversion_recipient,
+
+  journals.id AS journal_id,
+
+  fees.id AS fee_id,
+  fees.amount AS fee_amount,
+  fees.original AS fee_original,
+  fees.recipient AS fee_recipient,
+
+  escrow_movements.id AS escrow_movement_id,
+  escrow_movements.action AS escrow_action,
+  escrows.id AS escrow_id,
+  escrows.amount AS escrow_amount,
+  escrows.sender AS escrow_sender,
+  escrows.recipient AS escrow_recipient,
+
+  entries.currency AS currency,
+  entries.delta::DECIMAL AS delta,
+  entries.balance::DECIMAL AS balance,
+  counterparties.id AS counterparty
+FROM transactions
+JOIN (
+  SELECT
+    postings.transaction,
+    postings.currency,
+    SUM(postings.amount) AS delta,
+    (array_agg(postings.balance_after ORDER BY postings.id DESC))[1] AS balance
+  FROM postings
+  JOIN transactions ON postings.transaction=transactions.id
+  WHERE postings.account=$1
+    AND ($2::timestamptz IS NULL OR (transactions.inserted_at, transactions.id) >= ($2, $3::bigint))
+  GROUP BY postings.transaction, postings.currency
+  UNION ALL
+  SELECT
+    transactions.id,
+    escrows.currency,
+    0,
+    COALESCE((
+      SELECT earlier.balance_after FROM postings AS earlier
+      JOIN transactions AS earlier_transactions ON earlier.transaction=earlier_transactions.id
+      WHERE earlier.account=$1 AND earlier.currency=escrows.currency
+        AND (earlier_transactions.inserted_at, earlier_transactions.id) < (transactions.inserted_at, transactions.id)
+      ORDER BY earlier_transactions.inserted_at DESC, earlier_transactions.id DESC, earlier.id DESC
+      LIMIT 1
+    ), 0)
+  FROM transactions
+  JOIN escrow_movements ON transactions.escrow_movement=escrow_movements.id
+  JOIN escrows ON escrow_movements.escrow=escrows.id
+  WHERE (escrows.sender=$1 OR escrows.recipient=$1)
+    AND NOT EXISTS (SELECT 1 FROM postings WHERE postings.transaction=transactions.id AND postings.account=$1)
+    AND ($2::timestamptz IS NULL OR (transactions.inserted_at, transactions.id) >= ($2, $3::bigint))
+) AS entries ON transactions.id=entries.transaction
+LEFT OUTER JOIN mints ON transactions.mint=mints.id
+LEFT OUTER JOIN spends ON transactions.spend=spends.id
+LEFT OUTER JOIN transfers ON transactions.transfer=transfers.id
+LEFT OUTER JOIN reversals ON transactions.reversal=reversals.id
+LEFT OUTER JOIN conversions ON transactions.conversion=conversions.id
+LEFT OUTER JOIN journals ON transactions.journal=journals.id
+LEFT OUTER JOIN fees ON transactions.fee=fees.id
+LEFT OUTER JOIN escrow_movements ON transactions.escrow_movement=escrow_movements.id
+LEFT OUTER JOIN escrows ON escrow_movements.escrow=escrows.id
+LEFT OUTER JOIN accounts AS counterparties ON counterparties.id=COALESCE((
+  SELECT others.account FROM postings AS others
+  WHERE others.transaction=transactions.id AND others.account<>$1 AND others.account >= 0
+  ORDER BY others.currency=entries.currency DESC
+  LIMIT 1
+), CASE $1 WHEN escrows.sender THEN escrows.recipient WHEN escrows.recipient THEN escrows.sender END)
+WHERE
+  ($2::timestamptz IS NULL OR (transactions.inserted_at, transactions.id, entries.currency) > ($2, $3::bigint, $4::text))
+  AND ($5::text IS NULL OR $5 = CASE
+    WHEN transactions.mint IS NOT NULL THEN 'mint'
+    WHEN transactions.spend IS NOT NULL THEN 'spend'
+    WHEN transactions.transfer IS NOT NULL THEN 'transfer'
+    WHEN transactions.reversal IS NOT NULL THEN 'reversal'
+    WHEN transactions.conversion IS NOT NULL THEN 'conversion'
+    WHEN transactions.journal IS NOT NULL THEN 'journal'
+    WHEN transactions.fee IS NOT NULL THEN 'fee'
+    WHEN transactions.escrow_movement IS NOT NULL THEN 'escrow'
+  END)
+  AND ($6::text IS NULL OR ($6 = 'incoming' AND entries.delta > 0) OR ($6 = 'outgoing' AND entries.delta < 0))
+  AND ($7::bigint IS NULL OR counterparties.id=$7 OR EXISTS (
+    SELECT 1 FROM postings AS others
+    WHERE others.transaction=transactions.id AND others.account=$7 AND others.account<>$1
+  ))
+  AND ($8::decimal IS NULL OR abs(entries.delta) >= $8)
+  AND ($9::decimal IS NULL OR abs(entries.delta) <= $9)
+  AND ($10::timestamptz IS NULL OR transactions.inserted_at >= $10)
+  AND ($11::timestamptz IS NULL OR transactions.inserted_at < $11)
+  AND ($12::text IS NULL OR entries.currency = $12)
+  AND ($13::text IS NULL OR transactions.metadata @> $13::jsonb)
+  AND ($14::text IS NULL OR COALESCE(mints.memo, spends.memo, transfers.memo, journals.memo, escrows.memo) ILIKE '%' || $14 || '%')
+ORDER BY transactions.inserted_at ASC, transactions.id ASC, entries.currency ASC
+LIMIT $15
+`
+
+type GetTransactionsParams struct {
+	Account         int64
+	AfterInsertedAt sql.NullTime
+	AfterID         sql.NullInt64
+	AfterCurrency   sql.NullString
+	Type            sql.NullString
+	Direction       sql.NullString
+	Counterparty    sql.NullInt64
+	MinAmount       sql.NullString
+	MaxAmount       sql.NullString
+	Since           sql.NullTime
+	Until           sql.NullTime
+	Currency        sql.NullString
+	Metadata        sql.NullString
+	Memo            sql.NullString
+	RowLimit        int32
+}
+
+type GetTransactionsRow struct {
+	TransactionID             int64
+	AccountID                 int64
+	InsertedAt                time.Time
+	Metadata                  json.RawMessage
+	Memo                      sql.NullString
+	ReferenceType             sql.NullString
+	ReferenceID               sql.NullString
+	MintID                    sql.NullInt64
+	MintAmount                sql.NullString
+	SpendID                   sql.NullInt64
+	SpendAmount               sql.NullString
+	TransferID                sql.NullInt64
+	TransferAmount            sql.NullString
+	TransferRecipient         sql.NullInt64
+	ReversalID                sql.NullInt64
+	ReversalAmount            sql.NullString
+	ReversalOriginal          sql.NullInt64
+	ConversionID              sql.NullInt64
+	ConversionAmount          sql.NullString
+	ConversionSource          sql.NullString
+	ConversionConvertedAmount sql.NullString
+	ConversionTarget          sql.NullString
+	ConversionRate            sql.NullString
+	ConversionRecipient       sql.NullInt64
+	JournalID                 sql.NullInt64
+	FeeID                     sql.NullInt64
+	FeeAmount                 sql.NullString
+	FeeOriginal               sql.NullInt64
+	FeeRecipient              sql.NullInt64
+	EscrowMovementID          sql.NullInt64
+	EscrowAction              NullEscrowAction
+	EscrowID                  sql.NullInt64
+	EscrowAmount              sql.NullString
+	EscrowSender              sql.NullInt64
+	EscrowRecipient           sql.NullInt64
+	Currency                  string
+	Delta                     string
+	Balance                   string
+	Counterparty              sql.NullInt64
+}
+
+// accountのpostingsを持つtransactionsをcurrency毎に1行として、(inserted_at, id, currency)の順にrow_limit件まで取得します
+// accountが当事者のescrowsの資金の動きは、accountの残高が動かなくても増減0の行として含めます
+// conversionsのように複数のcurrencyを動かすtransactionは、それぞれのcurrencyの行になります
+// after_inserted_at, after_id, after_currencyを指定すると、その位置より後から取得します
+// direction, min_amount, max_amountは、accountから見た増減(postingsの合計)について判定し、増減0の行はdirectionのどちらにも含めません
+// metadataは、指定したkeyとvalueを全て含むtransactionsに絞り込みます
+// memoは、大文字と小文字を区別せずにその文字列を含むものに絞り込みます。%と_はエスケープしてください
+// balanceは絞り込みやページ送りに関わらず、postingsに記録された取引直後のaccountの残高です
+// entriesはカーソルより前の履歴を集計しないよう、after_inserted_at, after_idで先に絞り込みます
+// counterpartyはaccount以外でpostingsを持つ利用者のaccountで、同じcurrencyのものを優先し、システム勘定(負のid)は含めません
+// そのようなaccountがなければ、escrowsのもう一方の当事者です
+func (q *Queries) GetTransactions(ctx context.Context, arg GetTransactionsParams) ([]GetTransactionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTransactions,
+		arg.Account,
+		arg.AfterInsertedAt,
+		arg.AfterID,
+		arg.AfterCurrency,
+		arg.Type,
+		arg.Direction,
+		arg.Counterparty,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.Since,
+		arg.Until,
+		arg.Currency,
+		arg.Metadata,
+		arg.Memo,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTransactionsRow
+	for rows.Next() {
+		var i GetTransactionsRow
+		if err := rows.Scan(
+			&i.TransactionID,
+			&i.AccountID,
+			&i.InsertedAt,
+			&i.Metadata,
+			&i.Memo,
+			&i.ReferenceType,
+			&i.ReferenceID,
+			&i.MintID,
+			&i.MintAmount,
+			&i.SpendID,
+			&i.SpendAmount,
+			&i.TransferID,
+			&i.TransferAmount,
+			&i.TransferRecipient,
+			&i.ReversalID,
+			&i.ReversalAmount,
+			&i.ReversalOriginal,
+			&i.ConversionID,
+			&i.ConversionAmount,
+			&i.ConversionSource,
+			&i.ConversionConvertedAmount,
+			&i.ConversionTarget,
+			&i.ConversionRate,
+			&i.ConversionRecipient,
+			&i.JournalID,
+			&i.FeeID,
+			&i.FeeAmount,
+			&i.FeeOriginal,
+			&i.FeeRecipient,
+			&i.EscrowMovementID,
+			&i.EscrowAction,
+			&i.EscrowID,
+			&i.EscrowAmount,
+			&i.EscrowSender,
+			&i.EscrowRecipient,
+			&i.Currency,
+			&i.Delta,
+			&i.Balance,
+			&i.Counterparty,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const incrementBalance = `-- name: IncrementBalance :one
+INSERT INTO balances (
+  account, currency, balance
+) VALUES (
+  $1, $2, $3
+) ON CONFLICT (account, currency) DO UPDATE SET balance = balances.balance + EXCLUDED.balance
+RETURNING balance
+`
+
+type IncrementBalanceParams struct {
+	Account  int64
+	Currency string
+	Amount   string
+}
+
+// 初めて受け取るcurrencyであれば行を作ります。増やした後の残高を返します
+func (q *Queries) IncrementBalance(ctx context.Context, arg IncrementBalanceParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, incrementBalance, arg.Account, arg.Currency, arg.Amount)
+	var balance string
+	err := row.Scan(&balance)
+	return balance, err
+}
+
+const insertAccount = `-- name: InsertAccount :one
+INSERT INTO accounts (
+  name, external_id, metadata
+) VALUES (
+  $1, $2, $3
+) RETURNING id
+`
+
+type InsertAccountParams struct {
+	Name       sql.NullString
+	ExternalID sql.NullString
+	Metadata   json.RawMessage
+}
+
+func (q *Queries) InsertAccount(ctx context.Context, arg InsertAccountParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertAccount, arg.Name, arg.ExternalID, arg.Metadata)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const insertAccountStatusChange = `-- name: InsertAccountStatusChange :one
+INSERT INTO account_status_changes (
+  account, previous, status, reason
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, account, previous, status, reason, inserted_at
+`
+
+type InsertAccountStatusChangeParams struct {
+	Account  int64
+	Previous AccountStatus
+	Status   AccountStatus
+	Reason   string
+}
+
+func (q *Queries) InsertAccountStatusChange(ctx context.Context, arg InsertAccountStatusChangeParams) (AccountStatusChange, error) {
+	row := q.db.QueryRowContext(ctx, insertAccountStatusChange,
+		arg.Account,
+		arg.Previous,
+		arg.Status,
+		arg.Reason,
+	)
+	var i AccountStatusChange
+	err := row.Scan(
+		&i.ID,
+		&i.Account,
+		&i.Previous,
+		&i.Status,
+		&i.Reason,
+		&i.InsertedAt,
+	)
+	return i, err
+}
+
+const insertBalance = `-- name: InsertBalance :exec
+INSERT INTO balances (
+  account, currency, balance
+) VALUES (
+  $1, $2, 0
+) ON CONFLICT (account, currency) DO NOTHING
+`
+
+type InsertBalanceParams struct {
+	Account  int64
+	Currency string
+}
+
+// 既に行があれば何もしません
+func (q *Queries) InsertBalance(ctx context.Context, arg InsertBalanceParams) error {
+	_, err := q.db.ExecContext(ctx, insertBalance, arg.Account, arg.Currency)
+	return err
+}
+
+const insertConversion = `-- name: InsertConversion :one
+INSERT INTO conversions (
+  amount, source, converted_amount, target, rate, rate_id, recipient
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) RETURNING id
+`
+
+type InsertConversionParams struct {
+	Amount          string
+	Source          string
+	ConvertedAmount string
+	Target          string
+	Rate            string
+	RateID          int64
+	Recipient       int64
+}
+
+func (q *Queries) InsertConversion(ctx context.Context, arg InsertConversionParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertConversion,
+		arg.Amount,
+		arg.Source,
+		arg.ConvertedAmount,
+		arg.Target,
+		arg.Rate,
+		arg.RateID,
+		arg.Recipient,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const insertCurrency = `-- name: InsertCurrency :one
+INSERT INTO currencies (
+  code, name, scale
+) VALUES (
+  $1, $2, $3
+) RETURNING code, name, scale, inserted_at
+`
+
+type InsertCurrencyParams struct {
+	Code  string
+	Name  string
+	Scale int32
+}
+
+func (q *Queries) InsertCurrency(ctx context.Context, arg InsertCurrencyParams) (Currency, error) {
+	row := q.db.QueryRowContext(ctx, insertCurrency, arg.Code, arg.Name, arg.Scale)
+	var i Currency
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.Scale,
+		&i.InsertedAt,
+	)
+	return i, err
+}
+
+const insertEscrow = `-- name: InsertEscrow :one
+INSERT INTO escrows (
+  sender, recipient, currency, amount, memo, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, now() + $6::integer * interval '1 second'
+) RETURNING id, sender, recipient, currency, amount, memo, status, sender_confirmed_at, recipient_confirmed_at, expires_at, inserted_at, updated_at
+`
+
+type InsertEscrowParams struct {
+	Sender    int64
+	Recipient int64
+	Currency  string
+	Amount    string
+	Memo      sql.NullString
+	Ttl       int32
+}
+
+func (q *Queries) InsertEscrow(ctx context.Context, arg InsertEscrowParams) (Escrow, error) {
+	row := q.db.QueryRowContext(ctx, insertEscrow,
+		arg.Sender,
+		arg.Recipient,
+		arg.Currency,
+		arg.Amount,
+		arg.Memo,
+		arg.Ttl,
+	)
+	var i Escrow
+	err := row.Scan(
+		&i.ID,
+		&i.Sender,
+		&i.Recipient,
+		&i.Currency,
+		&i.Amount,
+		&i.Memo,
+		&i.Status,
+		&i.SenderConfirmedAt,
+		&i.RecipientConfirmedAt,
+		&i.ExpiresAt,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertEscrowMovement = `-- name: InsertEscrowMovement :one
+INSERT INTO escrow_movements (
+  escrow, action
+) VALUES (
+  $1, $2
+) RETURNING id
+`
+
+type InsertEscrowMovementParams struct {
+	Escrow int64
+	Action EscrowAction
+}
+
+func (q *Queries) InsertEscrowMovement(ctx context.Context, arg InsertEscrowMovementParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertEscrowMovement, arg.Escrow, arg.Action)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const insertFee = `-- name: InsertFee :one
+INSERT INTO fees (
+  amount, currency, original, recipient, schedule
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING id
+`
+
+type InsertFeeParams struct {
+	Amount    string
+	Currency  string
+	Original  int64
+	Recipient int64
+	Schedule  sql.NullInt64
+}
+
+func (q *Queries) InsertFee(ctx context.Context, arg InsertFeeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertFee,
+		arg.Amount,
+		arg.Currency,
+		arg.Original,
+		arg.Recipient,
+		arg.Schedule,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const insertHold = `-- name: InsertHold :one
+INSERT INTO holds (
+  account, amount, currency, expires_at
+) VALUES (
+  $1, $2, $3, now() + $4::integer * interval '1 second'
+) RETURNING id, account, amount, status, expires_at, inserted_at, updated_at, transaction, currency
+`
+
+type InsertHoldParams struct {
+	Account  int64
+	Amount   string
+	Currency string
+	Ttl      int32
+}
+
+func (q *Queries) InsertHold(ctx context.Context, arg InsertHoldParams) (Hold, error) {
+	row := q.db.QueryRowContext(ctx, insertHold,
+		arg.Account,
+		arg.Amount,
+		arg.Currency,
+		arg.Ttl,
+	)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.Account,
+		&i.Amount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+		&i.Transaction,
+		&i.Currency,
+	)
+	return i, err
+}
+
+const insertIdempotencyKey = `-- name: InsertIdempotencyKey :exec
+INSERT INTO idempotency_keys (
+  account, key, request_hash, transaction
+) VALUES (
+  $1, $2, $3, $4
+)
+`
+
+type InsertIdempotencyKeyParams struct {
+	Account     int64
+	Key         string
+	RequestHash string
+	Transaction int64
+}
+
+func (q *Queries) InsertIdempotencyKey(ctx context.Context, arg InsertIdempotencyKeyParams) error {
+	_, err := q.db.ExecContext(ctx, insertIdempotencyKey,
+		arg.Account,
+		arg.Key,
+		arg.RequestHash,
+		arg.Transaction,
+	)
+	return err
+}
+
+const insertJournal = `-- name: InsertJournal :one
+INSERT INTO journals (
+  memo
+) VALUES (
+  $1
+) RETURNING id
+`
+
+func (q *Queries) InsertJournal(ctx context.Context, memo sql.NullString) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertJournal, memo)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const insertJournalLeg = `-- name: InsertJournalLeg :exec
+INSERT INTO journal_legs (
+  journal, account, currency, amount
+) VALUES (
+  $1, $2, $3, $4
+)
+`
+
+type InsertJournalLegParams struct {
+	Journal  int64
+	Account  int64
+	Currency string
+	Amount   string
+}
+
+func (q *Queries) InsertJournalLeg(ctx context.Context, arg InsertJournalLegParams) error {
+	_, err := q.db.ExecContext(ctx, insertJournalLeg,
+		arg.Journal,
+		arg.Account,
+		arg.Currency,
+		arg.Amount,
+	)
+	return err
+}
+
+const insertMint = `-- name: InsertMint :one
+INSERT INTO mints (
+  amount, currency, memo, reference_type, reference_id
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING id
+`
+
+type InsertMintParams struct {
+	Amount        string
+	Currency      string
+	Memo          sql.NullString
+	ReferenceType sql.NullString
+	ReferenceID   sql.NullString
+}
+
+func (q *Queries) InsertMint(ctx context.Context, arg InsertMintParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertMint,
+		arg.Amount,
+		arg.Currency,
+		arg.Memo,
+		arg.ReferenceType,
+		arg.ReferenceID,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const insertPaymentRequest = `-- name: InsertPaymentRequest :one
+INSERT INTO payment_requests (
+  requester, payer, currency, amount, memo, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, now() + $6::integer * interval '1 second'
+) RETURNING id, requester, payer, currency, amount, memo, status, expires_at, transaction, inserted_at, updated_at
+`
+
+type InsertPaymentRequestParams struct {
+	Requester int64
+	Payer     int64
+	Currency  string
+	Amount    string
+	Memo      sql.NullString
+	Ttl       int32
+}
+
+func (q *Queries) InsertPaymentRequest(ctx context.Context, arg InsertPaymentRequestParams) (PaymentRequest, error) {
+	row := q.db.QueryRowContext(ctx, insertPaymentRequest,
+		arg.Requester,
+		arg.Payer,
+		arg.Currency,
+		arg.Amount,
+		arg.Memo,
+		arg.Ttl,
+	)
+	var i PaymentRequest
+	err := row.Scan(
+		&i.ID,
+		&i.Requester,
+		&i.Payer,
+		&i.Currency,
+		&i.Amount,
+		&i.Memo,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.Transaction,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertPosting = `-- name: InsertPosting :exec
+INSERT INTO postings (
+  transaction, account, amount, currency, balance_after
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+`
+
+type InsertPostingParams struct {
+	Transaction  int64
+	Account      int64
+	Amount       string
+	Currency     string
+	BalanceAfter sql.NullString
+}
+
+func (q *Queries) InsertPosting(ctx context.Context, arg InsertPostingParams) error {
+	_, err := q.db.ExecContext(ctx, insertPosting,
+		arg.Transaction,
+		arg.Account,
+		arg.Amount,
+		arg.Currency,
+		arg.BalanceAfter,
+	)
+	return err
+}
+
+const insertQuote = `-- name: InsertQuote :one
+INSERT INTO quotes (
+  account, rate_id, source, target, rate, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, now() + $6::integer * interval '1 second'
+) RETURNING id, account, rate_id, source, target, rate, expires_at, inserted_at, transaction
+`
+
+type InsertQuoteParams struct {
+	Account int64
+	RateID  int64
+	Source  string
+	Target  string
+	Rate    string
+	Ttl     int32
+}
+
+func (q *Queries) InsertQuote(ctx context.Context, arg InsertQuoteParams) (Quote, error) {
+	row := q.db.QueryRowContext(ctx, insertQuote,
+		arg.Account,
+		arg.RateID,
+		arg.Source,
+		arg.Target,
+		arg.Rate,
+		arg.Ttl,
+	)
+	var i Quote
+	err := row.Scan(
+		&i.ID,
+		&i.Account,
+		&i.RateID,
+		&i.Source,
+		&i.Target,
+		&i.Rate,
+		&i.ExpiresAt,
+		&i.InsertedAt,
+		&i.Transaction,
+	)
+	return i, err
+}
+
+const insertRate = `-- name: InsertRate :one
+INSERT INTO rates (
+  source, target, rate, valid_from, valid_until
+) VALUES (
+  $1, $2, $3, COALESCE($4::timestamptz, now()), $5
+) RETURNING id, source, target, rate, valid_from, valid_until, inserted_at
+`
+
+type InsertRateParams struct {
+	Source     string
+	Target     string
+	Rate       string
+	ValidFrom  sql.NullTime
+	ValidUntil sql.NullTime
+}
+
+// valid_fromを省略すると、直ちに有効になります
+func (q *Queries) InsertRate(ctx context.Context, arg InsertRateParams) (Rate, error) {
+	row := q.db.QueryRowContext(ctx, insertRate,
+		arg.Source,
+		arg.Target,
+		arg.Rate,
+		arg.ValidFrom,
+		arg.ValidUntil,
+	)
+	var i Rate
+	err := row.Scan(
+		&i.ID,
+		&i.Source,
+		&i.Target,
+		&i.Rate,
+		&i.ValidFrom,
+		&i.ValidUntil,
+		&i.InsertedAt,
+	)
+	return i, err
+}
+
+const insertReconciliation = `-- name: InsertReconciliation :one
+INSERT INTO reconciliations (
+  mismatches
+) VALUES (
+  $1
+) RETURNING id
+`
+
+func (q *Queries) InsertReconciliation(ctx context.Context, mismatches json.RawMessage) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertReconciliation, mismatches)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const insertReversal = `-- name: InsertReversal :one
+INSERT INTO reversals (
+  amount, original, currency
+) VALUES (
+  $1, $2, $3
+) RETURNING id
+`
+
+type InsertReversalParams struct {
+	Amount   string
+	Original int64
+	Currency string
+}
+
+func (q *Queries) InsertReversal(ctx context.Context, arg InsertReversalParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertReversal, arg.Amount, arg.Original, arg.Currency)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const insertSchedule = `-- name: InsertSchedule :one
+INSERT INTO schedules (
+  account, recipient, currency, amount, memo, frequency, starts_at, ends_at, next_run_at
+) SELECT
+  $1, $2, $3, $4, $5, $6, COALESCE($7::timestamptz, now()), $8::timestamptz, COALESCE($7::timestamptz, now())
+WHERE $8::timestamptz IS NULL OR $8::timestamptz > COALESCE($7::timestamptz, now())
+RETURNING id, account, recipient, currency, amount, memo, frequency, status, starts_at, ends_at, next_run_at, inserted_at, updated_at
+`
+
+type InsertScheduleParams struct {
+	Account   int64
+	Recipient int64
+	Currency  string
+	Amount    string
+	Memo      sql.NullString
+	Frequency ScheduleFrequency
+	StartsAt  sql.NullTime
+	EndsAt    sql.NullTime
+}
+
+// 最初の回はstarts_atに実行します。starts_atを省略すると直ちに実行します
+// ends_atが最初の回より後でなければ作らずに行を返しません
+func (q *Queries) InsertSchedule(ctx context.Context, arg InsertScheduleParams) (Schedule, error) {
+	row := q.db.QueryRowContext(ctx, insertSchedule,
+		arg.Account,
+		arg.Recipient,
+		arg.Currency,
+		arg.Amount,
+		arg.Memo,
+		arg.Frequency,
+		arg.StartsAt,
+		arg.EndsAt,
+	)
+	var i Schedule
+	err := row.Scan(
+		&i.ID,
+		&i.Account,
+		&i.Recipient,
+		&i.Currency,
+		&i.Amount,
+		&i.Memo,
+		&i.Frequency,
+		&i.Status,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.NextRunAt,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const insertScheduleRun = `-- name: InsertScheduleRun :one
+INSERT INTO schedule_runs (
+  schedule, due_at, status, transaction, error
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING id, schedule, due_at, status, transaction, error, inserted_at
+`
+
+type InsertScheduleRunParams struct {
+	Schedule    int64
+	DueAt       time.Time
+	Status      ScheduleRunStatus
+	Transaction sql.NullInt64
+	Error       sql.NullString
+}
+
+func (q *Queries) InsertScheduleRun(ctx context.Context, arg InsertScheduleRunParams) (ScheduleRun, error) {
+	row := q.db.QueryRowContext(ctx, insertScheduleRun,
+		arg.Schedule,
+		arg.DueAt,
+		arg.Status,
+		arg.Transaction,
+		arg.Error,
+	)
+	var i ScheduleRun
+	err := row.Scan(
+		&i.ID,
+		&i.Schedule,
+		&i.DueAt,
+		&i.Status,
+		&i.Transaction,
+		&i.Error,
+		&i.InsertedAt,
+	)
+	return i, err
+}
+
+const insertSpend = `-- name: InsertSpend :one
+INSERT INTO spends (
+  amount, currency, memo, reference_type, reference_id
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING id
+`
+
+type InsertSpendParams struct {
+	Amount        string
+	Currency      string
+	Memo          sql.NullString
+	ReferenceType sql.NullString
+	ReferenceID   sql.NullString
+}
+
+func (q *Queries) InsertSpend(ctx context.Context, arg InsertSpendParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertSpend,
+		arg.Amount,
+		arg.Currency,
+		arg.Memo,
+		arg.ReferenceType,
+		arg.ReferenceID,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const insertTransaction = `-- name: InsertTransaction :one
+INSERT INTO transactions (
+  account, mint, spend, transfer, reversal, conversion, journal, fee, escrow_movement, metadata
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING id
+`
+
+type InsertTransactionParams struct {
+	Account        int64
+	Mint           sql.NullInt64
+	Spend          sql.NullInt64
+	Transfer       sql.NullInt64
+	Reversal       sql.NullInt64
+	Conversion     sql.NullInt64
+	Journal        sql.NullInt64
+	Fee            sql.NullInt64
+	EscrowMovement sql.NullInt64
+	Metadata       json.RawMessage
+}
+
+func (q *Queries) InsertTransaction(ctx context.Context, arg InsertTransactionParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertTransaction,
+		arg.Account,
+		arg.Mint,
+		arg.Spend,
+		arg.Transfer,
+		arg.Reversal,
+		arg.Conversion,
+		arg.Journal,
+		arg.Fee,
+		arg.EscrowMovement,
+		arg.Metadata,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const insertTransfer = `-- name: InsertTransfer :one
+INSERT INTO transfers (
+  recipient, amount, currency, memo, reference_type, reference_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id
+`
+
+type InsertTransferParams struct {
+	Recipient     int64
+	Amount        string
+	Currency      string
+	Memo          sql.NullString
+	ReferenceType sql.NullString
+	ReferenceID   sql.NullString
+}
+
+func (q *Queries) InsertTransfer(ctx context.Context, arg InsertTransferParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertTransfer,
+		arg.Recipient,
+		arg.Amount,
+		arg.Currency,
+		arg.Memo,
+		arg.ReferenceType,
+		arg.ReferenceID,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const lockAccount = `-- name: LockAccount :one
+SELECT id, inserted_at, updated_at, name, status, external_id, metadata FROM accounts WHERE id=$1 LIMIT 1 FOR UPDATE
+`
+
+// statusを変更するためにaccountをロックします
+func (q *Queries) LockAccount(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRowContext(ctx, lockAccount, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Status,
+		&i.ExternalID,
+		&i.Metadata,
+	)
+	return i, err
+}
+
+const lockBalance = `-- name: LockBalance :one
+SELECT balance FROM balances WHERE account=$1 AND currency=$2 LIMIT 1 FOR UPDATE
+`
+
+type LockBalanceParams struct {
+	Account  int64
+	Currency string
+}
+
+func (q *Queries) LockBalance(ctx context.Context, arg LockBalanceParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, lockBalance, arg.Account, arg.Currency)
+	var balance string
+	err := row.Scan(&balance)
+	return balance, err
+}
+
+const lockEscrow = `-- name: LockEscrow :one
+SELECT id, sender, recipient, currency, amount, memo, status, sender_confirmed_at, recipient_confirmed_at, expires_at, inserted_at, updated_at, expires_at <= now() AS expired FROM escrows WHERE id=$1 LIMIT 1 FOR UPDATE
+`
+
+type LockEscrowRow struct {
+	ID                   int64
+	Sender               int64
+	Recipient            int64
+	Currency             string
+	Amount               string
+	Memo                 sql.NullString
+	Status               EscrowStatus
+	SenderConfirmedAt    sql.NullTime
+	RecipientConfirmedAt sql.NullTime
+	ExpiresAt            time.Time
+	InsertedAt           time.Time
+	UpdatedAt            time.Time
+	Expired              bool
+}
+
+// 当事者かどうかは呼び出し側で確かめます
+func (q *Queries) LockEscrow(ctx context.Context, id int64) (LockEscrowRow, error) {
+	row := q.db.QueryRowContext(ctx, lockEscrow, id)
+	var i LockEscrowRow
+	err := row.Scan(
+		&i.ID,
+		&i.Sender,
+		&i.Recipient,
+		&i.Currency,
+		&i.Amount,
+		&i.Memo,
+		&i.Status,
+		&i.SenderConfirmedAt,
+		&i.RecipientConfirmedAt,
+		&i.ExpiresAt,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+		&i.Expired,
+	)
+	return i, err
+}
+
+const lockHold = `-- name: LockHold :one
+SELECT id, account, amount, status, expires_at, inserted_at, updated_at, transaction, currency, expires_at <= now() AS expired FROM holds WHERE id=$1 AND account=$2 LIMIT 1 FOR UPDATE
+`
+
+type LockHoldParams struct {
+	ID      int64
+	Account int64
+}
+
+type LockHoldRow struct {
+	ID          int64
+	Account     int64
+	Amount      string
+	Status      HoldStatus
+	ExpiresAt   time.Time
+	InsertedAt  time.Time
+	UpdatedAt   time.Time
+	Transaction sql.NullInt64
+	Currency    string
+	Expired     bool
+}
+
+func (q *Queries) LockHold(ctx context.Context, arg LockHoldParams) (LockHoldRow, error) {
+	row := q.db.QueryRowContext(ctx, lockHold, arg.ID, arg.Account)
+	var i LockHoldRow
+	err := row.Scan(
+		&i.ID,
+		&i.Account,
+		&i.Amount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+		&i.Transaction,
+		&i.Currency,
+		&i.Expired,
+	)
+	return i, err
+}
+
+const lockIdempotencyKey = `-- name: LockIdempotencyKey :exec
+SELECT pg_advisory_xact_lock(hashtextextended($1::text, $2::bigint))
+`
+
+type LockIdempotencyKeyParams struct {
+	Key     string
 	Account int64
-	Amount  string
 }
 
-func (q *Queries) DecrementBalance(ctx context.Context, arg DecrementBalanceParams) error {
-	_, err := q.db.ExecContext(ctx, decrementBalance, arg.Account, arg.Amount)
+func (q *Queries) LockIdempotencyKey(ctx context.Context, arg LockIdempotencyKeyParams) error {
+	_, err := q.db.ExecContext(ctx, lockIdempotencyKey, arg.Key, arg.Account)
 	return err
 }
 
-const getAccount = `-- name: GetAccount :one
-SELECT id, inserted_at, updated_at, name FROM accounts WHERE id=$1 LIMIT 1
+const lockPaymentRequest = `-- name: LockPaymentRequest :one
+SELECT id, requester, payer, currency, amount, memo, status, expires_at, transaction, inserted_at, updated_at, (status='pending' AND expires_at <= now())::boolean AS expired FROM payment_requests
+WHERE id=$1 AND payer=$2
+LIMIT 1 FOR UPDATE
 `
 
-func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
-	row := q.db.QueryRowContext(ctx, getAccount, id)
-	var i Account
+type LockPaymentRequestParams struct {
+	ID    int64
+	Payer int64
+}
+
+type LockPaymentRequestRow struct {
+	ID          int64
+	Requester   int64
+	Payer       int64
+	Currency    string
+	Amount      string
+	Memo        sql.NullString
+	Status      PaymentRequestStatus
+	ExpiresAt   time.Time
+	Transaction sql.NullInt64
+	InsertedAt  time.Time
+	UpdatedAt   time.Time
+	Expired     bool
+}
+
+// 支払いや拒否ができるpayerについてのみロックして取得します
+func (q *Queries) LockPaymentRequest(ctx context.Context, arg LockPaymentRequestParams) (LockPaymentRequestRow, error) {
+	row := q.db.QueryRowContext(ctx, lockPaymentRequest, arg.ID, arg.Payer)
+	var i LockPaymentRequestRow
 	err := row.Scan(
 		&i.ID,
+		&i.Requester,
+		&i.Payer,
+		&i.Currency,
+		&i.Amount,
+		&i.Memo,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.Transaction,
 		&i.InsertedAt,
 		&i.UpdatedAt,
-		&i.Name,
+		&i.Expired,
 	)
 	return i, err
 }
 
-const getBalance = `-- name: GetBalance :one
-SELECT balance FROM balances WHERE account=$1 LIMIT 1
+const lockQuote = `-- name: LockQuote :one
+SELECT id, account, rate_id, source, target, rate, expires_at, inserted_at, transaction, expires_at <= now() AS expired FROM quotes WHERE id=$1 AND account=$2 LIMIT 1 FOR UPDATE
 `
 
-func (q *Queries) GetBalance(ctx context.Context, account int64) (string, error) {
-	row := q.db.QueryRowContext(ctx, getBalance, account)
-	var balance string
-	err := row.Scan(&balance)
-	return balance, err
+type LockQuoteParams struct {
+	ID      int64
+	Account int64
 }
 
-const getTransactions = `-- name: GetTransactions :many
+type LockQuoteRow struct {
+	ID          int64
+	Account     int64
+	RateID      int64
+	Source      string
+	Target      string
+	Rate        string
+	ExpiresAt   time.Time
+	InsertedAt  time.Time
+	Transaction sql.NullInt64
+	Expired     bool
+}
+
+func (q *Queries) LockQuote(ctx context.Context, arg LockQuoteParams) (LockQuoteRow, error) {
+	row := q.db.QueryRowContext(ctx, lockQuote, arg.ID, arg.Account)
+	var i LockQuoteRow
+	err := row.Scan(
+		&i.ID,
+		&i.Account,
+		&i.RateID,
+		&i.Source,
+		&i.Target,
+		&i.Rate,
+		&i.ExpiresAt,
+		&i.InsertedAt,
+		&i.Transaction,
+		&i.Expired,
+	)
+	return i, err
+}
+
+const lockSchedule = `-- name: LockSchedule :one
+SELECT id, account, recipient, currency, amount, memo, frequency, status, starts_at, ends_at, next_run_at, inserted_at, updated_at FROM schedules WHERE id=$1 AND account=$2 LIMIT 1 FOR UPDATE
+`
+
+type LockScheduleParams struct {
+	ID      int64
+	Account int64
+}
+
+func (q *Queries) LockSchedule(ctx context.Context, arg LockScheduleParams) (Schedule, error) {
+	row := q.db.QueryRowContext(ctx, lockSchedule, arg.ID, arg.Account)
+	var i Schedule
+	err := row.Scan(
+		&i.ID,
+		&i.Account,
+		&i.Recipient,
+		&i.Currency,
+		&i.Amount,
+		&i.Memo,
+		&i.Frequency,
+		&i.Status,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.NextRunAt,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const lockTransaction = `-- name: LockTransaction :one
 SELECT
   transactions.id AS transaction_id,
   transactions.account AS account_id,
-  transactions.inserted_at AS inserted_at,
 
-  mints.id AS mint_id,
   mints.amount AS mint_amount,
-
-  spends.id AS spend_id,
+  mints.currency AS mint_currency,
   spends.amount AS spend_amount,
-
-  transfers.id AS transfer_id,
+  spends.currency AS spend_currency,
   transfers.amount AS transfer_amount,
-  transfers.recipient AS transfer_recipient
+  transfers.recipient AS transfer_recipient,
+  transfers.currency AS transfer_currency,
+  transactions.reversal AS reversal_id,
+  transactions.conversion AS conversion_id,
+  transactions.journal AS journal_id,
+  transactions.fee AS fee_id,
+  transactions.escrow_movement AS escrow_movement_id
 FROM transactions
 LEFT OUTER JOIN mints ON transactions.mint=mints.id
 LEFT OUTER JOIN spends ON transactions.spend=spends.id
 LEFT OUTER JOIN transfers ON transactions.transfer=transfers.id
-WHERE transactions.account=$1 OR transfers.recipient=$1 ORDER BY transactions.inserted_at ASC
+WHERE transactions.id=$1 LIMIT 1 FOR UPDATE OF transactions
 `
 
-type GetTransactionsRow struct {
+type LockTransactionRow struct {
 	TransactionID     int64
 	AccountID         int64
-	InsertedAt        time.Time
-	MintID            sql.NullInt64
 	MintAmount        sql.NullString
-	SpendID           sql.NullInt64
+	MintCurrency      sql.NullString
 	SpendAmount       sql.NullString
-	TransferID        sql.NullInt64
+	SpendCurrency     sql.NullString
 	TransferAmount    sql.NullString
 	TransferRecipient sql.NullInt64
+	TransferCurrency  sql.NullString
+	ReversalID        sql.NullInt64
+	ConversionID      sql.NullInt64
+	JournalID         sql.NullInt64
+	FeeID             sql.NullInt64
+	EscrowMovementID  sql.NullInt64
 }
 
-func (q *Queries) GetTransactions(ctx context.Context, account int64) ([]GetTransactionsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTransactions, account)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetTransactionsRow
-	for rows.Next() {
-		var i GetTransactionsRow
-		if err := rows.Scan(
-			&i.TransactionID,
-			&i.AccountID,
-			&i.InsertedAt,
-			&i.MintID,
-			&i.MintAmount,
-			&i.SpendID,
-			&i.SpendAmount,
-			&i.TransferID,
-			&i.TransferAmount,
-			&i.TransferRecipient,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+// 取り消しの対象になるtransactionをロックして取得します
+func (q *Queries) LockTransaction(ctx context.Context, id int64) (LockTransactionRow, error) {
+	row := q.db.QueryRowContext(ctx, lockTransaction, id)
+	var i LockTransactionRow
+	err := row.Scan(
+		&i.TransactionID,
+		&i.AccountID,
+		&i.MintAmount,
+		&i.MintCurrency,
+		&i.SpendAmount,
+		&i.SpendCurrency,
+		&i.TransferAmount,
+		&i.TransferRecipient,
+		&i.TransferCurrency,
+		&i.ReversalID,
+		&i.ConversionID,
+		&i.JournalID,
+		&i.FeeID,
+		&i.EscrowMovementID,
+	)
+	return i, err
 }
 
-const incrementBalance = `-- name: IncrementBalance :exec
-UPDATE balances SET balance = balance + $2 WHERE account=$1
+const setBalance = `-- name: SetBalance :exec
+UPDATE balances SET balance = $3 WHERE account=$1 AND currency=$2
 `
 
-type IncrementBalanceParams struct {
-	Account int64
-	Amount  string
+type SetBalanceParams struct {
+	Account  int64
+	Currency string
+	Balance  string
 }
 
-func (q *Queries) IncrementBalance(ctx context.Context, arg IncrementBalanceParams) error {
-	_, err := q.db.ExecContext(ctx, incrementBalance, arg.Account, arg.Amount)
+func (q *Queries) SetBalance(ctx context.Context, arg SetBalanceParams) error {
+	_, err := q.db.ExecContext(ctx, setBalance, arg.Account, arg.Currency, arg.Balance)
 	return err
 }
 
-const insertAccount = `-- name: InsertAccount :one
-INSERT INTO accounts (
-  name
-) VALUES (
-  $1
-) RETURNING id
+const shareAccountStatus = `-- name: ShareAccountStatus :one
+SELECT status FROM accounts WHERE id=$1 LIMIT 1 FOR SHARE
 `
 
-func (q *Queries) InsertAccount(ctx context.Context, name sql.NullString) (int64, error) {
-	row := q.db.QueryRowContext(ctx, insertAccount, name)
-	var id int64
-	err := row.Scan(&id)
-	return id, err
+// 取引の間statusが変わらないよう、共有ロックを取ってstatusを取得します
+func (q *Queries) ShareAccountStatus(ctx context.Context, id int64) (AccountStatus, error) {
+	row := q.db.QueryRowContext(ctx, shareAccountStatus, id)
+	var status AccountStatus
+	err := row.Scan(&status)
+	return status, err
 }
 
-const insertBalance = `-- name: InsertBalance :exec
-INSERT INTO balances (
-  account, balance
-) VALUES (
-  $1, 0
-)
+const updateAccountName = `-- name: UpdateAccountName :one
+UPDATE accounts SET name=$2, updated_at=timezone('utc':: text, now()) WHERE id=$1 RETURNING id, inserted_at, updated_at, name, status, external_id, metadata
+`
+
+type UpdateAccountNameParams struct {
+	ID   int64
+	Name sql.NullString
+}
+
+func (q *Queries) UpdateAccountName(ctx context.Context, arg UpdateAccountNameParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountName, arg.ID, arg.Name)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Status,
+		&i.ExternalID,
+		&i.Metadata,
+	)
+	return i, err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :exec
+UPDATE accounts SET status=$2, updated_at=timezone('utc':: text, now()) WHERE id=$1
 `
 
-func (q *Queries) InsertBalance(ctx context.Context, account int64) error {
-	_, err := q.db.ExecContext(ctx, insertBalance, account)
+type UpdateAccountStatusParams struct {
+	ID     int64
+	Status AccountStatus
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateAccountStatus, arg.ID, arg.Status)
 	return err
 }
 
-const insertMint = `-- name: InsertMint :one
-INSERT INTO mints (
-  amount
-) VALUES (
-  $1
-) RETURNING id
+const updateEscrowStatus = `-- name: UpdateEscrowStatus :one
+UPDATE escrows SET status=$2, updated_at=timezone('utc':: text, now()) WHERE id=$1 RETURNING id, sender, recipient, currency, amount, memo, status, sender_confirmed_at, recipient_confirmed_at, expires_at, inserted_at, updated_at
 `
 
-func (q *Queries) InsertMint(ctx context.Context, amount string) (int64, error) {
-	row := q.db.QueryRowContext(ctx, insertMint, amount)
-	var id int64
-	err := row.Scan(&id)
-	return id, err
+type UpdateEscrowStatusParams struct {
+	ID     int64
+	Status EscrowStatus
 }
 
-const insertSpend = `-- name: InsertSpend :one
-INSERT INTO spends (
-  amount
+func (q *Queries) UpdateEscrowStatus(ctx context.Context, arg UpdateEscrowStatusParams) (Escrow, error) {
+	row := q.db.QueryRowContext(ctx, updateEscrowStatus, arg.ID, arg.Status)
+	var i Escrow
+	err := row.Scan(
+		&i.ID,
+		&i.Sender,
+		&i.Recipient,
+		&i.Currency,
+		&i.Amount,
+		&i.Memo,
+		&i.Status,
+		&i.SenderConfirmedAt,
+		&i.RecipientConfirmedAt,
+		&i.ExpiresAt,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateHoldStatus = `-- name: UpdateHoldStatus :exec
+UPDATE holds SET status=$2, transaction=$3, updated_at=timezone('utc':: text, now()) WHERE id=$1
+`
+
+type UpdateHoldStatusParams struct {
+	ID          int64
+	Status      HoldStatus
+	Transaction sql.NullInt64
+}
+
+func (q *Queries) UpdateHoldStatus(ctx context.Context, arg UpdateHoldStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateHoldStatus, arg.ID, arg.Status, arg.Transaction)
+	return err
+}
+
+const updatePaymentRequestStatus = `-- name: UpdatePaymentRequestStatus :one
+UPDATE payment_requests SET status=$2, transaction=$3, updated_at=timezone('utc':: text, now()) WHERE id=$1 RETURNING id, requester, payer, currency, amount, memo, status, expires_at, transaction, inserted_at, updated_at
+`
+
+type UpdatePaymentRequestStatusParams struct {
+	ID          int64
+	Status      PaymentRequestStatus
+	Transaction sql.NullInt64
+}
+
+func (q *Queries) UpdatePaymentRequestStatus(ctx context.Context, arg UpdatePaymentRequestStatusParams) (PaymentRequest, error) {
+	row := q.db.QueryRowContext(ctx, updatePaymentRequestStatus, arg.ID, arg.Status, arg.Transaction)
+	var i PaymentRequest
+	err := row.Scan(
+		&i.ID,
+		&i.Requester,
+		&i.Payer,
+		&i.Currency,
+		&i.Amount,
+		&i.Memo,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.Transaction,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateQuoteTransaction = `-- name: UpdateQuoteTransaction :exec
+UPDATE quotes SET transaction=$2 WHERE id=$1
+`
+
+type UpdateQuoteTransactionParams struct {
+	ID          int64
+	Transaction sql.NullInt64
+}
+
+func (q *Queries) UpdateQuoteTransaction(ctx context.Context, arg UpdateQuoteTransactionParams) error {
+	_, err := q.db.ExecContext(ctx, updateQuoteTransaction, arg.ID, arg.Transaction)
+	return err
+}
+
+const updateSchedule = `-- name: UpdateSchedule :one
+UPDATE schedules SET
+  amount=$2,
+  memo=$3,
+  ends_at=$4,
+  status=$5,
+  next_run_at=$6,
+  updated_at=timezone('utc':: text, now())
+WHERE id=$1
+RETURNING id, account, recipient, currency, amount, memo, frequency, status, starts_at, ends_at, next_run_at, inserted_at, updated_at
+`
+
+type UpdateScheduleParams struct {
+	ID        int64
+	Amount    string
+	Memo      sql.NullString
+	EndsAt    sql.NullTime
+	Status    ScheduleStatus
+	NextRunAt time.Time
+}
+
+func (q *Queries) UpdateSchedule(ctx context.Context, arg UpdateScheduleParams) (Schedule, error) {
+	row := q.db.QueryRowContext(ctx, updateSchedule,
+		arg.ID,
+		arg.Amount,
+		arg.Memo,
+		arg.EndsAt,
+		arg.Status,
+		arg.NextRunAt,
+	)
+	var i Schedule
+	err := row.Scan(
+		&i.ID,
+		&i.Account,
+		&i.Recipient,
+		&i.Currency,
+		&i.Amount,
+		&i.Memo,
+		&i.Frequency,
+		&i.Status,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.NextRunAt,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertCreditLimit = `-- name: UpsertCreditLimit :one
+INSERT INTO credit_limits (
+  account, currency, credit_limit
 ) VALUES (
-  $1
-) RETURNING id
+  $1, $2, $3
+) ON CONFLICT (account, currency) DO UPDATE SET
+  credit_limit=EXCLUDED.credit_limit,
+  updated_at=timezone('utc':: text, now())
+RETURNING account, currency, credit_limit, inserted_at, updated_at
 `
 
-func (q *Queries) InsertSpend(ctx context.Context, amount string) (int64, error) {
-	row := q.db.QueryRowContext(ctx, insertSpend, amount)
-	var id int64
-	err := row.Scan(&id)
-	return id, err
+type UpsertCreditLimitParams struct {
+	Account     int64
+	Currency    string
+	CreditLimit string
 }
 
-const insertTransaction = `-- name: InsertTransaction :one
-INSERT INTO transactions (
-  account, mint, spend, transfer
+// accountのcurrencyの与信枠があれば置き換えます
+func (q *Queries) UpsertCreditLimit(ctx context.Context, arg UpsertCreditLimitParams) (CreditLimit, error) {
+	row := q.db.QueryRowContext(ctx, upsertCreditLimit, arg.Account, arg.Currency, arg.CreditLimit)
+	var i CreditLimit
+	err := row.Scan(
+		&i.Account,
+		&i.Currency,
+		&i.CreditLimit,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertFeeSchedule = `-- name: UpsertFeeSchedule :one
+INSERT INTO fee_schedules (
+  operation, currency, flat, percentage, min, max, account
 ) VALUES (
-  $1, $2, $3, $4
-) RETURNING id
+  $1, $2, $3, $4, $5, $6, $7
+) ON CONFLICT (operation, currency) DO UPDATE SET
+  flat=EXCLUDED.flat,
+  percentage=EXCLUDED.percentage,
+  min=EXCLUDED.min,
+  max=EXCLUDED.max,
+  account=EXCLUDED.account,
+  updated_at=timezone('utc':: text, now())
+RETURNING id, operation, currency, flat, percentage, min, max, account, inserted_at, updated_at
 `
 
-type InsertTransactionParams struct {
-	Account  int64
-	Mint     sql.NullInt64
-	Spend    sql.NullInt64
-	Transfer sql.NullInt64
+type UpsertFeeScheduleParams struct {
+	Operation  FeeOperation
+	Currency   string
+	Flat       string
+	Percentage string
+	Min        sql.NullString
+	Max        sql.NullString
+	Account    int64
 }
 
-func (q *Queries) InsertTransaction(ctx context.Context, arg InsertTransactionParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, insertTransaction,
+// operationとcurrencyの手数料の定めがあれば置き換えます
+func (q *Queries) UpsertFeeSchedule(ctx context.Context, arg UpsertFeeScheduleParams) (FeeSchedule, error) {
+	row := q.db.QueryRowContext(ctx, upsertFeeSchedule,
+		arg.Operation,
+		arg.Currency,
+		arg.Flat,
+		arg.Percentage,
+		arg.Min,
+		arg.Max,
 		arg.Account,
-		arg.Mint,
-		arg.Spend,
-		arg.Transfer,
 	)
-	var id int64
-	err := row.Scan(&id)
-	return id, err
+	var i FeeSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.Operation,
+		&i.Currency,
+		&i.Flat,
+		&i.Percentage,
+		&i.Min,
+		&i.Max,
+		&i.Account,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const insertTransfer = `-- name: InsertTransfer :one
-INSERT INTO transfers (
-  recipient, amount
+const upsertLimit = `-- name: UpsertLimit :one
+INSERT INTO limits (
+  account, operation, currency, kind, value
 ) VALUES (
-  $1, $2
-) RETURNING id
+  $1, $2, $3, $4, $5
+) ON CONFLICT ((COALESCE(account, 0)), operation, currency, kind) DO UPDATE SET
+  value=EXCLUDED.value,
+  updated_at=timezone('utc':: text, now())
+RETURNING id, account, operation, currency, kind, value, inserted_at, updated_at
 `
 
-type InsertTransferParams struct {
-	Recipient int64
-	Amount    string
+type UpsertLimitParams struct {
+	Account   sql.NullInt64
+	Operation LimitOperation
+	Currency  string
+	Kind      LimitKind
+	Value     string
 }
 
-func (q *Queries) InsertTransfer(ctx context.Context, arg InsertTransferParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, insertTransfer, arg.Recipient, arg.Amount)
-	var id int64
-	err := row.Scan(&id)
-	return id, err
+// accountとoperation, currency, kindの制限があれば置き換えます
+func (q *Queries) UpsertLimit(ctx context.Context, arg UpsertLimitParams) (Limit, error) {
+	row := q.db.QueryRowContext(ctx, upsertLimit,
+		arg.Account,
+		arg.Operation,
+		arg.Currency,
+		arg.Kind,
+		arg.Value,
+	)
+	var i Limit
+	err := row.Scan(
+		&i.ID,
+		&i.Account,
+		&i.Operation,
+		&i.Currency,
+		&i.Kind,
+		&i.Value,
+		&i.InsertedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
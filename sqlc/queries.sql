@@ -1,14 +1,91 @@
 -- name: GetAccount :one
 SELECT * FROM accounts WHERE id=$1 LIMIT 1;
 
+-- name: GetAccounts :many
+-- 利用者のaccountsをidの順に、row_limit件まで取得します。システム勘定(負のid)は含めません
+-- after_idを指定すると、そのidより後から取得します
+-- metadataは、指定したkeyとvalueを全て含むものに絞り込みます
+SELECT * FROM accounts
+WHERE
+  id > 0
+  AND (sqlc.narg(after_id)::bigint IS NULL OR id > sqlc.narg(after_id))
+  AND (sqlc.narg(name_prefix)::text IS NULL OR starts_with(name, sqlc.narg(name_prefix)))
+  AND (sqlc.narg(since)::timestamptz IS NULL OR inserted_at >= sqlc.narg(since))
+  AND (sqlc.narg(until)::timestamptz IS NULL OR inserted_at < sqlc.narg(until))
+  AND (sqlc.narg(external_id)::text IS NULL OR external_id = sqlc.narg(external_id))
+  AND (sqlc.narg(metadata)::text IS NULL OR metadata @> sqlc.narg(metadata)::jsonb)
+ORDER BY id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: UpdateAccountName :one
+UPDATE accounts SET name=$2, updated_at=timezone('utc':: text, now()) WHERE id=$1 RETURNING *;
+
+-- name: LockAccount :one
+-- statusを変更するためにaccountをロックします
+SELECT * FROM accounts WHERE id=$1 LIMIT 1 FOR UPDATE;
+
+-- name: ShareAccountStatus :one
+-- 取引の間statusが変わらないよう、共有ロックを取ってstatusを取得します
+SELECT status FROM accounts WHERE id=$1 LIMIT 1 FOR SHARE;
+
+-- name: UpdateAccountStatus :exec
+UPDATE accounts SET status=$2, updated_at=timezone('utc':: text, now()) WHERE id=$1;
+
+-- name: InsertAccountStatusChange :one
+INSERT INTO account_status_changes (
+  account, previous, status, reason
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetAccountStatusChanges :many
+SELECT * FROM account_status_changes WHERE account=$1 ORDER BY inserted_at ASC, id ASC;
+
 -- name: GetBalance :one
-SELECT balance FROM balances WHERE account=$1 LIMIT 1;
+SELECT balance FROM balances WHERE account=$1 AND currency=$2 LIMIT 1;
+
+-- name: GetBalances :many
+SELECT currency, balance FROM balances WHERE account=$1 ORDER BY currency ASC;
+
+-- name: GetPostedBalance :one
+SELECT COALESCE(SUM(amount), 0)::DECIMAL AS balance FROM postings WHERE account=$1 AND currency=$2;
+
+-- name: LockBalance :one
+SELECT balance FROM balances WHERE account=$1 AND currency=$2 LIMIT 1 FOR UPDATE;
+
+-- name: GetCurrency :one
+SELECT * FROM currencies WHERE code=$1 LIMIT 1;
+
+-- name: GetCurrencies :many
+SELECT * FROM currencies ORDER BY code ASC;
+
+-- name: InsertCurrency :one
+INSERT INTO currencies (
+  code, name, scale
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
 
 -- name: GetTransactions :many
+-- accountのpostingsを持つtransactionsをcurrency毎に1行として、(inserted_at, id, currency)の順にrow_limit件まで取得します
+-- accountが当事者のescrowsの資金の動きは、accountの残高が動かなくても増減0の行として含めます
+-- conversionsのように複数のcurrencyを動かすtransactionは、それぞれのcurrencyの行になります
+-- after_inserted_at, after_id, after_currencyを指定すると、その位置より後から取得します
+-- direction, min_amount, max_amountは、accountから見た増減(postingsの合計)について判定し、増減0の行はdirectionのどちらにも含めません
+-- metadataは、指定したkeyとvalueを全て含むtransactionsに絞り込みます
+-- memoは、大文字と小文字を区別せずにその文字列を含むものに絞り込みます。%と_はエスケープしてください
+-- balanceは絞り込みやページ送りに関わらず、postingsに記録された取引直後のaccountの残高です
+-- entriesはカーソルより前の履歴を集計しないよう、after_inserted_at, after_idで先に絞り込みます
+-- counterpartyはaccount以外でpostingsを持つ利用者のaccountで、同じcurrencyのものを優先し、システム勘定(負のid)は含めません
+-- そのようなaccountがなければ、escrowsのもう一方の当事者です
 SELECT
   transactions.id AS transaction_id,
   transactions.account AS account_id,
   transactions.inserted_at AS inserted_at,
+  transactions.metadata AS metadata,
+  COALESCE(mints.memo, spends.memo, transfers.memo, journals.memo, escrows.memo) AS memo,
+  COALESCE(mints.reference_type, spends.reference_type, transfers.reference_type) AS reference_type,
+  COALESCE(mints.reference_id, spends.reference_id, transfers.reference_id) AS reference_id,
 
   mints.id AS mint_id,
   mints.amount AS mint_amount,
@@ -18,58 +95,580 @@ SELECT
 
   transfers.id AS transfer_id,
   transfers.amount AS transfer_amount,
-  transfers.recipient AS transfer_recipient
+  transfers.recipient AS transfer_recipient,
+
+  reversals.id AS reversal_id,
+  reversals.amount AS reversal_amount,
+  reversals.original AS reversal_original,
+
+  conversions.id AS conversion_id,
+  conversions.amount AS conversion_amount,
+  conversions.source AS conversion_source,
+  conversions.converted_amount AS conversion_converted_amount,
+  conversions.target AS conversion_target,
+  conversions.rate AS conversion_rate,
+  conversions.recipient AS conversion_recipient,
+
+  journals.id AS journal_id,
+
+  fees.id AS fee_id,
+  fees.amount AS fee_amount,
+  fees.original AS fee_original,
+  fees.recipient AS fee_recipient,
+
+  escrow_movements.id AS escrow_movement_id,
+  escrow_movements.action AS escrow_action,
+  escrows.id AS escrow_id,
+  escrows.amount AS escrow_amount,
+  escrows.sender AS escrow_sender,
+  escrows.recipient AS escrow_recipient,
+
+  entries.currency AS currency,
+  entries.delta::DECIMAL AS delta,
+  entries.balance::DECIMAL AS balance,
+  counterparties.id AS counterparty
+FROM transactions
+JOIN (
+  SELECT
+    postings.transaction,
+    postings.currency,
+    SUM(postings.amount) AS delta,
+    (array_agg(postings.balance_after ORDER BY postings.id DESC))[1] AS balance
+  FROM postings
+  JOIN transactions ON postings.transaction=transactions.id
+  WHERE postings.account=sqlc.arg(account)
+    AND (sqlc.narg(after_inserted_at)::timestamptz IS NULL OR (transactions.inserted_at, transactions.id) >= (sqlc.narg(after_inserted_at), sqlc.narg(after_id)::bigint))
+  GROUP BY postings.transaction, postings.currency
+  UNION ALL
+  SELECT
+    transactions.id,
+    escrows.currency,
+    0,
+    COALESCE((
+      SELECT earlier.balance_after FROM postings AS earlier
+      JOIN transactions AS earlier_transactions ON earlier.transaction=earlier_transactions.id
+      WHERE earlier.account=sqlc.arg(account) AND earlier.currency=escrows.currency
+        AND (earlier_transactions.inserted_at, earlier_transactions.id) < (transactions.inserted_at, transactions.id)
+      ORDER BY earlier_transactions.inserted_at DESC, earlier_transactions.id DESC, earlier.id DESC
+      LIMIT 1
+    ), 0)
+  FROM transactions
+  JOIN escrow_movements ON transactions.escrow_movement=escrow_movements.id
+  JOIN escrows ON escrow_movements.escrow=escrows.id
+  WHERE (escrows.sender=sqlc.arg(account) OR escrows.recipient=sqlc.arg(account))
+    AND NOT EXISTS (SELECT 1 FROM postings WHERE postings.transaction=transactions.id AND postings.account=sqlc.arg(account))
+    AND (sqlc.narg(after_inserted_at)::timestamptz IS NULL OR (transactions.inserted_at, transactions.id) >= (sqlc.narg(after_inserted_at), sqlc.narg(after_id)::bigint))
+) AS entries ON transactions.id=entries.transaction
+LEFT OUTER JOIN mints ON transactions.mint=mints.id
+LEFT OUTER JOIN spends ON transactions.spend=spends.id
+LEFT OUTER JOIN transfers ON transactions.transfer=transfers.id
+LEFT OUTER JOIN reversals ON transactions.reversal=reversals.id
+LEFT OUTER JOIN conversions ON transactions.conversion=conversions.id
+LEFT OUTER JOIN journals ON transactions.journal=journals.id
+LEFT OUTER JOIN fees ON transactions.fee=fees.id
+LEFT OUTER JOIN escrow_movements ON transactions.escrow_movement=escrow_movements.id
+LEFT OUTER JOIN escrows ON escrow_movements.escrow=escrows.id
+LEFT OUTER JOIN accounts AS counterparties ON counterparties.id=COALESCE((
+  SELECT others.account FROM postings AS others
+  WHERE others.transaction=transactions.id AND others.account<>sqlc.arg(account) AND others.account >= 0
+  ORDER BY others.currency=entries.currency DESC
+  LIMIT 1
+), CASE sqlc.arg(account) WHEN escrows.sender THEN escrows.recipient WHEN escrows.recipient THEN escrows.sender END)
+WHERE
+  (sqlc.narg(after_inserted_at)::timestamptz IS NULL OR (transactions.inserted_at, transactions.id, entries.currency) > (sqlc.narg(after_inserted_at), sqlc.narg(after_id)::bigint, sqlc.narg(after_currency)::text))
+  AND (sqlc.narg(type)::text IS NULL OR sqlc.narg(type) = CASE
+    WHEN transactions.mint IS NOT NULL THEN 'mint'
+    WHEN transactions.spend IS NOT NULL THEN 'spend'
+    WHEN transactions.transfer IS NOT NULL THEN 'transfer'
+    WHEN transactions.reversal IS NOT NULL THEN 'reversal'
+    WHEN transactions.conversion IS NOT NULL THEN 'conversion'
+    WHEN transactions.journal IS NOT NULL THEN 'journal'
+    WHEN transactions.fee IS NOT NULL THEN 'fee'
+    WHEN transactions.escrow_movement IS NOT NULL THEN 'escrow'
+  END)
+  AND (sqlc.narg(direction)::text IS NULL OR (sqlc.narg(direction) = 'incoming' AND entries.delta > 0) OR (sqlc.narg(direction) = 'outgoing' AND entries.delta < 0))
+  AND (sqlc.narg(counterparty)::bigint IS NULL OR counterparties.id=sqlc.narg(counterparty) OR EXISTS (
+    SELECT 1 FROM postings AS others
+    WHERE others.transaction=transactions.id AND others.account=sqlc.narg(counterparty) AND others.account<>sqlc.arg(account)
+  ))
+  AND (sqlc.narg(min_amount)::decimal IS NULL OR abs(entries.delta) >= sqlc.narg(min_amount))
+  AND (sqlc.narg(max_amount)::decimal IS NULL OR abs(entries.delta) <= sqlc.narg(max_amount))
+  AND (sqlc.narg(since)::timestamptz IS NULL OR transactions.inserted_at >= sqlc.narg(since))
+  AND (sqlc.narg(until)::timestamptz IS NULL OR transactions.inserted_at < sqlc.narg(until))
+  AND (sqlc.narg(currency)::text IS NULL OR entries.currency = sqlc.narg(currency))
+  AND (sqlc.narg(metadata)::text IS NULL OR transactions.metadata @> sqlc.narg(metadata)::jsonb)
+  AND (sqlc.narg(memo)::text IS NULL OR COALESCE(mints.memo, spends.memo, transfers.memo, journals.memo, escrows.memo) ILIKE '%' || sqlc.narg(memo) || '%')
+ORDER BY transactions.inserted_at ASC, transactions.id ASC, entries.currency ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: LockTransaction :one
+-- 取り消しの対象になるtransactionをロックして取得します
+SELECT
+  transactions.id AS transaction_id,
+  transactions.account AS account_id,
+
+  mints.amount AS mint_amount,
+  mints.currency AS mint_currency,
+  spends.amount AS spend_amount,
+  spends.currency AS spend_currency,
+  transfers.amount AS transfer_amount,
+  transfers.recipient AS transfer_recipient,
+  transfers.currency AS transfer_currency,
+  transactions.reversal AS reversal_id,
+  transactions.conversion AS conversion_id,
+  transactions.journal AS journal_id,
+  transactions.fee AS fee_id,
+  transactions.escrow_movement AS escrow_movement_id
 FROM transactions
 LEFT OUTER JOIN mints ON transactions.mint=mints.id
 LEFT OUTER JOIN spends ON transactions.spend=spends.id
 LEFT OUTER JOIN transfers ON transactions.transfer=transfers.id
-WHERE transactions.account=$1 OR transfers.recipient=$1 ORDER BY transactions.inserted_at ASC;
+WHERE transactions.id=$1 LIMIT 1 FOR UPDATE OF transactions;
+
+-- name: GetReversedAmount :one
+SELECT COALESCE(SUM(amount), 0)::DECIMAL AS amount FROM reversals WHERE original=$1;
 
 -- name: InsertAccount :one
 INSERT INTO accounts (
-  name
+  name, external_id, metadata
 ) VALUES (
-  $1
+  $1, $2, $3
 ) RETURNING id;
 
 -- name: InsertBalance :exec
+-- 既に行があれば何もしません
 INSERT INTO balances (
-  account, balance
+  account, currency, balance
 ) VALUES (
-  $1, 0
-);
+  $1, $2, 0
+) ON CONFLICT (account, currency) DO NOTHING;
 
 -- name: InsertMint :one
 INSERT INTO mints (
-  amount
+  amount, currency, memo, reference_type, reference_id
 ) VALUES (
-  $1
+  $1, $2, $3, $4, $5
 ) RETURNING id;
 
 -- name: InsertSpend :one
 INSERT INTO spends (
-  amount
+  amount, currency, memo, reference_type, reference_id
 ) VALUES (
-  $1
+  $1, $2, $3, $4, $5
 ) RETURNING id;
 
 -- name: InsertTransfer :one
 INSERT INTO transfers (
-  recipient, amount
+  recipient, amount, currency, memo, reference_type, reference_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3, $4, $5, $6
+) RETURNING id;
+
+-- name: InsertReversal :one
+INSERT INTO reversals (
+  amount, original, currency
+) VALUES (
+  $1, $2, $3
+) RETURNING id;
+
+-- name: InsertConversion :one
+INSERT INTO conversions (
+  amount, source, converted_amount, target, rate, rate_id, recipient
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
 ) RETURNING id;
 
 -- name: InsertTransaction :one
 INSERT INTO transactions (
-  account, mint, spend, transfer
+  account, mint, spend, transfer, reversal, conversion, journal, fee, escrow_movement, metadata
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING id;
+
+-- name: GetFeeSchedule :one
+SELECT * FROM fee_schedules WHERE operation=$1 AND currency=$2 LIMIT 1;
+
+-- name: GetFeeSchedules :many
+SELECT * FROM fee_schedules ORDER BY operation ASC, currency ASC;
+
+-- name: UpsertFeeSchedule :one
+-- operationとcurrencyの手数料の定めがあれば置き換えます
+INSERT INTO fee_schedules (
+  operation, currency, flat, percentage, min, max, account
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+) ON CONFLICT (operation, currency) DO UPDATE SET
+  flat=EXCLUDED.flat,
+  percentage=EXCLUDED.percentage,
+  min=EXCLUDED.min,
+  max=EXCLUDED.max,
+  account=EXCLUDED.account,
+  updated_at=timezone('utc':: text, now())
+RETURNING *;
+
+-- name: DeleteFeeSchedule :execrows
+DELETE FROM fee_schedules WHERE operation=$1 AND currency=$2;
+
+-- name: InsertFee :one
+INSERT INTO fees (
+  amount, currency, original, recipient, schedule
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING id;
+
+-- name: InsertJournal :one
+INSERT INTO journals (
+  memo
+) VALUES (
+  $1
+) RETURNING id;
+
+-- name: InsertJournalLeg :exec
+INSERT INTO journal_legs (
+  journal, account, currency, amount
+) VALUES (
+  $1, $2, $3, $4
+);
+
+-- name: InsertPosting :exec
+INSERT INTO postings (
+  transaction, account, amount, currency, balance_after
+) VALUES (
+  $1, $2, $3, $4, $5
+);
+
+-- name: SetBalance :exec
+UPDATE balances SET balance = sqlc.arg(balance) WHERE account=$1 AND currency=$2;
+
+-- name: InsertReconciliation :one
+INSERT INTO reconciliations (
+  mismatches
+) VALUES (
+  $1
+) RETURNING id;
+
+-- name: IncrementBalance :one
+-- 初めて受け取るcurrencyであれば行を作ります。増やした後の残高を返します
+INSERT INTO balances (
+  account, currency, balance
+) VALUES (
+  $1, $2, sqlc.arg(amount)
+) ON CONFLICT (account, currency) DO UPDATE SET balance = balances.balance + EXCLUDED.balance
+RETURNING balance;
+
+-- name: DecrementBalance :one
+-- 減らした後の残高を返します
+UPDATE balances SET balance = balance - sqlc.arg(amount) WHERE account=$1 AND currency=$2
+RETURNING balance;
+
+
+-- name: LockIdempotencyKey :exec
+SELECT pg_advisory_xact_lock(hashtextextended(sqlc.arg(key)::text, sqlc.arg(account)::bigint));
+
+-- name: GetExpectedBalances :many
+-- mints, spends, transfers, reversals, conversions, journal_legs, fees, escrowsの履歴から導出した残高と、balancesに記録された残高、postingsの合計をaccountとcurrency毎に並べます
+SELECT
+  balances.account,
+  balances.currency,
+  balances.balance,
+  COALESCE(history.balance, 0)::DECIMAL AS expected,
+  COALESCE(posted.balance, 0)::DECIMAL AS posted
+FROM balances
+LEFT OUTER JOIN (
+  SELECT entries.account, entries.currency, SUM(entries.amount) AS balance FROM (
+    SELECT transactions.account, mints.currency, mints.amount FROM transactions JOIN mints ON transactions.mint=mints.id
+    UNION ALL
+    SELECT transactions.account, spends.currency, -spends.amount FROM transactions JOIN spends ON transactions.spend=spends.id
+    UNION ALL
+    SELECT transactions.account, transfers.currency, -transfers.amount FROM transactions JOIN transfers ON transactions.transfer=transfers.id
+    UNION ALL
+    SELECT transfers.recipient, transfers.currency, transfers.amount FROM transactions JOIN transfers ON transactions.transfer=transfers.id
+    UNION ALL
+    SELECT originals.account, reversals.currency, CASE WHEN originals.mint IS NULL THEN reversals.amount ELSE -reversals.amount END
+      FROM reversals JOIN transactions AS originals ON reversals.original=originals.id
+    UNION ALL
+    SELECT transfers.recipient, reversals.currency, -reversals.amount
+      FROM reversals JOIN transactions AS originals ON reversals.original=originals.id JOIN transfers ON originals.transfer=transfers.id
+    UNION ALL
+    SELECT transactions.account, conversions.source, -conversions.amount FROM transactions JOIN conversions ON transactions.conversion=conversions.id
+    UNION ALL
+    SELECT conversions.recipient, conversions.target, conversions.converted_amount FROM transactions JOIN conversions ON transactions.conversion=conversions.id
+    UNION ALL
+    SELECT journal_legs.account, journal_legs.currency, journal_legs.amount FROM transactions JOIN journal_legs ON transactions.journal=journal_legs.journal
+    UNION ALL
+    SELECT transactions.account, fees.currency, -fees.amount FROM transactions JOIN fees ON transactions.fee=fees.id
+    UNION ALL
+    SELECT fees.recipient, fees.currency, fees.amount FROM transactions JOIN fees ON transactions.fee=fees.id
+    UNION ALL
+    SELECT CASE WHEN escrow_movements.action='release' THEN escrows.recipient ELSE escrows.sender END, escrows.currency, CASE WHEN escrow_movements.action='deposit' THEN -escrows.amount ELSE escrows.amount END
+      FROM transactions JOIN escrow_movements ON transactions.escrow_movement=escrow_movements.id JOIN escrows ON escrow_movements.escrow=escrows.id
+  ) AS entries GROUP BY entries.account, entries.currency
+) AS history ON balances.account=history.account AND balances.currency=history.currency
+LEFT OUTER JOIN (
+  SELECT postings.account, postings.currency, SUM(postings.amount) AS balance FROM postings GROUP BY postings.account, postings.currency
+) AS posted ON balances.account=posted.account AND balances.currency=posted.currency
+ORDER BY balances.account ASC, balances.currency ASC;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys WHERE account=$1 AND key=$2 LIMIT 1;
+
+-- name: InsertIdempotencyKey :exec
+INSERT INTO idempotency_keys (
+  account, key, request_hash, transaction
 ) VALUES (
   $1, $2, $3, $4
+);
+
+-- name: GetHeldAmount :one
+SELECT COALESCE(SUM(amount), 0)::DECIMAL AS amount FROM holds WHERE account=$1 AND currency=$2 AND status='active' AND expires_at > now();
+
+-- name: InsertHold :one
+INSERT INTO holds (
+  account, amount, currency, expires_at
+) VALUES (
+  $1, $2, $3, now() + sqlc.arg(ttl)::integer * interval '1 second'
+) RETURNING *;
+
+-- name: LockHold :one
+SELECT *, expires_at <= now() AS expired FROM holds WHERE id=$1 AND account=$2 LIMIT 1 FOR UPDATE;
+
+-- name: UpdateHoldStatus :exec
+UPDATE holds SET status=$2, transaction=$3, updated_at=timezone('utc':: text, now()) WHERE id=$1;
+
+-- name: InsertRate :one
+-- valid_fromを省略すると、直ちに有効になります
+INSERT INTO rates (
+  source, target, rate, valid_from, valid_until
+) VALUES (
+  $1, $2, $3, COALESCE(sqlc.narg(valid_from)::timestamptz, now()), sqlc.narg(valid_until)
+) RETURNING *;
+
+-- name: GetRates :many
+SELECT * FROM rates
+WHERE
+  (sqlc.narg(source)::text IS NULL OR source = sqlc.narg(source))
+  AND (sqlc.narg(target)::text IS NULL OR target = sqlc.narg(target))
+ORDER BY source ASC, target ASC, valid_from DESC, id DESC;
+
+-- name: GetCurrentRate :one
+-- sourceからtargetへの、今有効なレートのうちvalid_fromの最も新しいものを取得します
+SELECT * FROM rates
+WHERE source=$1 AND target=$2 AND valid_from <= now() AND (valid_until IS NULL OR valid_until > now())
+ORDER BY valid_from DESC, id DESC
+LIMIT 1;
+
+-- name: InsertQuote :one
+INSERT INTO quotes (
+  account, rate_id, source, target, rate, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, now() + sqlc.arg(ttl)::integer * interval '1 second'
+) RETURNING *;
+
+-- name: LockQuote :one
+SELECT *, expires_at <= now() AS expired FROM quotes WHERE id=$1 AND account=$2 LIMIT 1 FOR UPDATE;
+
+-- name: UpdateQuoteTransaction :exec
+UPDATE quotes SET transaction=$2 WHERE id=$1;
+
+-- name: InsertSchedule :one
+-- 最初の回はstarts_atに実行します。starts_atを省略すると直ちに実行します
+-- ends_atが最初の回より後でなければ作らずに行を返しません
+INSERT INTO schedules (
+  account, recipient, currency, amount, memo, frequency, starts_at, ends_at, next_run_at
+) SELECT
+  $1, $2, $3, $4, $5, $6, COALESCE(sqlc.narg(starts_at)::timestamptz, now()), sqlc.narg(ends_at)::timestamptz, COALESCE(sqlc.narg(starts_at)::timestamptz, now())
+WHERE sqlc.narg(ends_at)::timestamptz IS NULL OR sqlc.narg(ends_at)::timestamptz > COALESCE(sqlc.narg(starts_at)::timestamptz, now())
+RETURNING *;
+
+-- name: GetSchedule :one
+SELECT * FROM schedules WHERE id=$1 AND account=$2 LIMIT 1;
+
+-- name: GetSchedules :many
+SELECT * FROM schedules WHERE account=$1 ORDER BY id ASC;
+
+-- name: LockSchedule :one
+SELECT * FROM schedules WHERE id=$1 AND account=$2 LIMIT 1 FOR UPDATE;
+
+-- name: UpdateSchedule :one
+UPDATE schedules SET
+  amount=$2,
+  memo=$3,
+  ends_at=$4,
+  status=$5,
+  next_run_at=$6,
+  updated_at=timezone('utc':: text, now())
+WHERE id=$1
+RETURNING *;
+
+-- name: ClaimDueSchedule :one
+-- 実行時刻を過ぎたactiveなschedulesを1件ロックして取得します
+-- 他のworkerがロックしているものは飛ばすので、複数のworkerが同時に動いても同じ回を重ねて実行しません
+SELECT * FROM schedules
+WHERE status='active' AND next_run_at <= now()
+ORDER BY next_run_at ASC
+LIMIT 1 FOR UPDATE SKIP LOCKED;
+
+-- name: InsertScheduleRun :one
+INSERT INTO schedule_runs (
+  schedule, due_at, status, transaction, error
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetScheduleRuns :many
+-- 新しい回から順にrow_limit件まで取得します
+SELECT * FROM schedule_runs WHERE schedule=$1 ORDER BY due_at DESC LIMIT sqlc.arg(row_limit);
+
+-- name: InsertPaymentRequest :one
+INSERT INTO payment_requests (
+  requester, payer, currency, amount, memo, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, now() + sqlc.arg(ttl)::integer * interval '1 second'
+) RETURNING *;
+
+-- name: GetPaymentRequest :one
+-- requesterとpayerのどちらからも参照できます
+SELECT *, (status='pending' AND expires_at <= now())::boolean AS expired FROM payment_requests
+WHERE id=$1 AND (requester=sqlc.arg(account) OR payer=sqlc.arg(account))
+LIMIT 1;
+
+-- name: GetPaymentRequests :many
+-- directionがincomingならaccountが支払いを求められたもの、outgoingならaccountが求めたもの、省略すると両方です
+-- statusにはexpiredも指定できます
+SELECT *, (status='pending' AND expires_at <= now())::boolean AS expired FROM payment_requests
+WHERE
+  (CASE sqlc.narg(direction)::text
+    WHEN 'incoming' THEN payer=sqlc.arg(account)
+    WHEN 'outgoing' THEN requester=sqlc.arg(account)
+    ELSE payer=sqlc.arg(account) OR requester=sqlc.arg(account)
+  END)
+  AND (sqlc.narg(status)::text IS NULL OR sqlc.narg(status) = CASE
+    WHEN status='pending' AND expires_at <= now() THEN 'expired'
+    ELSE status::text
+  END)
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: LockPaymentRequest :one
+-- 支払いや拒否ができるpayerについてのみロックして取得します
+SELECT *, (status='pending' AND expires_at <= now())::boolean AS expired FROM payment_requests
+WHERE id=$1 AND payer=$2
+LIMIT 1 FOR UPDATE;
+
+-- name: UpdatePaymentRequestStatus :one
+UPDATE payment_requests SET status=$2, transaction=$3, updated_at=timezone('utc':: text, now()) WHERE id=$1 RETURNING *;
+
+-- name: InsertEscrow :one
+INSERT INTO escrows (
+  sender, recipient, currency, amount, memo, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, now() + sqlc.arg(ttl)::integer * interval '1 second'
+) RETURNING *;
+
+-- name: GetEscrow :one
+-- senderとrecipientのどちらからも参照できます
+SELECT * FROM escrows WHERE id=$1 AND (sender=sqlc.arg(account) OR recipient=sqlc.arg(account)) LIMIT 1;
+
+-- name: GetEscrows :many
+SELECT * FROM escrows
+WHERE sender=sqlc.arg(account) OR recipient=sqlc.arg(account)
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: LockEscrow :one
+-- 当事者かどうかは呼び出し側で確かめます
+SELECT *, expires_at <= now() AS expired FROM escrows WHERE id=$1 LIMIT 1 FOR UPDATE;
+
+-- name: ConfirmEscrow :one
+-- accountの側の確認の日時を、まだなければ記録します
+-- 期限切れかどうかと同じく、日時はデータベースの時計で決めます
+UPDATE escrows SET
+  sender_confirmed_at=CASE WHEN sender=sqlc.arg(account) THEN COALESCE(sender_confirmed_at, now()) ELSE sender_confirmed_at END,
+  recipient_confirmed_at=CASE WHEN recipient=sqlc.arg(account) THEN COALESCE(recipient_confirmed_at, now()) ELSE recipient_confirmed_at END,
+  updated_at=timezone('utc':: text, now())
+WHERE id=$1
+RETURNING *;
+
+-- name: UpdateEscrowStatus :one
+UPDATE escrows SET status=$2, updated_at=timezone('utc':: text, now()) WHERE id=$1 RETURNING *;
+
+-- name: GetExpiredEscrows :many
+-- expires_atを過ぎても双方の確認が揃わなかったfundedのescrowsを、古いものからrow_limit件まで取得します
+-- after_expires_at, after_idを指定すると、その位置より後から取得します
+SELECT id, expires_at FROM escrows
+WHERE status='funded' AND expires_at <= now()
+  AND (sqlc.narg(after_expires_at)::timestamptz IS NULL OR (expires_at, id) > (sqlc.narg(after_expires_at), sqlc.narg(after_id)::bigint))
+ORDER BY expires_at ASC, id ASC
+LIMIT sqlc.arg(row_limit);
+
+-- name: CountOpenEscrows :one
+-- accountがsenderかrecipientで、まだ払い出しも払い戻しもされていないescrowsの件数
+SELECT COUNT(*) FROM escrows
+WHERE (sender=sqlc.arg(account) OR recipient=sqlc.arg(account)) AND status IN ('funded', 'disputed');
+
+-- name: InsertEscrowMovement :one
+INSERT INTO escrow_movements (
+  escrow, action
+) VALUES (
+  $1, $2
 ) RETURNING id;
 
--- name: IncrementBalance :exec
-UPDATE balances SET balance = balance + sqlc.arg(amount) WHERE account=$1;
+-- name: GetCreditLimit :one
+SELECT credit_limit FROM credit_limits WHERE account=$1 AND currency=$2 LIMIT 1;
+
+-- name: GetCreditLimits :many
+SELECT * FROM credit_limits WHERE account=$1 ORDER BY currency ASC;
 
--- name: DecrementBalance :exec
-UPDATE balances SET balance = balance - sqlc.arg(amount) WHERE account=$1;
+-- name: UpsertCreditLimit :one
+-- accountのcurrencyの与信枠があれば置き換えます
+INSERT INTO credit_limits (
+  account, currency, credit_limit
+) VALUES (
+  $1, $2, $3
+) ON CONFLICT (account, currency) DO UPDATE SET
+  credit_limit=EXCLUDED.credit_limit,
+  updated_at=timezone('utc':: text, now())
+RETURNING *;
+
+-- name: DeleteCreditLimit :execrows
+DELETE FROM credit_limits WHERE account=$1 AND currency=$2;
+
+-- name: GetLimits :many
+SELECT * FROM limits ORDER BY account ASC NULLS FIRST, operation ASC, currency ASC, kind ASC;
+
+-- name: GetEffectiveLimits :many
+-- accountがoperationでcurrencyを出金する際の制限を、kind毎にaccountのものを既定値より優先して取得します
+SELECT DISTINCT ON (kind) * FROM limits
+WHERE (account=$1 OR account IS NULL) AND operation=$2 AND currency=$3
+ORDER BY kind ASC, account ASC NULLS LAST;
 
+-- name: UpsertLimit :one
+-- accountとoperation, currency, kindの制限があれば置き換えます
+INSERT INTO limits (
+  account, operation, currency, kind, value
+) VALUES (
+  $1, $2, $3, $4, $5
+) ON CONFLICT ((COALESCE(account, 0)), operation, currency, kind) DO UPDATE SET
+  value=EXCLUDED.value,
+  updated_at=timezone('utc':: text, now())
+RETURNING *;
+
+-- name: DeleteLimit :execrows
+DELETE FROM limits WHERE id=$1;
+
+-- name: GetOutgoingUsage :one
+-- accountが直近window_seconds秒の間にoperationでcurrencyを出金した回数と金額の合計
+-- transferにはjournalでの出金とescrowへの預け入れも含めます。手数料や取り消しなどは含めません
+SELECT
+  COUNT(DISTINCT transactions.id) AS count,
+  COALESCE(SUM(-postings.amount), 0)::DECIMAL AS volume
+FROM postings
+JOIN transactions ON postings.transaction=transactions.id
+WHERE postings.account=$1
+  AND postings.amount < 0
+  AND transactions.inserted_at > timezone('utc':: text, now()) - sqlc.arg(window_seconds)::integer * interval '1 second'
+  AND (CASE sqlc.arg(operation)::text
+    WHEN 'transfer' THEN transactions.transfer IS NOT NULL OR transactions.journal IS NOT NULL OR transactions.escrow_movement IS NOT NULL
+    WHEN 'spend' THEN transactions.spend IS NOT NULL
+  END)
+  AND postings.currency=sqlc.arg(currency);
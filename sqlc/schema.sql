@@ -1,29 +1,176 @@
 DROP schema public cascade;
 CREATE schema public;
 
+-- frozenとclosedのaccountsは、送金も入金もできません
+CREATE TYPE account_status AS ENUM ('active', 'frozen', 'closed');
+
 CREATE TABLE accounts (
   id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY KEY,
   inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
   updated_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
-  name text
+  name text,
+  status account_status DEFAULT 'active' NOT NULL,
+  -- 連携先のシステムが付与するid。指定されていれば一意です
+  external_id text UNIQUE,
+  metadata JSONB DEFAULT '{}' NOT NULL
+);
+CREATE INDEX ON accounts USING gin (metadata jsonb_path_ops);
+
+-- accountsのstatusを変更した記録
+CREATE TABLE account_status_changes (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  account BIGINT REFERENCES accounts NOT NULL,
+  previous account_status NOT NULL,
+  status account_status NOT NULL,
+  reason text NOT NULL,
+  inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL
+);
+CREATE INDEX ON account_status_changes (account);
+
+-- 通貨。scaleは金額に許す小数点以下の桁数です
+CREATE TABLE currencies (
+  code text PRIMARY KEY,
+  name text NOT NULL,
+  scale INTEGER NOT NULL CHECK (scale >= 0),
+  inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL
 );
 
+-- 単一の通貨しかなかった頃からの既定の通貨
+INSERT INTO currencies (code, name, scale) VALUES ('G', 'g', 2);
+
+-- memoは利用者が取引の目的を書く自由記述で、referenceは連携先のシステムの請求書などを(reference_type, reference_id)で指します
 CREATE TABLE mints (
   id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
-  amount DECIMAL NOT NULL
+  amount DECIMAL NOT NULL,
+  currency text REFERENCES currencies NOT NULL,
+  memo text,
+  reference_type text,
+  reference_id text,
+  CHECK ((reference_type IS NULL) = (reference_id IS NULL))
 );
 
 CREATE TABLE spends (
   id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
-  amount DECIMAL NOT NULL
+  amount DECIMAL NOT NULL,
+  currency text REFERENCES currencies NOT NULL,
+  memo text,
+  reference_type text,
+  reference_id text,
+  CHECK ((reference_type IS NULL) = (reference_id IS NULL))
 );
 
+-- 送金元と送金先は同じcurrencyで動かします
 CREATE TABLE transfers (
   id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
   amount DECIMAL NOT NULL NOT NULL,
+  recipient BIGINT REFERENCES accounts NOT NULL,
+  currency text REFERENCES currencies NOT NULL,
+  memo text,
+  reference_type text,
+  reference_id text,
+  CHECK ((reference_type IS NULL) = (reference_id IS NULL))
+);
+
+-- sourceの1単位がtargetのrate単位に相当する為替レート
+-- valid_from以降、valid_untilがあればその前まで有効で、有効なものが複数あればvalid_fromの新しいものを使います
+CREATE TABLE rates (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  source text REFERENCES currencies NOT NULL,
+  target text REFERENCES currencies NOT NULL,
+  rate DECIMAL NOT NULL CHECK (rate > 0),
+  valid_from TIMESTAMP WITH TIME zone NOT NULL,
+  valid_until TIMESTAMP WITH TIME zone,
+  inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  CHECK (source <> target),
+  CHECK (valid_until IS NULL OR valid_until > valid_from)
+);
+CREATE INDEX ON rates (source, target, valid_from);
+
+-- sourceのamountを、rateで換算したtargetのconverted_amountとしてrecipientに入金する取引
+-- 使ったレートはrateに写しておくので、後からratesが追加されても変わりません
+CREATE TABLE conversions (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  amount DECIMAL NOT NULL,
+  source text REFERENCES currencies NOT NULL,
+  converted_amount DECIMAL NOT NULL,
+  target text REFERENCES currencies NOT NULL,
+  rate DECIMAL NOT NULL,
+  rate_id BIGINT REFERENCES rates NOT NULL,
   recipient BIGINT REFERENCES accounts NOT NULL
 );
 
+-- 複数のaccountsの間で、currency毎に合計が0になる金額をまとめて動かす取引
+-- 各accountの増減はjournal_legsに記録します
+CREATE TABLE journals (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  memo text
+);
+
+-- journalの1行。amountが正なら入金、負なら出金です
+CREATE TABLE journal_legs (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  journal BIGINT REFERENCES journals NOT NULL,
+  account BIGINT REFERENCES accounts NOT NULL,
+  currency text REFERENCES currencies NOT NULL,
+  amount DECIMAL NOT NULL CHECK (amount <> 0)
+);
+CREATE INDEX ON journal_legs (journal);
+
+-- 手数料を課す取引の種類
+CREATE TYPE fee_operation AS ENUM ('transfer', 'spend');
+
+-- operationとcurrency毎の手数料の定め
+-- 手数料はflat + 金額 * percentage / 100を、min以上max以下に収めたもので、accountに入金されます
+CREATE TABLE fee_schedules (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  operation fee_operation NOT NULL,
+  currency text REFERENCES currencies NOT NULL,
+  flat DECIMAL DEFAULT 0 NOT NULL CHECK (flat >= 0),
+  percentage DECIMAL DEFAULT 0 NOT NULL CHECK (percentage >= 0 AND percentage <= 100),
+  min DECIMAL CHECK (min >= 0),
+  max DECIMAL CHECK (max >= 0),
+  account BIGINT REFERENCES accounts NOT NULL,
+  inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  updated_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  UNIQUE (operation, currency),
+  CHECK (min IS NULL OR max IS NULL OR min <= max)
+);
+
+-- fundedはescrowが預かっている状態、disputedは当事者が異議を申し立て、管理者による解決を待っている状態です
+CREATE TYPE escrow_status AS ENUM ('funded', 'disputed', 'released', 'refunded');
+
+-- senderがrecipientとの取引のためにシステム勘定のescrowへ預けた資金
+-- 双方が確認するとrecipientへ払い出し、異議の解決やexpires_atを過ぎるとsenderへ払い戻します
+CREATE TABLE escrows (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  sender BIGINT REFERENCES accounts NOT NULL,
+  recipient BIGINT REFERENCES accounts NOT NULL,
+  currency text REFERENCES currencies NOT NULL,
+  amount DECIMAL NOT NULL CHECK (amount > 0),
+  memo text,
+  status escrow_status DEFAULT 'funded' NOT NULL,
+  sender_confirmed_at TIMESTAMP WITH TIME zone,
+  recipient_confirmed_at TIMESTAMP WITH TIME zone,
+  expires_at TIMESTAMP WITH TIME zone NOT NULL,
+  inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  updated_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  CHECK (sender <> recipient)
+);
+CREATE INDEX ON escrows (sender);
+CREATE INDEX ON escrows (recipient);
+CREATE INDEX ON escrows (expires_at) WHERE status = 'funded';
+
+-- depositはsenderからescrowへの預け入れ、releaseはrecipientへの払い出し、refundはsenderへの払い戻しです
+CREATE TYPE escrow_action AS ENUM ('deposit', 'release', 'refund');
+
+-- escrowsの資金の動き。金額とcurrencyはescrowsと同じです
+CREATE TABLE escrow_movements (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  escrow BIGINT REFERENCES escrows NOT NULL,
+  action escrow_action NOT NULL,
+  UNIQUE (escrow, action)
+);
+
 CREATE TABLE transactions (
   id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
   account BIGINT REFERENCES accounts NOT NULL,
@@ -32,12 +179,221 @@ CREATE TABLE transactions (
   mint BIGINT REFERENCES mints UNIQUE,
   spend BIGINT REFERENCES spends UNIQUE,
   transfer BIGINT REFERENCES transfers UNIQUE,
-  CONSTRAINT kind CHECK(num_nonnulls(mint, transfer, spend) = 1)
+  reversal BIGINT UNIQUE,
+  conversion BIGINT REFERENCES conversions UNIQUE,
+  journal BIGINT REFERENCES journals UNIQUE,
+  fee BIGINT UNIQUE,
+  escrow_movement BIGINT REFERENCES escrow_movements UNIQUE,
+  CONSTRAINT kind CHECK(num_nonnulls(mint, transfer, spend, reversal, conversion, journal, fee, escrow_movement) = 1),
+  -- 連携先のシステムが付与する注文idやタグなど、文字列の値をもつオブジェクトです
+  metadata JSONB DEFAULT '{}' NOT NULL
 );
 CREATE INDEX ON transactions (account);
-CREATE INDEX ON transactions (inserted_at);
+CREATE INDEX ON transactions USING gin (metadata jsonb_path_ops);
+CREATE INDEX ON transactions (inserted_at, id);
 
+-- originalのtransactionを取り消す取引。amountはoriginalの金額以下で、部分的な払い戻しにも使います
+-- currencyはoriginalと同じです
+CREATE TABLE reversals (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  amount DECIMAL NOT NULL,
+  original BIGINT REFERENCES transactions NOT NULL,
+  currency text REFERENCES currencies NOT NULL
+);
+CREATE INDEX ON reversals (original);
+ALTER TABLE transactions ADD FOREIGN KEY (reversal) REFERENCES reversals;
+
+-- originalのtransferやspendに対して、fee_schedulesに従って課した手数料
+-- originalと同じaccountからrecipientへ、originalとは別のtransactionとして支払われます
+CREATE TABLE fees (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  amount DECIMAL NOT NULL,
+  currency text REFERENCES currencies NOT NULL,
+  original BIGINT REFERENCES transactions NOT NULL,
+  recipient BIGINT REFERENCES accounts NOT NULL,
+  schedule BIGINT REFERENCES fee_schedules
+);
+CREATE INDEX ON fees (original);
+ALTER TABLE transactions ADD FOREIGN KEY (fee) REFERENCES fees;
+
+-- accountが初めてcurrencyを受け取った際に作られます
 CREATE TABLE balances (
-  account BIGINT REFERENCES accounts PRIMARY KEY NOT NULL,
-  balance DECIMAL NOT NULL
+  account BIGINT REFERENCES accounts NOT NULL,
+  balance DECIMAL NOT NULL,
+  currency text REFERENCES currencies NOT NULL,
+  PRIMARY KEY (account, currency)
+);
+
+-- accountのcurrencyの残高を、-credit_limitまで負にすることを認める与信枠
+-- 定めのないaccountとcurrencyの与信枠は0で、残高は負になりません
+CREATE TABLE credit_limits (
+  account BIGINT REFERENCES accounts NOT NULL,
+  currency text REFERENCES currencies NOT NULL,
+  credit_limit DECIMAL NOT NULL CHECK (credit_limit >= 0),
+  inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  updated_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  PRIMARY KEY (account, currency)
+);
+
+-- 複式簿記の仕訳。amountは正なら入金、負なら出金で、transactionごとの合計はcurrency毎に必ず0になります
+CREATE TABLE postings (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  transaction BIGINT REFERENCES transactions NOT NULL,
+  account BIGINT REFERENCES accounts NOT NULL,
+  amount DECIMAL NOT NULL,
+  currency text REFERENCES currencies NOT NULL,
+  -- この仕訳を反映した直後のbalancesの残高。balancesを持たないシステム勘定ではNULLです
+  balance_after DECIMAL
+);
+CREATE INDEX ON postings (transaction);
+CREATE INDEX ON postings (account, transaction);
+
+CREATE FUNCTION check_postings_balanced() RETURNS trigger AS $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM postings WHERE transaction = NEW.transaction GROUP BY currency HAVING SUM(amount) <> 0) THEN
+    RAISE EXCEPTION 'postings for transaction % are not balanced', NEW.transaction;
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- コミット時に検査するので、同じトランザクション内で貸方と借方を順に書き込めます
+CREATE CONSTRAINT TRIGGER postings_balanced AFTER INSERT ON postings
+  DEFERRABLE INITIALLY DEFERRED
+  FOR EACH ROW EXECUTE FUNCTION check_postings_balanced();
+
+-- Mintの発行元とSpendの行き先、換算の相手、escrowsの資金を預かるシステム勘定
+-- 利用者のaccountsと重ならないよう負のidを使い、残高はpostingsから導出するのでbalancesは持ちません
+INSERT INTO accounts (id, name) VALUES (-1, 'issuance'), (-2, 'sink'), (-3, 'exchange'), (-4, 'escrow');
+
+CREATE TABLE idempotency_keys (
+  account BIGINT REFERENCES accounts NOT NULL,
+  key text NOT NULL,
+  request_hash text NOT NULL,
+  transaction BIGINT REFERENCES transactions NOT NULL,
+  inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  PRIMARY KEY (account, key)
+);
+
+-- 残高の突き合わせで修正を行った際の記録
+CREATE TABLE reconciliations (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  mismatches JSONB NOT NULL
+);
+
+CREATE TYPE hold_status AS ENUM ('active', 'captured', 'released');
+
+-- 残高を動かさずに、利用可能な残高だけを減らしておく仮押さえ
+-- expires_atを過ぎたactiveなholdは期限切れとして扱い、利用可能な残高から差し引きません
+CREATE TABLE holds (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  account BIGINT REFERENCES accounts NOT NULL,
+  amount DECIMAL NOT NULL CHECK (amount > 0),
+  status hold_status DEFAULT 'active' NOT NULL,
+  expires_at TIMESTAMP WITH TIME zone NOT NULL,
+  inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  updated_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  transaction BIGINT REFERENCES transactions,
+  currency text REFERENCES currencies NOT NULL
+);
+CREATE INDEX ON holds (account, currency) WHERE status = 'active';
+
+-- accountのためにratesの値をexpires_atまで固定した見積もり
+-- 一度のconversionにだけ使え、使ったconversionのtransactionを記録します
+CREATE TABLE quotes (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  account BIGINT REFERENCES accounts NOT NULL,
+  rate_id BIGINT REFERENCES rates NOT NULL,
+  source text REFERENCES currencies NOT NULL,
+  target text REFERENCES currencies NOT NULL,
+  rate DECIMAL NOT NULL,
+  expires_at TIMESTAMP WITH TIME zone NOT NULL,
+  inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  transaction BIGINT REFERENCES transactions
+);
+
+-- 予約送金を繰り返す単位。onceは一度だけ実行します
+CREATE TYPE schedule_frequency AS ENUM ('once', 'daily', 'weekly', 'monthly');
+
+-- completedは繰り返しを終えたもの、cancelledは利用者が取り消したもので、どちらも再開できません
+CREATE TYPE schedule_status AS ENUM ('active', 'paused', 'completed', 'cancelled');
+
+-- accountからrecipientへ、starts_atを起点にfrequency毎に行う予約送金
+-- next_run_atを過ぎたactiveなschedulesをworkerが実行し、ends_atを過ぎればcompletedになります
+CREATE TABLE schedules (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  account BIGINT REFERENCES accounts NOT NULL,
+  recipient BIGINT REFERENCES accounts NOT NULL,
+  currency text REFERENCES currencies NOT NULL,
+  amount DECIMAL NOT NULL CHECK (amount > 0),
+  memo text,
+  frequency schedule_frequency NOT NULL,
+  status schedule_status DEFAULT 'active' NOT NULL,
+  starts_at TIMESTAMP WITH TIME zone NOT NULL,
+  ends_at TIMESTAMP WITH TIME zone,
+  next_run_at TIMESTAMP WITH TIME zone NOT NULL,
+  inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  updated_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  CHECK (account <> recipient)
+);
+CREATE INDEX ON schedules (next_run_at) WHERE status = 'active';
+CREATE INDEX ON schedules (account);
+
+CREATE TYPE schedule_run_status AS ENUM ('succeeded', 'failed');
+
+-- schedulesの各回の実行結果
+-- 同じ回を二度記録しないよう、(schedule, due_at)で一意です。失敗した回はerrorに理由を残します
+CREATE TABLE schedule_runs (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  schedule BIGINT REFERENCES schedules NOT NULL,
+  due_at TIMESTAMP WITH TIME zone NOT NULL,
+  status schedule_run_status NOT NULL,
+  transaction BIGINT REFERENCES transactions,
+  error text,
+  inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  UNIQUE (schedule, due_at)
+);
+
+-- expires_atを過ぎたpendingのpayment_requestsは期限切れとして扱い、支払えません
+CREATE TYPE payment_request_status AS ENUM ('pending', 'paid', 'declined');
+
+-- requesterがpayerに支払いを求める請求
+-- payerが受け入れるとpayerからrequesterへのtransferを行い、そのtransactionを記録します
+CREATE TABLE payment_requests (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  requester BIGINT REFERENCES accounts NOT NULL,
+  payer BIGINT REFERENCES accounts NOT NULL,
+  currency text REFERENCES currencies NOT NULL,
+  amount DECIMAL NOT NULL CHECK (amount > 0),
+  memo text,
+  status payment_request_status DEFAULT 'pending' NOT NULL,
+  expires_at TIMESTAMP WITH TIME zone NOT NULL,
+  transaction BIGINT REFERENCES transactions,
+  inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  updated_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  CHECK (requester <> payer)
+);
+CREATE INDEX ON payment_requests (requester);
+CREATE INDEX ON payment_requests (payer);
+
+-- 出金の制限をかける取引の種類
+CREATE TYPE limit_operation AS ENUM ('transfer', 'spend');
+
+-- max_amountは1回の金額、daily_volumeは直近24時間の金額の合計、hourly_countは直近1時間の回数の上限です
+CREATE TYPE limit_kind AS ENUM ('max_amount', 'daily_volume', 'hourly_count');
+
+-- accountがoperationでcurrencyを出金する際の制限
+-- accountがNULLのものは全てのaccountsに対する既定値で、同じoperation, currency, kindのaccount毎の制限があればそちらを優先します
+CREATE TABLE limits (
+  id BIGINT generated BY DEFAULT AS IDENTITY PRIMARY key,
+  account BIGINT REFERENCES accounts,
+  operation limit_operation NOT NULL,
+  currency text REFERENCES currencies NOT NULL,
+  kind limit_kind NOT NULL,
+  value DECIMAL NOT NULL CHECK (value > 0),
+  inserted_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL,
+  updated_at TIMESTAMP WITH TIME zone DEFAULT timezone('utc':: text, now()) NOT NULL
 );
+-- 利用者のaccountsのidは正なので、既定値は0として一意にします
+CREATE UNIQUE INDEX limits_scope ON limits ((COALESCE(account, 0)), operation, currency, kind);
@@ -0,0 +1,70 @@
+package transactions
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rail44/g/accounts"
+)
+
+// 各RouteがErrorをreturnした場合のハンドラ
+// エラーはaccountsのModelから返ってくるので、変換はaccountsと共通です
+var ServerOptions = StrictHTTPServerOptions{
+	RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	},
+	ResponseErrorHandlerFunc: accounts.ErrorHandler,
+}
+
+// 特定のaccountに属さない、複数のaccountsにまたがる取引のコントローラ
+func NewController(model *accounts.Model) http.Handler {
+	controller := Controller{model: model}
+	return Handler(NewStrictHandlerWithOptions(controller, nil, ServerOptions))
+}
+
+type Controller struct {
+	model *accounts.Model
+}
+
+// POST /
+func (controller Controller) CreateTransaction(ctx context.Context, req CreateTransactionRequestObject) (CreateTransactionResponseObject, error) {
+	legs := make([]accounts.Leg, 0, len(req.Body.Legs))
+	for _, leg := range req.Body.Legs {
+		currency := accounts.DefaultCurrency
+		if leg.Currency != nil {
+			currency = *leg.Currency
+		}
+
+		legs = append(legs, accounts.Leg{
+			Account:  leg.Account,
+			Currency: currency,
+			Amount:   leg.Amount,
+		})
+	}
+
+	// transactionは最初に出金するaccountが行ったものとして記録されるので、idempotencyもそのaccountについて扱います
+	initiator, err := accounts.JournalInitiator(legs)
+	if err != nil {
+		return nil, err
+	}
+
+	idempotency, err := accounts.NewIdempotency(req.Params.IdempotencyKey, "journal", initiator, req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata accounts.Metadata
+	if req.Body.Metadata != nil {
+		metadata = accounts.Metadata(*req.Body.Metadata)
+	}
+
+	txId, err := controller.model.PostJournal(ctx, legs, req.Body.Memo, metadata, idempotency)
+	if err != nil {
+		return nil, err
+	}
+
+	res := CreateTransaction200JSONResponse{
+		TransactionId: txId,
+	}
+	return res, nil
+}